@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+)
+
+// Configuration file and profile selection
+var (
+	configFile  string
+	profileName string
+
+	loadedFile    *config.File      // Configuration file in use, nil when there is none
+	activeProfile string            // Name of the applied profile
+	sources       map[string]string // Where each setting came from, by config file key
+
+	// Credentials wait until the provider, and with it the environment variables, is known
+	keysProfile    *config.Profile
+	keysSource     string
+	baseURLProfile *config.Profile
+	baseURLSource  string
+)
+
+// configCmd groups the commands that inspect the configuration
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration file and profiles",
+}
+
+// configShowCmd prints the effective configuration with the source of every value
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration and where each value comes from",
+	Long: `Print the configuration a translation would use after merging flags,
+environment variables, the selected profile, the config file and the defaults,
+together with the source of every value. API keys are masked.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		printEffectiveConfig(cmd)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file (default: "+config.DefaultFilePath()+" when it exists)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Named profile from the config file")
+
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// applyConfigFile applies the config file and the selected profile to every
+// setting that was not given as a flag
+func applyConfigFile(cmd *cobra.Command) error {
+	sources = map[string]string{}
+
+	path := configFile
+	if path == "" {
+		path = config.DefaultFilePath()
+		if _, err := os.Stat(path); path == "" || err != nil {
+			if profileName != "" {
+				return errors.NewConfigurationError("--profile needs a config file", nil).WithContext("file_path", path)
+			}
+			return nil
+		}
+	}
+
+	file, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	loadedFile = file
+	applyProfile(cmd, &file.Base, "config file")
+
+	activeProfile = profileName
+	if activeProfile == "" {
+		activeProfile = file.Profile
+	}
+	if activeProfile != "" {
+		profile, errLookup := file.Lookup(activeProfile)
+		if errLookup != nil {
+			return errLookup
+		}
+		applyProfile(cmd, profile, "profile "+activeProfile)
+	}
+	return nil
+}
+
+// applyProfile copies the settings of a profile that no flag overrides
+func applyProfile(cmd *cobra.Command, profile *config.Profile, source string) {
+	flags := cmd.Flags()
+	setString := func(key, flag string, value *string, target *string) {
+		if value != nil && !flags.Changed(flag) {
+			*target = *value
+			sources[key] = source
+		}
+	}
+	setFloat := func(key, flag string, value *float32, target **float32) {
+		if value != nil && !flags.Changed(flag) {
+			number := *value
+			*target = &number
+			sources[key] = source
+		}
+	}
+
+	setString("provider", "provider", profile.Provider, &cfg.Provider)
+	setString("model", "model", profile.Model, &cfg.ModelName)
+	setString("source_language", "source-language", profile.SourceLanguage, &cfg.SourceLanguage)
+	setString("target_language", "target-language", profile.TargetLanguage, &cfg.TargetLanguage)
+	setString("description", "description", profile.Description, &cfg.Description)
+	setString("output_template", "output-template", profile.OutputTemplate, &cfg.OutputTemplate)
+	setString("output_dir", "output-dir", profile.OutputDir, &cfg.OutputDir)
+	setString("thinking_level", "thinking-level", profile.ThinkingLevel, &cfg.ThinkingLevel)
+	setString("prompt_template", "prompt-template", profile.PromptTemplate, &cfg.PromptTemplate)
+	setString("rules_dir", "rules-dir", profile.RulesDir, &cfg.RulesDir)
+	setString("style_guide", "style-guide", profile.StyleGuide, &cfg.StyleGuide)
+	setFloat("temperature", "temperature", profile.Temperature, &cfg.Temperature)
+	setFloat("top_p", "top-p", profile.TopP, &cfg.TopP)
+	setFloat("top_k", "top-k", profile.TopK, &cfg.TopK)
+
+	if profile.BatchSize != nil && !flags.Changed("batch-size") {
+		cfg.BatchSize = *profile.BatchSize
+		sources["batch_size"] = source
+	}
+	if profile.Glossary != nil && !flags.Changed("glossary") {
+		cfg.Glossary = profile.Glossary
+		sources["glossary"] = source
+	}
+
+	if profile.BaseURL != nil {
+		baseURLProfile, baseURLSource = profile, source
+	}
+	if profile.APIKeyEnv != nil || profile.APIKeyFile != nil {
+		keysProfile, keysSource = profile, source
+	}
+}
+
+// applyConfigCredentials sets the base URL and API keys of the profile once the
+// provider is known. The provider environment variables take precedence.
+func applyConfigCredentials(cmd *cobra.Command) error {
+	apiKeysVar, baseURLVar := config.ProviderEnvVars(cfg.Provider)
+
+	switch {
+	case cmd.Flags().Changed("base-url"):
+	case os.Getenv(baseURLVar) != "":
+		sources["base_url"] = "env " + baseURLVar
+	case baseURLProfile != nil:
+		cfg.BaseURL = *baseURLProfile.BaseURL
+		sources["base_url"] = baseURLSource
+	}
+
+	switch {
+	case cmd.Flags().Changed("api-key"):
+	case os.Getenv(apiKeysVar) != "":
+		sources["api_keys"] = "env " + apiKeysVar
+	case keysProfile != nil:
+		keys, err := keysProfile.APIKeys()
+		if err != nil {
+			return err
+		}
+		cfg.APIKeys = keys
+		sources["api_keys"] = keysSource
+	case len(cfg.APIKeys) > 0:
+		// Loaded before the provider was known
+		sources["api_keys"] = "env GEMINI_API_KEY"
+	}
+	return nil
+}
+
+// printEffectiveConfig prints every setting that a profile can set with its source
+func printEffectiveConfig(cmd *cobra.Command) {
+	if loadedFile != nil {
+		fmt.Printf("Config file: %s\n", loadedFile.Path)
+		if names := loadedFile.ProfileNames(); len(names) > 0 {
+			fmt.Printf("Profiles:    %s\n", strings.Join(names, ", "))
+		}
+	} else {
+		fmt.Printf("Config file: none (%s does not exist)\n", config.DefaultFilePath())
+	}
+	if activeProfile != "" {
+		fmt.Printf("Profile:     %s\n", activeProfile)
+	}
+	fmt.Println()
+
+	optional := func(value *float32) string {
+		if value == nil {
+			return "(model default)"
+		}
+		return strconv.FormatFloat(float64(*value), 'g', -1, 32)
+	}
+	sourceLanguage := cfg.SourceLanguage
+	if sourceLanguage == "" {
+		sourceLanguage = "auto (detected from the subtitles)"
+	}
+	settings := []struct {
+		key   string
+		flag  string
+		value string
+	}{
+		{"provider", "provider", cfg.Provider},
+		{"model", "model", cfg.ModelName},
+		{"base_url", "base-url", cfg.BaseURL},
+		{"api_keys", "api-key", maskAPIKeys(cfg.APIKeys)},
+		{"source_language", "source-language", sourceLanguage},
+		{"target_language", "target-language", cfg.TargetLanguage},
+		{"description", "description", cfg.Description},
+		{"output_template", "output-template", cfg.OutputTemplate},
+		{"output_dir", "output-dir", cfg.OutputDir},
+		{"glossary", "glossary", strings.Join(cfg.Glossary, "; ")},
+		{"prompt_template", "prompt-template", cfg.PromptTemplate},
+		{"rules_dir", "rules-dir", cfg.RulesDir},
+		{"style_guide", "style-guide", cfg.StyleGuide},
+		{"batch_size", "batch-size", strconv.Itoa(cfg.BatchSize)},
+		{"thinking_level", "thinking-level", cfg.ThinkingLevel},
+		{"temperature", "temperature", optional(cfg.Temperature)},
+		{"top_p", "top-p", optional(cfg.TopP)},
+		{"top_k", "top-k", optional(cfg.TopK)},
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "SETTING\tVALUE\tSOURCE")
+	for _, setting := range settings {
+		source := "default"
+		if cmd.Flags().Changed(setting.flag) {
+			source = "flag --" + setting.flag
+		} else if fromConfig, ok := sources[setting.key]; ok {
+			source = fromConfig
+		}
+		value := setting.value
+		if value == "" {
+			value = "(not set)"
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\n", setting.key, value, source)
+	}
+	_ = writer.Flush()
+}
+
+// maskAPIKeys shows how many keys are configured and their last characters
+func maskAPIKeys(keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	masked := make([]string, len(keys))
+	for i, key := range keys {
+		if len(key) > 8 {
+			masked[i] = "..." + key[len(key)-4:]
+		} else {
+			masked[i] = "***"
+		}
+	}
+	return fmt.Sprintf("%d key(s) %s", len(keys), strings.Join(masked, ", "))
+}
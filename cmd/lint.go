@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/translator"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+var lintFix bool
+
+// lintCmd reports the problems of SRT files with line numbers
+var lintCmd = &cobra.Command{
+	Use:   "lint [--fix] <SRT_FILE>...",
+	Short: "Check SRT files for defects the parser has to repair or skip",
+	Long: `Print every problem the parser finds in SRT files as FILE:LINE: SEVERITY: MESSAGE.
+Warnings were repaired (missing cue numbers, '.' in timestamps, blank lines in
+the text, UTF-16 or Windows-1252 encodings); errors mean lines were dropped.
+--fix rewrites each file with the repaired cues, numbered from 1, in
+--output-encoding (UTF-8 by default); files with errors are left untouched.
+The command fails when a problem is found, or with --fix when lines had to
+be dropped.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		logger.SetColorMode(cfg.UseColors)
+		logger.SetQuietMode(cfg.QuietMode)
+
+		problems, dropped, failed := 0, 0, 0
+		for _, file := range args {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return errors.NewFileError("failed to read input file", err).WithContext("file_path", file)
+			}
+			subtitles, diagnostics, err := srt.ParseCharset(data, cfg.InputEncoding)
+			if err != nil {
+				return errors.NewValidationError("invalid input encoding", err).WithContext("input_encoding", cfg.InputEncoding)
+			}
+			errorCount := 0
+			for _, diagnostic := range diagnostics {
+				location := file
+				if diagnostic.Line > 0 {
+					location = fmt.Sprintf("%s:%d", file, diagnostic.Line)
+				}
+				fmt.Printf("%s: %s: %s\n", location, diagnostic.Severity, diagnostic.Message)
+				if diagnostic.Severity == srt.SeverityError {
+					errorCount++
+				}
+			}
+			if len(diagnostics) == 0 {
+				continue
+			}
+			problems += len(diagnostics)
+			dropped += errorCount
+			failed++
+
+			// Rewriting a file with dropped lines would lose them for good
+			if lintFix && errorCount > 0 {
+				logger.Warning(fmt.Sprintf("Not rewriting %s, %d problem(s) dropped lines; repair them by hand", file, errorCount))
+				continue
+			}
+			if lintFix && len(subtitles) > 0 {
+				for i := range subtitles {
+					subtitles[i].Index = i + 1
+				}
+				content, errEncode := srt.EncodeText(srt.ComposeSRT(subtitles), cfg.OutputEncoding, cfg.OutputBOM)
+				if errEncode != nil {
+					return errors.NewValidationError("failed to encode the output", errEncode).WithContext("output_encoding", cfg.OutputEncoding)
+				}
+				if err = translator.WriteFileAtomic(file, content); err != nil {
+					return errors.NewFileError("failed to write output file", err).WithContext("file_path", file)
+				}
+				logger.Success(fmt.Sprintf("Rewrote %s with %d cues", file, len(subtitles)))
+			}
+		}
+
+		switch {
+		case problems == 0:
+			logger.Success(fmt.Sprintf("No problems found in %d file(s)", len(args)))
+		case lintFix && dropped == 0:
+			logger.Success(fmt.Sprintf("Repaired %d problem(s) in %d file(s)", problems, failed))
+		case lintFix:
+			return errors.NewValidationError(fmt.Sprintf("%d problem(s) dropped lines that could not be repaired; those files were not rewritten", dropped), nil)
+		default:
+			return errors.NewValidationError(fmt.Sprintf("found %d problem(s) in %d file(s)", problems, failed), nil)
+		}
+		return nil
+	},
+}
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "Rewrite the files with the repaired and renumbered cues")
+
+	rootCmd.AddCommand(lintCmd)
+}
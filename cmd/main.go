@@ -13,6 +13,9 @@ import (
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
+	"github.com/luispater/gemini-srt-translator-go/internal/batch"
+	"github.com/luispater/gemini-srt-translator-go/internal/events"
+	"github.com/luispater/gemini-srt-translator-go/internal/interrupt"
 	"github.com/luispater/gemini-srt-translator-go/internal/logger"
 	"github.com/luispater/gemini-srt-translator-go/internal/translator"
 	"github.com/luispater/gemini-srt-translator-go/pkg/config"
@@ -23,69 +26,151 @@ var cfg *config.Config
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "gst [flags] <SRT_FILE|MKV_FILE>",
+	Use:   "gst [flags] <SRT_FILE|MKV_FILE|DIR|GLOB>...",
 	Short: "Translate SRT subtitle files or extract and translate subtitles from MKV files using AI",
 	Long: `Gemini SRT Translator is a powerful tool to translate subtitle files using AI providers (Gemini, OpenAI).
 Supports both SRT files and MKV files with embedded subtitles.
+Several files, directories (searched recursively) and glob patterns can be given to translate a whole season.
 Perfect for anyone needing fast, accurate, and customizable translations for videos, movies, and series.`,
 	SilenceUsage:  true, // Don't show usage on errors
 	SilenceErrors: true, // Don't show errors automatically (we handle them in main)
+	// Input files are positional arguments, not subcommand names
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if no arguments provided, show help
 		if len(args) == 0 {
 			return cmd.Help()
 		}
-		// Set input file from positional argument
-		cfg.InputFile = args[0]
-		return runTranslate(cmd, args)
+		batchOptions.Roots, batchOptions.Config = args, cfg
+		inputFiles, err := batch.CollectInputs(args, batchOptions)
+		if err != nil {
+			return err
+		}
+		if len(inputFiles) == 0 {
+			return errors.NewValidationError("no .srt or .mkv files found", nil).WithContext("inputs", strings.Join(args, ", "))
+		}
+
+		// A single explicit file keeps the classic one-shot behaviour
+		if len(inputFiles) == 1 && !batchOptions.SkipExisting {
+			cfg.InputFile = inputFiles[0]
+			return runTranslate(cmd, args)
+		}
+		if cfg.OutputFile != "" {
+			return errors.NewValidationError("--output-file cannot be used with multiple input files", nil)
+		}
+		return runBatch(inputFiles)
 	},
 }
 
+var batchOptions batch.Options
+
+// Machine-readable progress output
+var (
+	progressFormat string
+	progressFD     int
+)
+
 func init() {
 	cfg = config.NewConfig()
 
 	// Root command flags (removed input-file flag)
-	rootCmd.Flags().StringVarP(&cfg.TargetLanguage, "target-language", "l", "Simplified Chinese", "Target language for translation")
-	rootCmd.Flags().StringVarP(&cfg.Provider, "provider", "p", "gemini", "AI provider (gemini, openai)")
-	rootCmd.Flags().StringVarP(&cfg.BaseURL, "base-url", "", "", "API Base URL (auto-detected based on provider)")
+	rootCmd.PersistentFlags().StringVarP(&cfg.TargetLanguage, "target-language", "l", "Simplified Chinese", "Target language for translation")
+	rootCmd.PersistentFlags().StringVarP(&cfg.Provider, "provider", "p", "gemini", "AI provider (gemini, openai, replay)")
+	rootCmd.PersistentFlags().StringVarP(&cfg.BaseURL, "base-url", "", "", "API Base URL (auto-detected based on provider)")
 
 	// Custom handling for comma-separated API keys
 	var apiKeysStr string
-	rootCmd.Flags().StringVarP(&apiKeysStr, "api-key", "k", "", "API key(s) - comma-separated for multiple keys (auto-detected based on provider)")
+	rootCmd.PersistentFlags().StringVarP(&apiKeysStr, "api-key", "k", "", "API key(s) - comma-separated for multiple keys (auto-detected based on provider)")
 	rootCmd.Flags().StringVarP(&cfg.OutputFile, "output-file", "o", "", "Output file path")
+	rootCmd.PersistentFlags().StringVar(&cfg.OutputTemplate, "output-template", "", "Output path template, e.g. \"{dir}/{name}.{lang_bcp47}{.forced}{.ai}.{ext}\" (default: {dir}/{name}.{lang}.{ext})")
+	rootCmd.PersistentFlags().StringVar(&cfg.OutputDir, "output-dir", "", "Write outputs to this directory, mirroring the input directories in batch mode")
+	rootCmd.PersistentFlags().StringVar(&cfg.InputEncoding, "input-encoding", "auto", "Charset of the input subtitles, e.g. gbk, big5, shift_jis, windows-1251, utf-16le, or auto to detect it")
+	rootCmd.PersistentFlags().StringVar(&cfg.OutputEncoding, "output-encoding", "utf-8", "Charset of the output file, e.g. utf-8, utf-16le, gbk, windows-1256")
+	rootCmd.PersistentFlags().BoolVar(&cfg.OutputBOM, "output-bom", false, "Start the output file with a byte order mark (UTF-8 and UTF-16 only)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.PreserveFormat, "preserve-format", false, "Keep the cue numbers, timestamp text, whitespace, line endings and charset of the input in the output")
+	rootCmd.PersistentFlags().StringSliceVar(&cfg.TrackFlags, "track-flags", nil, "Flags of the translated track for the output template: forced, sdh, default")
 	rootCmd.Flags().IntVarP(&cfg.StartLine, "start-line", "s", 0, "Starting line number")
-	rootCmd.Flags().StringVarP(&cfg.Description, "description", "d", "", "Description for translation context")
-	rootCmd.Flags().StringVarP(&cfg.ModelName, "model", "m", cfg.ModelName, "Model to use (gemini-2.5-pro, gpt-4o, etc.)")
-	rootCmd.Flags().IntVarP(&cfg.BatchSize, "batch-size", "b", cfg.BatchSize, "Batch size for translation")
-	rootCmd.Flags().IntVarP(&cfg.RetryCount, "retry-count", "r", cfg.RetryCount, "Number of retries for failed requests (default: 3)")
+	rootCmd.Flags().StringVar(&cfg.LineSelection, "lines", "", "Re-translate only these lines of an existing output (e.g. 120-180,455)")
+	rootCmd.Flags().StringVar(&cfg.TimeSelection, "time", "", "Re-translate only cues in these time ranges of an existing output (e.g. 00:12:00-00:15:30)")
+	rootCmd.PersistentFlags().StringVarP(&cfg.Description, "description", "d", "", "Description for translation context")
+	rootCmd.PersistentFlags().StringVar(&cfg.SourceLanguage, "source-language", "auto", "Language of the source subtitles, or auto to detect it")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.Glossary, "glossary", nil, "Glossary entry \"term = translation\" the model must follow (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&cfg.PromptTemplate, "prompt-template", "", "Instruction template file (Go text/template) or built-in template name: default, zh")
+	rootCmd.PersistentFlags().StringVar(&cfg.RulesDir, "rules-dir", "", "Directory of per-target-language rule packs, e.g. japanese.md")
+	rootCmd.PersistentFlags().StringVar(&cfg.StyleGuide, "style-guide", "", "File with a style guide added to the instruction")
+	rootCmd.PersistentFlags().StringVarP(&cfg.ModelName, "model", "m", cfg.ModelName, "Model to use (gemini-2.5-pro, gpt-4o, etc.)")
+	rootCmd.PersistentFlags().IntVarP(&cfg.BatchSize, "batch-size", "b", cfg.BatchSize, "Batch size for translation")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.TimingPre, "timing-pre", nil, "Timing step applied to the source before translating, e.g. shift=-1.5s, sync=OLD->NEW;OLD->NEW, fps=23.976:25 (repeatable)")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.TimingPost, "timing-post", nil, "Timing step applied to the translated output, e.g. fix-overlaps, min-gap=84ms (repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.StrictParsing, "strict", false, "Fail on any defect in the source subtitles instead of repairing it (see gst lint)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.StripSDH, "strip-sdh", false, "Remove sound descriptions, speaker labels and music lines for the hearing impaired before translating")
+	rootCmd.PersistentFlags().IntVarP(&cfg.RetryCount, "retry-count", "r", cfg.RetryCount, "Number of retries for failed requests (default: 3)")
+	rootCmd.Flags().BoolVar(&cfg.DryRun, "dry-run", false, "Estimate requests, tokens and cost without translating")
+	rootCmd.PersistentFlags().StringVar(&cfg.PriceTableFile, "price-table", "", "JSON file with per-model prices per million tokens")
 
 	// Model tuning parameters
 	var temperature, topP, topK float32
-	rootCmd.Flags().Float32Var(&temperature, "temperature", 1.0, "Temperature (0.0-2.0)")
-	rootCmd.Flags().Float32Var(&topP, "top-p", 0.95, "Top P (0.0-1.0)")
-	rootCmd.Flags().Float32Var(&topK, "top-k", 0, "Top K (>=0)")
-	rootCmd.Flags().StringVar(&cfg.ThinkingLevel, "thinking-level", cfg.ThinkingLevel, "Thinking level (minimal, low, medium, high)")
+	rootCmd.PersistentFlags().Float32Var(&temperature, "temperature", 1.0, "Temperature (0.0-2.0)")
+	rootCmd.PersistentFlags().Float32Var(&topP, "top-p", 0.95, "Top P (0.0-1.0)")
+	rootCmd.PersistentFlags().Float32Var(&topK, "top-k", 0, "Top K (>=0)")
+	rootCmd.PersistentFlags().StringVar(&cfg.ThinkingLevel, "thinking-level", cfg.ThinkingLevel, "Thinking level (minimal, low, medium, high)")
 
 	// Boolean flags
-	var noStreaming, noThinking, noColors, progressLog, quiet bool
+	var noStreaming, noThinking, noColors, progressLog, quiet, noDialogueSegments bool
 	var paidQuota, interactive, resume, noResume bool
 
-	rootCmd.Flags().BoolVar(&noStreaming, "no-streaming", false, "Disable streaming")
-	rootCmd.Flags().BoolVar(&noThinking, "no-thinking", false, "Disable thinking mode")
-	rootCmd.Flags().BoolVar(&noColors, "no-colors", false, "Disable colored output")
-	rootCmd.Flags().BoolVar(&progressLog, "progress-log", false, "Enable progress logging")
-	rootCmd.Flags().BoolVar(&quiet, "quiet", false, "Suppress output")
-	rootCmd.Flags().BoolVar(&resume, "resume", false, "Resume interrupted translation")
-	rootCmd.Flags().BoolVar(&noResume, "no-resume", false, "Start from beginning")
-	rootCmd.Flags().BoolVar(&paidQuota, "paid-quota", false, "Remove artificial limits for paid quota users")
-	rootCmd.Flags().BoolVar(&interactive, "interactive", false, "Interactive model selection")
-
-	// Set flag processing
-	rootCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+	rootCmd.PersistentFlags().BoolVar(&noStreaming, "no-streaming", false, "Disable streaming")
+	rootCmd.PersistentFlags().BoolVar(&noThinking, "no-thinking", false, "Disable thinking mode")
+	rootCmd.PersistentFlags().BoolVar(&cfg.SkipTargetCues, "skip-target-cues", false, "Keep cues that are clearly in the target language already instead of translating them")
+	rootCmd.PersistentFlags().BoolVar(&noDialogueSegments, "no-dialogue-segments", false, "Send cues with dialogue dashes, speaker labels or SDH annotations as plain text instead of segments")
+	rootCmd.PersistentFlags().StringVar(&cfg.SDHAnnotations, "sdh-annotations", "keep", "SDH annotations such as [door slams] or ♪ lyrics ♪ in segmented cues: keep, drop")
+	rootCmd.PersistentFlags().BoolVar(&noColors, "no-colors", false, "Disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&progressLog, "progress-log", false, "Enable progress logging")
+	rootCmd.PersistentFlags().BoolVar(&cfg.SaveThoughts, "save-thoughts", false, "Save the model thoughts of every batch to <name>.thoughts.log")
+	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Suppress output")
+	rootCmd.PersistentFlags().BoolVar(&resume, "resume", false, "Resume interrupted translation")
+	rootCmd.PersistentFlags().BoolVar(&noResume, "no-resume", false, "Start from beginning")
+	rootCmd.PersistentFlags().BoolVar(&paidQuota, "paid-quota", false, "Remove artificial limits for paid quota users")
+	rootCmd.PersistentFlags().BoolVar(&interactive, "interactive", false, "Interactive model selection")
+
+	// Transcripts
+	rootCmd.PersistentFlags().StringVar(&cfg.RecordDir, "record-dir", "", "Write every request and response to numbered JSON files in this directory")
+	rootCmd.PersistentFlags().StringVar(&cfg.ReplayDir, "replay-dir", "", "Directory of recorded transcripts served by --provider replay")
+	rootCmd.PersistentFlags().BoolVar(&cfg.ReplayStrict, "replay-strict", false, "Fail a batch whose payload matches no recorded transcript instead of replaying the next one")
+
+	// Logging
+	rootCmd.PersistentFlags().StringVar(&cfg.LogLevel, "log-level", "info", "Log level: debug, info, warn, error")
+	rootCmd.PersistentFlags().StringVar(&cfg.LogFile, "log-file", "", "Append all log records to this file while running (API keys are redacted)")
+	rootCmd.PersistentFlags().StringVar(&cfg.LogFormat, "log-format", "text", "Log file format: text or json")
+
+	// Progress output
+	rootCmd.PersistentFlags().StringVar(&progressFormat, "progress-format", "text", "Progress output: text (progress bar) or json (one JSON event per line)")
+	rootCmd.PersistentFlags().IntVar(&progressFD, "progress-fd", 2, "File descriptor JSON progress events are written to (default: stderr)")
+
+	// Non-interactive decisions
+	rootCmd.PersistentFlags().BoolVar(&cfg.NonInteractive, "non-interactive", false, "Never prompt; answer every question from flags (for CI and daemons)")
+	rootCmd.PersistentFlags().StringVar(&cfg.OnExistingOutput, "on-existing-output", "", "What to do when an output or saved progress exists: resume, overwrite, fail (default: ask, or fail with --non-interactive)")
+	rootCmd.PersistentFlags().StringVar(&cfg.OnTokenLimit, "on-token-limit", "", "What to do when a batch exceeds the token limit: shrink, fail (default: ask, or shrink with --non-interactive)")
+	rootCmd.PersistentFlags().IntVar(&cfg.SubtitleTrack, "mkv-track", 0, "MKV subtitle track number to translate (default: ask, or the best track for the source language with --non-interactive)")
+
+	// Batch mode flags
+	rootCmd.PersistentFlags().StringSliceVar(&batchOptions.Include, "include", nil, "Only translate discovered files whose name matches these patterns (e.g. \"*S01E*.srt\")")
+	rootCmd.PersistentFlags().StringSliceVar(&batchOptions.Exclude, "exclude", nil, "Skip discovered files whose name matches these patterns")
+	rootCmd.Flags().BoolVar(&batchOptions.SkipExisting, "skip-existing", false, "Skip files whose translated output already exists")
+	rootCmd.Flags().BoolVar(&batchOptions.FailFast, "fail-fast", false, "Stop a batch at the first failed file instead of continuing")
+
+	// Set flag processing (shared by subcommands such as watch)
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		// Settings from the config file and profile apply below flags
+		if err := applyConfigFile(cmd); err != nil {
+			return err
+		}
+
 		// Auto-detect provider based on model name if not explicitly set
-		if !cmd.Flags().Changed("provider") {
+		if !cmd.Flags().Changed("provider") && sources["provider"] == "" {
 			if strings.Contains(cfg.ModelName, "gpt") {
 				cfg.Provider = "openai"
+				sources["provider"] = "detected from model"
 			} else if strings.Contains(cfg.ModelName, "gemini") {
 				cfg.Provider = "gemini"
 			}
@@ -93,9 +178,12 @@ func init() {
 
 		// Load environment variables based on final provider
 		cfg.LoadEnvironmentForProvider()
+		if err := applyConfigCredentials(cmd); err != nil {
+			return err
+		}
 
 		// Set default model based on provider
-		if !cmd.Flags().Changed("model") {
+		if !cmd.Flags().Changed("model") && sources["model"] == "" {
 			switch cfg.Provider {
 			case "openai":
 				cfg.ModelName = "gpt-4o"
@@ -139,6 +227,22 @@ func init() {
 		if noColors {
 			cfg.UseColors = false
 		}
+		if noDialogueSegments {
+			cfg.DialogueSegments = false
+		}
+		if strings.EqualFold(cfg.SourceLanguage, "auto") {
+			cfg.SourceLanguage = ""
+		}
+		if strings.EqualFold(cfg.InputEncoding, "auto") {
+			cfg.InputEncoding = ""
+		}
+		if cfg.PreserveFormat && !cmd.Flags().Changed("output-encoding") {
+			// The output is written in the charset of the input
+			cfg.OutputEncoding = ""
+		}
+		if err := translator.ValidateOutputTemplate(cfg.OutputTemplate); err != nil {
+			return err
+		}
 		if progressLog {
 			cfg.ProgressLog = true
 		}
@@ -158,6 +262,9 @@ func init() {
 		}
 
 		// Handle interactive model selection
+		if interactive && cfg.NonInteractive {
+			return errors.NewValidationError("--interactive cannot be used with --non-interactive", nil)
+		}
 		if interactive {
 			return selectModelInteractive()
 		}
@@ -167,13 +274,33 @@ func init() {
 
 }
 
-func runTranslate(_ *cobra.Command, _ []string) error {
+// prepareRun sets up logging and asks for settings that are still missing
+func prepareRun() error {
 	// Set logger modes
 	logger.SetColorMode(cfg.UseColors)
 	logger.SetQuietMode(cfg.QuietMode)
+	if err := setupLogging(); err != nil {
+		return err
+	}
+	if err := setupProgressOutput(); err != nil {
+		return err
+	}
+
+	// Replayed responses need no API key
+	needsKey := len(cfg.APIKeys) == 0 && cfg.Provider != "replay"
+
+	if cfg.NonInteractive {
+		if needsKey {
+			return errors.NewConfigurationError(fmt.Sprintf("no API key configured for provider %s (use --api-key or the environment)", cfg.Provider), nil)
+		}
+		if cfg.TargetLanguage == "" {
+			return errors.NewValidationError("please provide a target language", nil)
+		}
+		return nil
+	}
 
 	// Validate required fields based on provider
-	if len(cfg.APIKeys) == 0 {
+	if needsKey {
 		var prompt string
 		switch cfg.Provider {
 		case "openai":
@@ -190,6 +317,58 @@ func runTranslate(_ *cobra.Command, _ []string) error {
 	if cfg.TargetLanguage == "" {
 		cfg.TargetLanguage = strings.TrimSpace(logger.InputPrompt("Enter target language: "))
 	}
+	return nil
+}
+
+// setupLogging applies the log level, opens the log file and registers the API keys for redaction
+func setupLogging() error {
+	if err := logger.SetLevel(cfg.LogLevel); err != nil {
+		return errors.NewValidationError(err.Error(), nil).WithContext("log_level", cfg.LogLevel)
+	}
+	logger.AddSecrets(cfg.APIKeys...)
+	if cfg.LogFile != "" {
+		if err := logger.SetLogFile(cfg.LogFile, cfg.LogFormat); err != nil {
+			return errors.NewFileError("failed to open log file", err).WithContext("file_path", cfg.LogFile)
+		}
+	}
+	return nil
+}
+
+// setupProgressOutput switches from the progress bar to JSON events when requested
+func setupProgressOutput() error {
+	switch progressFormat {
+	case "text":
+		return nil
+	case "json":
+	default:
+		return errors.NewValidationError("progress-format must be text or json", nil).WithContext("progress_format", progressFormat)
+	}
+
+	var output *os.File
+	switch progressFD {
+	case 1:
+		output = os.Stdout
+	case 2:
+		output = os.Stderr
+	default:
+		output = os.NewFile(uintptr(progressFD), "progress")
+		if output == nil {
+			return errors.NewValidationError("invalid progress file descriptor", nil).WithContext("progress_fd", progressFD)
+		}
+		if _, err := output.Stat(); err != nil {
+			return errors.NewValidationError("progress file descriptor is not open", err).WithContext("progress_fd", progressFD)
+		}
+	}
+
+	events.SetOutput(output)
+	logger.SetProgressBars(false)
+	return nil
+}
+
+func runTranslate(_ *cobra.Command, _ []string) error {
+	if err := prepareRun(); err != nil {
+		return err
+	}
 
 	// Validate file paths
 	if cfg.InputFile != "" {
@@ -201,10 +380,84 @@ func runTranslate(_ *cobra.Command, _ []string) error {
 	// Create translator and perform translation
 	t := translator.NewTranslator(cfg)
 
-	ctx := context.Background()
+	ctx, cancel := interrupt.NotifyContext(context.Background())
+	defer cancel()
+	if cfg.DryRun {
+		estimate, err := t.EstimateCost(ctx)
+		if err != nil {
+			return err
+		}
+		printCostEstimates([]*translator.CostEstimate{estimate})
+		return nil
+	}
 	return t.Translate(ctx)
 }
 
+// runBatch translates several files with a shared provider and prints a summary
+func runBatch(inputFiles []string) error {
+	if err := prepareRun(); err != nil {
+		return err
+	}
+
+	runner, err := batch.NewRunner(cfg, batchOptions)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := interrupt.NotifyContext(context.Background())
+	defer cancel()
+	if cfg.DryRun {
+		estimates, errEstimate := runner.EstimateCosts(ctx, inputFiles)
+		if errEstimate != nil {
+			return errEstimate
+		}
+		printCostEstimates(estimates)
+		return nil
+	}
+
+	results := runner.Run(ctx, inputFiles)
+	if failed := batch.PrintSummary(results); failed > 0 {
+		return errors.NewTranslationError(fmt.Sprintf("%d of %d files failed", failed, len(results)), nil)
+	}
+	return nil
+}
+
+// printCostEstimates prints per-file and total dry-run estimates
+func printCostEstimates(estimates []*translator.CostEstimate) {
+	var total translator.CostEstimate
+	total.Priced = true
+
+	logger.Highlight(fmt.Sprintf("Dry run for model %s (no translation requests sent)\n", cfg.ModelName))
+	for _, estimate := range estimates {
+		logger.Info(fmt.Sprintf("%s: %d lines, %d requests, %d input tokens, ~%d output tokens, %s",
+			estimate.InputFile, estimate.Lines, estimate.Requests, estimate.InputTokens, estimate.OutputTokens, formatCost(estimate)))
+
+		total.Lines += estimate.Lines
+		total.Requests += estimate.Requests
+		total.InputTokens += estimate.InputTokens
+		total.OutputTokens += estimate.OutputTokens
+		total.Cost += estimate.Cost
+		total.Priced = total.Priced && estimate.Priced
+		if total.PricedAs == "" {
+			total.PricedAs = estimate.PricedAs
+		}
+	}
+
+	logger.Success(fmt.Sprintf("Total: %d files, %d lines, %d requests, %d input tokens, ~%d output tokens, %s",
+		len(estimates), total.Lines, total.Requests, total.InputTokens, total.OutputTokens, formatCost(&total)))
+}
+
+// formatCost renders an estimated cost, or explains why it is unknown
+func formatCost(estimate *translator.CostEstimate) string {
+	if !estimate.Priced {
+		return "cost unknown (model not in price table, see --price-table)"
+	}
+	if estimate.PricedAs != "" {
+		return fmt.Sprintf("estimated cost $%.4f (no list price for %s, priced as %s; see --price-table)", estimate.Cost, cfg.ModelName, estimate.PricedAs)
+	}
+	return fmt.Sprintf("estimated cost $%.4f", estimate.Cost)
+}
+
 func selectModelInteractive() error {
 	t := translator.NewTranslator(cfg)
 	ctx := context.Background()
@@ -290,6 +543,8 @@ func main() {
 			// Handle non-structured errors
 			logger.Error(fmt.Sprintf("Error: %v", err))
 		}
+		_ = logger.Close()
 		os.Exit(1)
 	}
+	_ = logger.Close()
 }
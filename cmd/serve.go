@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/interrupt"
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/server"
+)
+
+var serveListen string
+var serveOptions server.Options
+var serveMaxUploadMB int64
+
+// serveCmd exposes translation as an HTTP job API
+var serveCmd = &cobra.Command{
+	Use:   "serve [flags]",
+	Short: "Run an HTTP server that exposes translation as a job API",
+	Long: `Run an HTTP server that accepts subtitle uploads (or server-side paths with
+--allow-paths) as translation jobs. Jobs are queued and translated by a fixed
+number of workers; their status, progress (also as Server-Sent Events) and
+results are available over REST:
+
+  POST   /jobs              submit a job (multipart "file" field or JSON {"path": ...})
+  GET    /jobs              list jobs
+  GET    /jobs/{id}         job status and progress
+  GET    /jobs/{id}/events  progress stream (text/event-stream)
+  GET    /jobs/{id}/result  download the translated file
+  DELETE /jobs/{id}         cancel a job
+
+target_language, provider, model and description can be set per job and
+default to the command line flags.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := prepareRun(); err != nil {
+			return err
+		}
+		// Progress is reported per job over the API; concurrent bars would garble the terminal
+		logger.SetProgressBars(false)
+		serveOptions.MaxUploadSize = serveMaxUploadMB << 20
+
+		manager, err := server.NewManager(cfg, serveOptions)
+		if err != nil {
+			return err
+		}
+		defer manager.Close()
+
+		httpServer := &http.Server{
+			Addr:              serveListen,
+			Handler:           server.NewHandler(manager),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := interrupt.NotifyContext(context.Background())
+		defer stop()
+
+		errServe := make(chan error, 1)
+		go func() {
+			errServe <- httpServer.ListenAndServe()
+		}()
+		logger.Info(fmt.Sprintf("Listening on %s (work directory: %s)", serveListen, manager.WorkDir()))
+
+		select {
+		case err = <-errServe:
+			return err
+		case <-interrupt.Stopped(ctx):
+		case <-ctx.Done():
+		}
+
+		logger.Info("Shutting down...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err = httpServer.Shutdown(shutdownCtx); err != nil && !stdErrors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", ":8080", "Address to listen on")
+	serveCmd.Flags().IntVar(&serveOptions.Workers, "workers", 1, "Number of jobs translated concurrently")
+	serveCmd.Flags().IntVar(&serveOptions.QueueSize, "queue-size", 16, "Maximum number of jobs waiting for a worker")
+	serveCmd.Flags().StringVar(&serveOptions.WorkDir, "work-dir", "", "Directory for uploads and results (default: gst-serve in the system temp directory)")
+	serveCmd.Flags().BoolVar(&serveOptions.AllowPaths, "allow-paths", false, "Allow jobs to reference files on the server by path")
+	serveCmd.Flags().Int64Var(&serveMaxUploadMB, "max-upload-size", server.DefaultMaxUploadSize>>20, "Largest accepted upload in MiB")
+	serveCmd.Flags().DurationVar(&serveOptions.Retention, "retention", server.DefaultRetention, "How long finished jobs and their files are kept")
+
+	rootCmd.AddCommand(serveCmd)
+}
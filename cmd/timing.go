@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/translator"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// Options of the timing subcommands
+var (
+	timingOutput  string
+	timingOffset  string
+	timingAnchors []string
+	timingFromFPS string
+	timingToFPS   string
+	timingMinGap  string
+)
+
+// timingCmd groups the commands that retime subtitle files without translating
+var timingCmd = &cobra.Command{
+	Use:   "timing",
+	Short: "Shift, stretch, convert the frame rate of or repair subtitle timings",
+	Long: `Retime SRT files without translating them. Every file is rewritten in place,
+in its own charset, unless --output or --output-encoding is given; a file with
+lines the parser has to drop is only written to --output; --preserve-format also keeps
+the rest of the file as it was. The same transforms can run before or after a translation with
+--timing-pre and --timing-post.`,
+}
+
+var timingShiftCmd = &cobra.Command{
+	Use:   "shift --by OFFSET <SRT_FILE>...",
+	Short: "Move every cue by a constant offset",
+	Example: `  gst timing shift --by -1.5s movie.srt
+  gst timing shift --by 00:00:02,250 movie.srt`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runTimingStep("shift="+timingOffset, args)
+	},
+}
+
+var timingSyncCmd = &cobra.Command{
+	Use:   "sync --anchor OLD->NEW [--anchor OLD->NEW] <SRT_FILE>...",
+	Short: "Retime from one or two anchor points, correcting offset and drift",
+	Long: `Move the cue shown at OLD to NEW. With two anchors, one near the start and one
+near the end, the subtitles are also stretched linearly to correct drift.`,
+	Example: `  gst timing sync --anchor "00:01:10,500->00:01:12,000" --anchor "01:38:02,000->01:38:09,700" movie.srt`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		spec := "sync="
+		for i, anchor := range timingAnchors {
+			if i > 0 {
+				spec += ";"
+			}
+			spec += anchor
+		}
+		return runTimingStep(spec, args)
+	},
+}
+
+var timingFPSCmd = &cobra.Command{
+	Use:     "fps --from RATE --to RATE <SRT_FILE>...",
+	Short:   "Convert timings between frame rates, e.g. 23.976 to 25",
+	Example: `  gst timing fps --from 23.976 --to 25 movie.srt`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runTimingStep("fps="+timingFromFPS+":"+timingToFPS, args)
+	},
+}
+
+var timingFixCmd = &cobra.Command{
+	Use:   "fix [--min-gap DURATION] <SRT_FILE>...",
+	Short: "Repair overlapping cues and negative durations",
+	Long: `End every cue that runs into the next one where the next one starts, and give
+cues that end before they start a default duration. --min-gap also keeps that
+much time between consecutive cues.`,
+	Example: `  gst timing fix --min-gap 84ms movie.srt`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		specs := []string{"fix-overlaps"}
+		if timingMinGap != "" {
+			specs = append(specs, "min-gap="+timingMinGap)
+		}
+		return runTimingSteps(specs, args)
+	},
+}
+
+func init() {
+	timingCmd.PersistentFlags().StringVarP(&timingOutput, "output", "o", "", "Write the result to this file instead of rewriting the input (single file only)")
+	timingShiftCmd.Flags().StringVar(&timingOffset, "by", "", "Offset as a duration (-1.5s, 250ms) or timestamp (-00:00:01,500)")
+	_ = timingShiftCmd.MarkFlagRequired("by")
+	timingSyncCmd.Flags().StringArrayVar(&timingAnchors, "anchor", nil, "Anchor OLD->NEW, e.g. \"00:01:10,500->00:01:12,000\" (once or twice)")
+	_ = timingSyncCmd.MarkFlagRequired("anchor")
+	timingFPSCmd.Flags().StringVar(&timingFromFPS, "from", "", "Frame rate the subtitles were timed for")
+	timingFPSCmd.Flags().StringVar(&timingToFPS, "to", "", "Frame rate of the video")
+	_ = timingFPSCmd.MarkFlagRequired("from")
+	_ = timingFPSCmd.MarkFlagRequired("to")
+	timingFixCmd.Flags().StringVar(&timingMinGap, "min-gap", "", "Minimum gap between consecutive cues, e.g. 84ms")
+
+	timingCmd.AddCommand(timingShiftCmd, timingSyncCmd, timingFPSCmd, timingFixCmd)
+	rootCmd.AddCommand(timingCmd)
+}
+
+// runTimingStep applies one timing step to every file
+func runTimingStep(spec string, files []string) error {
+	return runTimingSteps([]string{spec}, files)
+}
+
+// runTimingSteps applies timing steps to every file and writes the results
+func runTimingSteps(specs []string, files []string) error {
+	steps, err := srt.ParseTimingSteps(specs)
+	if err != nil {
+		return errors.NewValidationError("invalid timing options", err)
+	}
+	if timingOutput != "" && len(files) > 1 {
+		return errors.NewValidationError("--output cannot be used with multiple input files", nil)
+	}
+	logger.SetColorMode(cfg.UseColors)
+	logger.SetQuietMode(cfg.QuietMode)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return errors.NewFileError("failed to read input file", err).WithContext("file_path", file)
+		}
+		subtitles, layout, diagnostics, err := srt.ParseLayout(data, cfg.InputEncoding)
+		if err != nil {
+			return errors.NewFileError("failed to decode input file", err).WithContext("file_path", file)
+		}
+		dropped := false
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Severity == srt.SeverityError {
+				dropped = true
+				logger.Error(fmt.Sprintf("%s: %s", file, diagnostic))
+			} else {
+				logger.Warning(fmt.Sprintf("%s: %s", file, diagnostic))
+			}
+		}
+		// Rewriting the input would lose the lines the parser dropped
+		if dropped && timingOutput == "" {
+			return errors.NewValidationError("the file has lines that could not be parsed and would be lost; fix it (see gst lint) or write to --output", nil).WithContext("file_path", file)
+		}
+		if len(subtitles) == 0 {
+			return errors.NewFileError("no subtitles found", nil).WithContext("file_path", file)
+		}
+		if err = srt.ApplyTiming(subtitles, steps); err != nil {
+			return errors.NewValidationError("failed to apply timing steps", err).WithContext("file_path", file)
+		}
+
+		output := file
+		if timingOutput != "" {
+			output = timingOutput
+		}
+		// The file keeps its charset and byte order mark unless others are asked for
+		charset := layout.Charset
+		if rootCmd.PersistentFlags().Changed("output-encoding") {
+			charset = cfg.OutputEncoding
+		}
+		text := srt.ComposeSRT(subtitles)
+		if cfg.PreserveFormat {
+			text = layout.Compose(subtitles)
+		}
+		content, err := srt.EncodeText(text, charset, cfg.OutputBOM || layout.BOM)
+		if err != nil {
+			return errors.NewValidationError("failed to encode the output", err).WithContext("output_encoding", charset)
+		}
+		if err = translator.WriteFileAtomic(output, content); err != nil {
+			return errors.NewFileError("failed to write output file", err).WithContext("file_path", output)
+		}
+		logger.Success(fmt.Sprintf("Retimed %d cues: %s", len(subtitles), output))
+	}
+	return nil
+}
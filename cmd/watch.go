@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/batch"
+	"github.com/luispater/gemini-srt-translator-go/internal/interrupt"
+	"github.com/luispater/gemini-srt-translator-go/internal/watch"
+)
+
+var watchOptions watch.Options
+
+// watchCmd watches a directory and translates new subtitle files as they appear
+var watchCmd = &cobra.Command{
+	Use:   "watch [flags] <DIR>",
+	Short: "Watch a directory and translate new SRT/MKV files as they appear",
+	Long: `Watch a directory (recursively) for new .srt and .mkv files, wait until they
+stop growing, and translate them into the configured target languages. Outputs
+are written next to the inputs, or below --output-dir. The queue is persisted so
+a restart resumes pending jobs through the regular .progress files.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := prepareRun(); err != nil {
+			return err
+		}
+		batchOptions.Roots, batchOptions.Config = args, cfg
+		watchOptions.Batch = batchOptions
+
+		runner, err := batch.NewRunner(cfg, batchOptions)
+		if err != nil {
+			return err
+		}
+
+		watcher, err := watch.NewWatcher(args[0], cfg, runner, watchOptions)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := interrupt.NotifyContext(context.Background())
+		defer cancel()
+		return watcher.Run(ctx)
+	},
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchOptions.Interval, "interval", 5*time.Second, "How often the directory is rescanned")
+	watchCmd.Flags().DurationVar(&watchOptions.StableFor, "stable-time", 10*time.Second, "How long a file must stay unchanged before it is queued")
+	watchCmd.Flags().StringVar(&watchOptions.StateFile, "state-file", "", "Queue/state file (default: "+watch.DefaultStateFile+" in the watched directory)")
+	watchCmd.Flags().StringSliceVar(&watchOptions.Targets, "targets", nil, "Target languages to translate into (default: --target-language)")
+
+	rootCmd.AddCommand(watchCmd)
+}
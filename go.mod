@@ -6,7 +6,9 @@ require (
 	github.com/luispater/matroska-go v1.2.4
 	github.com/openai/openai-go v1.12.0
 	github.com/spf13/cobra v1.9.1
+	golang.org/x/sys v0.34.0
 	golang.org/x/term v0.33.0
+	golang.org/x/text v0.27.0
 	google.golang.org/genai v1.57.0
 )
 
@@ -35,8 +37,6 @@ require (
 	go.opentelemetry.io/otel/trace v1.37.0 // indirect
 	golang.org/x/crypto v0.40.0 // indirect
 	golang.org/x/net v0.42.0 // indirect
-	golang.org/x/sys v0.34.0 // indirect
-	golang.org/x/text v0.27.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250728155136-f173205681a0 // indirect
 	google.golang.org/grpc v1.74.2 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
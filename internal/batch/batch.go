@@ -0,0 +1,376 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/interrupt"
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/internal/translator"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+)
+
+// SupportedExtensions lists the input file extensions that can be translated
+var SupportedExtensions = []string{".srt", ".mkv"}
+
+// Status describes the outcome of one file in a batch run
+type Status string
+
+const (
+	StatusTranslated Status = "translated"
+	StatusSkipped    Status = "skipped"
+	StatusFailed     Status = "failed"
+)
+
+// Result holds the outcome of translating one file
+type Result struct {
+	InputFile  string
+	OutputFile string
+	Status     Status
+	Err        error
+	Duration   time.Duration
+	Usage      providers.Usage
+}
+
+// Options controls how a batch run discovers and processes files
+type Options struct {
+	Include      []string       // Base name patterns a discovered file must match
+	Exclude      []string       // Base name patterns that drop a discovered file
+	SkipExisting bool           // Skip files whose output already exists
+	FailFast     bool           // Stop at the first failed file
+	Roots        []string       // Inputs given on the command line; --output-dir mirrors the directories below them
+	Config       *config.Config // Configuration naming the outputs; discovered files it would write for another input are skipped
+	Targets      []string       // Target languages whose outputs are skipped, defaults to the configured target language
+}
+
+// CollectInputs expands files, directories (recursively) and glob patterns into
+// a sorted list of translatable files. Include/exclude patterns and the
+// generated-file filter apply to discovered files only; explicitly named
+// files are always kept.
+func CollectInputs(args []string, opts Options) ([]string, error) {
+	seen := make(map[string]bool)
+	var explicit, discovered []string
+
+	add := func(list *[]string, path string) {
+		clean := filepath.Clean(path)
+		if !seen[clean] {
+			seen[clean] = true
+			*list = append(*list, clean)
+		}
+	}
+
+	for _, arg := range args {
+		var paths []string
+		isPattern := strings.ContainsAny(arg, "*?[")
+		if isPattern {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, errors.NewValidationError("invalid glob pattern", err).WithContext("pattern", arg)
+			}
+			paths = matches
+		} else {
+			paths = []string{arg}
+		}
+
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil {
+				if isPattern {
+					continue
+				}
+				return nil, errors.NewFileError(fmt.Sprintf("file does not exist: %s", path), err).WithContext("file_path", path)
+			}
+
+			if info.IsDir() {
+				errWalk := filepath.WalkDir(path, func(walkPath string, entry fs.DirEntry, errEntry error) error {
+					if errEntry != nil {
+						return errEntry
+					}
+					if !entry.IsDir() && isSupported(walkPath) {
+						add(&discovered, walkPath)
+					}
+					return nil
+				})
+				if errWalk != nil {
+					return nil, errors.NewFileError("failed to scan directory", errWalk).WithContext("dir_path", path)
+				}
+				continue
+			}
+
+			if !isSupported(path) {
+				if isPattern {
+					continue
+				}
+				return nil, errors.NewValidationError(fmt.Sprintf("file must have .srt or .mkv extension: %s", path), nil).WithContext("file_path", path)
+			}
+			if isPattern {
+				add(&discovered, path)
+			} else {
+				add(&explicit, path)
+			}
+		}
+	}
+
+	inputs := explicit
+	for _, path := range dropGeneratedFiles(discovered, opts) {
+		if matchesFilters(path, opts) {
+			inputs = append(inputs, path)
+		}
+	}
+	sort.Strings(inputs)
+	return inputs, nil
+}
+
+// isSupported reports whether the file has a translatable extension
+func isSupported(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, supported := range SupportedExtensions {
+		if ext == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesFilters applies include and exclude patterns to the file's base name
+func matchesFilters(path string, opts Options) bool {
+	base := filepath.Base(path)
+	if len(opts.Include) > 0 {
+		included := false
+		for _, pattern := range opts.Include {
+			if ok, _ := filepath.Match(pattern, base); ok {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, pattern := range opts.Exclude {
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// dropGeneratedFiles removes files this tool writes itself: extracted tracks,
+// "_translated" outputs and the files the configured output naming gives
+// another input in the list for any of the targets, such as E01.fr.srt next
+// to E01.srt
+func dropGeneratedFiles(paths []string, opts Options) []string {
+	targets := opts.Targets
+	if len(targets) == 0 {
+		targets = []string{""}
+	}
+	outputs := make(map[string]string) // Output file -> input it is written for
+	if opts.Config != nil {
+		for _, path := range paths {
+			for _, target := range targets {
+				output := filepath.Clean(translator.OutputPath(fileConfig(opts.Config, opts.Roots, path, target)))
+				if output != path {
+					outputs[output] = path
+				}
+			}
+		}
+	}
+
+	var result []string
+	for _, path := range paths {
+		lower := strings.ToLower(path)
+		if input, ok := outputs[path]; ok {
+			logger.Info(fmt.Sprintf("Skipping %s, it is the translation of %s", path, input))
+			continue
+		}
+		if strings.HasSuffix(lower, "_extracted.srt") || strings.HasSuffix(lower, "_translated.srt") {
+			logger.Info(fmt.Sprintf("Skipping %s, it was written by a previous run", path))
+			continue
+		}
+		result = append(result, path)
+	}
+	return result
+}
+
+// Runner translates several files with one shared provider
+type Runner struct {
+	config   *config.Config
+	provider providers.TranslationProvider
+	options  Options
+}
+
+// NewRunner creates a runner sharing a single provider, key pool and model
+// cache across all files
+func NewRunner(cfg *config.Config, opts Options) (*Runner, error) {
+	factory := &providers.ProviderFactory{}
+	provider, err := factory.NewProvider(cfg)
+	if err != nil {
+		return nil, errors.NewConfigurationError("failed to create provider", err)
+	}
+
+	return NewRunnerWithProvider(cfg, provider, opts), nil
+}
+
+// NewRunnerWithProvider creates a runner around an existing provider
+func NewRunnerWithProvider(cfg *config.Config, provider providers.TranslationProvider, opts Options) *Runner {
+	return &Runner{
+		config:   cfg,
+		provider: providers.NewCachingProvider(provider),
+		options:  opts,
+	}
+}
+
+// FileConfig returns a copy of the base configuration for one input file. A
+// non-empty targetLanguage overrides the configured target language. With an
+// output directory, the directory of the file below its root is recreated there.
+func (r *Runner) FileConfig(inputFile string, targetLanguage string) *config.Config {
+	return fileConfig(r.config, r.options.Roots, inputFile, targetLanguage)
+}
+
+// fileConfig is Runner.FileConfig for a base configuration and root inputs
+func fileConfig(base *config.Config, roots []string, inputFile string, targetLanguage string) *config.Config {
+	fileCfg := *base
+	fileCfg.InputFile = inputFile
+	if targetLanguage != "" {
+		fileCfg.TargetLanguage = targetLanguage
+	}
+	if fileCfg.OutputDir != "" {
+		fileCfg.OutputDir = filepath.Join(fileCfg.OutputDir, relativeDir(inputFile, roots))
+	}
+	return &fileCfg
+}
+
+// relativeDir returns the directory of a file relative to the deepest root
+// directory that contains it, or "." when no root does
+func relativeDir(inputFile string, roots []string) string {
+	dir := filepath.Dir(inputFile)
+	best, bestRoot := ".", ""
+	for _, root := range roots {
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(filepath.Clean(root), dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(root) > len(bestRoot) {
+			best, bestRoot = rel, root
+		}
+	}
+	return best
+}
+
+// Run translates the files in order. The conversation context of each file is
+// carried over to the next one so names and terms stay consistent.
+func (r *Runner) Run(ctx context.Context, inputFiles []string) []Result {
+	var results []Result
+	var carriedContext []providers.ContextMessage
+
+	for i, inputFile := range inputFiles {
+		logger.Highlight(fmt.Sprintf("[%d/%d] %s", i+1, len(inputFiles), inputFile))
+
+		result, lastContext := r.translate(ctx, r.FileConfig(inputFile, ""), carriedContext)
+		results = append(results, result)
+
+		if result.Status == StatusFailed {
+			if r.options.FailFast || interrupt.Requested(ctx) {
+				break
+			}
+			continue
+		}
+		if result.Status == StatusTranslated {
+			carriedContext = lastContext
+		}
+
+		// Interrupted: leave the remaining files for the next run
+		if interrupt.Requested(ctx) {
+			break
+		}
+	}
+
+	return results
+}
+
+// TranslateFile translates a single file. A non-empty targetLanguage overrides
+// the configured target language.
+func (r *Runner) TranslateFile(ctx context.Context, inputFile string, targetLanguage string) Result {
+	result, _ := r.translate(ctx, r.FileConfig(inputFile, targetLanguage), nil)
+	return result
+}
+
+// translate runs one translation and returns its result and final context
+func (r *Runner) translate(ctx context.Context, fileCfg *config.Config, carriedContext []providers.ContextMessage) (Result, []providers.ContextMessage) {
+	t := translator.NewTranslatorWithProvider(fileCfg, r.provider)
+	result := Result{InputFile: fileCfg.InputFile, OutputFile: t.OutputFile()}
+
+	if r.options.SkipExisting {
+		if _, err := os.Stat(t.OutputFile()); err == nil {
+			result.Status = StatusSkipped
+			logger.Info(fmt.Sprintf("Skipping %s, output already exists", fileCfg.InputFile))
+			return result, nil
+		}
+	}
+
+	t.SetContext(carriedContext)
+	startTime := time.Now()
+	err := t.Translate(ctx)
+	result.Duration = time.Since(startTime)
+	result.Usage = t.Usage()
+
+	if err != nil {
+		result.Status = StatusFailed
+		result.Err = err
+		logger.Error(fmt.Sprintf("Failed to translate %s: %v", fileCfg.InputFile, err))
+		return result, nil
+	}
+
+	result.Status = StatusTranslated
+	return result, t.Context()
+}
+
+// EstimateCosts runs the dry-run estimate for every file
+func (r *Runner) EstimateCosts(ctx context.Context, inputFiles []string) ([]*translator.CostEstimate, error) {
+	var estimates []*translator.CostEstimate
+	for _, inputFile := range inputFiles {
+		t := translator.NewTranslatorWithProvider(r.FileConfig(inputFile, ""), r.provider)
+		estimate, err := t.EstimateCost(ctx)
+		if err != nil {
+			if r.options.FailFast {
+				return nil, err
+			}
+			logger.Error(fmt.Sprintf("Failed to estimate %s: %v", inputFile, err))
+			continue
+		}
+		estimates = append(estimates, estimate)
+	}
+	return estimates, nil
+}
+
+// PrintSummary prints a per-file summary table and returns the number of failures
+func PrintSummary(results []Result) int {
+	failed := 0
+	logger.Highlight("\nBatch summary:")
+	logger.Info(fmt.Sprintf("%-10s  %-9s  %-12s  %s", "STATUS", "TIME", "TOKENS", "FILE"))
+	for _, result := range results {
+		tokens := result.Usage.PromptTokens + result.Usage.OutputTokens + result.Usage.ThinkingTokens
+		line := fmt.Sprintf("%-10s  %-9s  %-12d  %s", result.Status, result.Duration.Round(time.Second), tokens, result.InputFile)
+		switch result.Status {
+		case StatusFailed:
+			failed++
+			logger.Error(line + fmt.Sprintf(" (%v)", result.Err))
+		case StatusSkipped:
+			logger.Warning(line)
+		default:
+			logger.Success(line)
+		}
+	}
+	return failed
+}
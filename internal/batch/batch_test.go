@@ -0,0 +1,219 @@
+package batch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+const testSRT = "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n"
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(testSRT), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestCollectInputs(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	dir := t.TempDir()
+	writeFiles(t, dir,
+		"S01/E01.srt",
+		"S01/E01.fr.srt",
+		"S01/E01.pt-BR.forced.ai.srt",
+		"S01/E02.mkv",
+		"S01/E02_extracted.srt",
+		"S01/notes.txt",
+		"S02/E01.srt",
+		"S02/E01.sample.srt",
+		"S03/Movie.mkv",
+		"S03/Movie.en.srt",
+	)
+	french := &config.Config{TargetLanguage: "French"}
+
+	tests := []struct {
+		name string
+		args []string
+		opts Options
+		want []string
+	}{
+		{
+			name: "directory is scanned recursively without generated files",
+			args: []string{dir},
+			opts: Options{Config: french},
+			want: []string{"S01/E01.pt-BR.forced.ai.srt", "S01/E01.srt", "S01/E02.mkv", "S02/E01.sample.srt", "S02/E01.srt", "S03/Movie.en.srt", "S03/Movie.mkv"},
+		},
+		{
+			name: "output template",
+			args: []string{filepath.Join(dir, "S01")},
+			opts: Options{Config: &config.Config{TargetLanguage: "pt-BR", OutputTemplate: "{dir}/{name}.{lang}{.forced}{.ai}.{ext}", TrackFlags: []string{"forced"}}},
+			want: []string{"S01/E01.fr.srt", "S01/E01.srt", "S01/E02.mkv"},
+		},
+		{
+			name: "glob",
+			args: []string{filepath.Join(dir, "S01", "*.srt")},
+			opts: Options{Config: french},
+			want: []string{"S01/E01.pt-BR.forced.ai.srt", "S01/E01.srt"},
+		},
+		{
+			name: "include and exclude",
+			args: []string{dir},
+			opts: Options{Include: []string{"*.srt"}, Exclude: []string{"E01*.srt", "Movie.*"}},
+			want: nil,
+		},
+		{
+			name: "explicit file bypasses filters",
+			args: []string{filepath.Join(dir, "S01", "E01.fr.srt")},
+			opts: Options{Exclude: []string{"*"}},
+			want: []string{"S01/E01.fr.srt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CollectInputs(tt.args, tt.opts)
+			if err != nil {
+				t.Fatalf("CollectInputs() failed: %v", err)
+			}
+			var rel []string
+			for _, path := range got {
+				r, _ := filepath.Rel(dir, path)
+				rel = append(rel, filepath.ToSlash(r))
+			}
+			if !reflect.DeepEqual(rel, tt.want) {
+				t.Errorf("CollectInputs() = %v, want %v", rel, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectInputs_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "notes.txt")
+
+	if _, err := CollectInputs([]string{filepath.Join(dir, "missing.srt")}, Options{}); err == nil {
+		t.Error("Expected error for missing file")
+	}
+	if _, err := CollectInputs([]string{filepath.Join(dir, "notes.txt")}, Options{}); err == nil {
+		t.Error("Expected error for unsupported extension")
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	dir := t.TempDir()
+	writeFiles(t, dir, "E01.srt", "E02.srt", "E03.srt")
+	// E02 already has an output and E03 is not valid SRT
+	writeFiles(t, dir, "E02_translated.srt")
+	if err := os.WriteFile(filepath.Join(dir, "E03.srt"), []byte("garbage"), 0644); err != nil {
+		t.Fatalf("Failed to write E03: %v", err)
+	}
+
+	provider := &countingProvider{}
+	runner := NewRunnerWithProvider(&config.Config{
+		TargetLanguage: "Klingon",
+		ModelName:      "mock-model",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+	}, provider, Options{SkipExisting: true})
+
+	inputs := []string{filepath.Join(dir, "E01.srt"), filepath.Join(dir, "E02.srt"), filepath.Join(dir, "E03.srt")}
+	results := runner.Run(context.Background(), inputs)
+
+	var statuses []Status
+	for _, result := range results {
+		statuses = append(statuses, result.Status)
+	}
+	want := []Status{StatusTranslated, StatusSkipped, StatusFailed}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("statuses = %v, want %v", statuses, want)
+	}
+	if provider.modelCalls != 1 {
+		t.Errorf("Expected models to be fetched once, got %d", provider.modelCalls)
+	}
+	if failed := PrintSummary(results); failed != 1 {
+		t.Errorf("PrintSummary() = %d, want 1", failed)
+	}
+}
+
+func TestRunner_outputDir(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	dir := t.TempDir()
+	outputDir := t.TempDir()
+	writeFiles(t, dir, "Show/S01/E01.srt", "Show/S02/E01.srt")
+
+	runner := NewRunnerWithProvider(&config.Config{
+		TargetLanguage: "French",
+		ModelName:      "mock-model",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+		OutputDir:      outputDir,
+		OutputTemplate: "{dir}/{name}.{lang_jellyfin}{.forced}{.ai}.{ext}",
+	}, &countingProvider{}, Options{Roots: []string{filepath.Join(dir, "Show")}})
+
+	inputs, err := CollectInputs([]string{filepath.Join(dir, "Show")}, Options{})
+	if err != nil {
+		t.Fatalf("CollectInputs() failed: %v", err)
+	}
+	for _, result := range runner.Run(context.Background(), inputs) {
+		if result.Status != StatusTranslated {
+			t.Fatalf("%s: %s (%v)", result.InputFile, result.Status, result.Err)
+		}
+	}
+	for _, name := range []string{"S01/E01.fre.ai.srt", "S02/E01.fre.ai.srt"} {
+		if _, err = os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("Expected output %s: %v", name, err)
+		}
+	}
+
+	// Files outside every root are written directly to the output directory
+	if got := runner.FileConfig(filepath.Join(t.TempDir(), "movie.srt"), "").OutputDir; got != outputDir {
+		t.Errorf("FileConfig().OutputDir = %q, want %q", got, outputDir)
+	}
+}
+
+// countingProvider echoes batches and counts model list requests
+type countingProvider struct {
+	modelCalls int
+}
+
+func (p *countingProvider) GetModels(ctx context.Context) ([]string, error) {
+	p.modelCalls++
+	return []string{"mock-model"}, nil
+}
+
+func (p *countingProvider) GetTokenLimit(ctx context.Context, modelName string) (int32, error) {
+	return 100000, nil
+}
+
+func (p *countingProvider) CountTokens(ctx context.Context, modelName string, content string) (int32, error) {
+	return int32(len(content)), nil
+}
+
+func (p *countingProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []providers.ContextMessage, config *providers.TranslationConfig) (*providers.TranslationResponse, error) {
+	return &providers.TranslationResponse{TranslatedBatch: batch}, nil
+}
+
+func (p *countingProvider) GetName() string {
+	return "mock"
+}
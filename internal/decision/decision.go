@@ -0,0 +1,231 @@
+package decision
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+)
+
+// Actions for an existing output or saved progress
+const (
+	OutputResume    = "resume"
+	OutputOverwrite = "overwrite"
+	OutputFail      = "fail"
+)
+
+// Actions for a batch that exceeds the model token limit
+const (
+	TokenLimitShrink = "shrink"
+	TokenLimitFail   = "fail"
+)
+
+// Track describes an MKV subtitle track offered for selection
+type Track struct {
+	Language string
+	Name     string
+	Lines    int
+}
+
+// Decider answers the questions that come up while translating. The terminal
+// implementation asks the user, the policy implementation answers from flags.
+type Decider interface {
+	// ResumeProgress is asked when saved progress for the input exists
+	ResumeProgress(line int) (bool, error)
+
+	// StartLine is asked when the output exists without saved progress;
+	// it returns the 1-based line to continue from
+	StartLine(total int) (int, error)
+
+	// BatchSize is asked when a batch exceeds the token limit; it returns the new batch size
+	BatchSize(current int, tokenCount int32, tokenLimit int32) (int, error)
+
+	// SubtitleTrack picks one of several MKV subtitle tracks; best is the index
+	// of the recommended track. It returns the index of the chosen track.
+	SubtitleTrack(tracks []Track, best int) (int, error)
+}
+
+// Terminal asks the user on stdin
+type Terminal struct{}
+
+// ResumeProgress asks whether saved progress should be resumed
+func (Terminal) ResumeProgress(line int) (bool, error) {
+	input, err := logger.ReadInput("Found saved progress. Resume? (y/n): ")
+	if err != nil {
+		return false, noAnswer("resume saved progress", err)
+	}
+	input = strings.ToLower(input)
+	return input == "y" || input == "yes", nil
+}
+
+// StartLine asks for the line to continue from
+func (Terminal) StartLine(total int) (int, error) {
+	for {
+		input, err := logger.ReadInput(fmt.Sprintf("Enter the line number to start from (1 to %d): ", total))
+		if err != nil {
+			return 0, noAnswer("start line", err)
+		}
+		startLine, errParse := strconv.Atoi(input)
+		if errParse != nil || startLine < 1 || startLine > total {
+			logger.Warning(fmt.Sprintf("Line number must be between 1 and %d. Please try again.", total))
+			continue
+		}
+		return startLine, nil
+	}
+}
+
+// BatchSize asks for a smaller batch size
+func (Terminal) BatchSize(current int, _ int32, _ int32) (int, error) {
+	for {
+		input, err := logger.ReadInput(fmt.Sprintf("Please enter a new batch size (current: %d): ", current))
+		if err != nil {
+			return 0, noAnswer("batch size", err)
+		}
+		newBatchSize, errAtoi := strconv.Atoi(input)
+		if errAtoi != nil || newBatchSize <= 0 {
+			logger.Warning("Invalid input. Batch size must be a positive integer.")
+			continue
+		}
+		return newBatchSize, nil
+	}
+}
+
+// SubtitleTrack lists the tracks and asks for one; an empty answer picks the recommended track
+func (Terminal) SubtitleTrack(tracks []Track, best int) (int, error) {
+	logger.Info("Available subtitle tracks:")
+	for i, track := range tracks {
+		label := track.Language
+		if track.Name != "" {
+			label = fmt.Sprintf("%s (%s)", label, track.Name)
+		}
+		logger.Info(fmt.Sprintf("[%d] Language: %s, Lines:%d", i+1, label, track.Lines))
+	}
+
+	for {
+		input, err := logger.ReadInput("Select track number to extract: ")
+		if err != nil {
+			return 0, noAnswer("subtitle track", err)
+		}
+		if input == "" && best >= 0 {
+			return best, nil
+		}
+		if n, errAtoi := strconv.Atoi(input); errAtoi == nil && n >= 1 && n <= len(tracks) {
+			return n - 1, nil
+		}
+		logger.Warning("Invalid selection. Enter a valid number.")
+	}
+}
+
+// Policy answers every question from preset rules. Questions without a rule
+// are passed to Fallback, or fail when there is none.
+type Policy struct {
+	OnExistingOutput string // resume, overwrite or fail
+	OnTokenLimit     string // shrink or fail
+	Track            int    // 1-based MKV track number, 0 picks the recommended track
+	Fallback         Decider
+}
+
+// NonInteractive returns a policy that never asks, using safe defaults for
+// unset rules: fail on existing output, shrink oversized batches and pick the
+// recommended MKV track
+func NonInteractive(onExistingOutput string, onTokenLimit string, track int) *Policy {
+	if onExistingOutput == "" {
+		onExistingOutput = OutputFail
+	}
+	if onTokenLimit == "" {
+		onTokenLimit = TokenLimitShrink
+	}
+	return &Policy{OnExistingOutput: onExistingOutput, OnTokenLimit: onTokenLimit, Track: track}
+}
+
+// Validate checks the rule values
+func (p *Policy) Validate() error {
+	switch p.OnExistingOutput {
+	case "", OutputResume, OutputOverwrite, OutputFail:
+	default:
+		return errors.NewConfigurationError("on-existing-output must be one of resume, overwrite, fail", nil).WithContext("on_existing_output", p.OnExistingOutput)
+	}
+	switch p.OnTokenLimit {
+	case "", TokenLimitShrink, TokenLimitFail:
+	default:
+		return errors.NewConfigurationError("on-token-limit must be one of shrink, fail", nil).WithContext("on_token_limit", p.OnTokenLimit)
+	}
+	if p.Track < 0 {
+		return errors.NewConfigurationError("track must be a positive number", nil).WithContext("track", p.Track)
+	}
+	return nil
+}
+
+// ResumeProgress resumes or restarts according to OnExistingOutput
+func (p *Policy) ResumeProgress(line int) (bool, error) {
+	switch p.OnExistingOutput {
+	case OutputResume:
+		return true, nil
+	case OutputOverwrite:
+		return false, nil
+	case OutputFail:
+		return false, errors.NewValidationError("saved progress exists (use --on-existing-output=resume or overwrite)", nil).WithContext("line", line)
+	}
+	if p.Fallback != nil {
+		return p.Fallback.ResumeProgress(line)
+	}
+	return false, noAnswer("resume saved progress", nil)
+}
+
+// StartLine restarts from the first line when overwriting. Without saved
+// progress there is no line to resume from, so resume fails as well.
+func (p *Policy) StartLine(total int) (int, error) {
+	switch p.OnExistingOutput {
+	case OutputOverwrite:
+		return 1, nil
+	case OutputResume:
+		return 0, errors.NewValidationError("output file exists without saved progress to resume from (use --start-line or --on-existing-output=overwrite)", nil)
+	case OutputFail:
+		return 0, errors.NewValidationError("output file already exists (use --on-existing-output=overwrite)", nil)
+	}
+	if p.Fallback != nil {
+		return p.Fallback.StartLine(total)
+	}
+	return 0, noAnswer("start line", nil)
+}
+
+// BatchSize halves the batch size, or fails
+func (p *Policy) BatchSize(current int, tokenCount int32, tokenLimit int32) (int, error) {
+	switch p.OnTokenLimit {
+	case TokenLimitShrink:
+		if current <= 1 {
+			return 0, errors.NewValidationError("a single subtitle exceeds the token limit", nil).WithContext("token_count", tokenCount).WithContext("token_limit", tokenLimit)
+		}
+		return current / 2, nil
+	case TokenLimitFail:
+		return 0, errors.NewValidationError("batch size too large for the model token limit (use a smaller --batch-size or --on-token-limit=shrink)", nil).WithContext("current_batch_size", current).WithContext("token_count", tokenCount).WithContext("token_limit", tokenLimit)
+	}
+	if p.Fallback != nil {
+		return p.Fallback.BatchSize(current, tokenCount, tokenLimit)
+	}
+	return 0, noAnswer("batch size", nil)
+}
+
+// SubtitleTrack picks the configured track or the recommended one
+func (p *Policy) SubtitleTrack(tracks []Track, best int) (int, error) {
+	if p.Track > 0 {
+		if p.Track > len(tracks) {
+			return 0, errors.NewValidationError(fmt.Sprintf("track %d does not exist, the file has %d subtitle tracks", p.Track, len(tracks)), nil).WithContext("track", p.Track)
+		}
+		return p.Track - 1, nil
+	}
+	if p.Fallback != nil {
+		return p.Fallback.SubtitleTrack(tracks, best)
+	}
+	if best < 0 {
+		return 0, nil
+	}
+	return best, nil
+}
+
+// noAnswer reports a question that could not be answered
+func noAnswer(question string, cause error) error {
+	return errors.NewValidationError(fmt.Sprintf("cannot ask for %s without an interactive terminal (see --non-interactive)", question), cause)
+}
@@ -0,0 +1,130 @@
+package decision
+
+import (
+	"testing"
+)
+
+// recordingDecider remembers which questions reached it
+type recordingDecider struct {
+	asked []string
+}
+
+func (r *recordingDecider) ResumeProgress(int) (bool, error) {
+	r.asked = append(r.asked, "resume")
+	return true, nil
+}
+
+func (r *recordingDecider) StartLine(int) (int, error) {
+	r.asked = append(r.asked, "start")
+	return 7, nil
+}
+
+func (r *recordingDecider) BatchSize(int, int32, int32) (int, error) {
+	r.asked = append(r.asked, "batch")
+	return 3, nil
+}
+
+func (r *recordingDecider) SubtitleTrack([]Track, int) (int, error) {
+	r.asked = append(r.asked, "track")
+	return 1, nil
+}
+
+func TestNonInteractive(t *testing.T) {
+	policy := NonInteractive("", "", 0)
+
+	if _, err := policy.ResumeProgress(10); err == nil {
+		t.Error("Expected saved progress to fail by default")
+	}
+	if _, err := policy.StartLine(10); err == nil {
+		t.Error("Expected existing output to fail by default")
+	}
+	if size, err := policy.BatchSize(300, 1000, 900); err != nil || size != 150 {
+		t.Errorf("BatchSize() = %d, %v, want 150", size, err)
+	}
+	if _, err := policy.BatchSize(1, 1000, 900); err == nil {
+		t.Error("Expected error when a single subtitle exceeds the limit")
+	}
+	if index, err := policy.SubtitleTrack(make([]Track, 3), 2); err != nil || index != 2 {
+		t.Errorf("SubtitleTrack() = %d, %v, want recommended track 2", index, err)
+	}
+}
+
+func TestPolicy_Rules(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     *Policy
+		resume     bool
+		startLine  int
+		wantErrors bool
+	}{
+		{"resume", NonInteractive(OutputResume, "", 0), true, 0, true},
+		{"overwrite", NonInteractive(OutputOverwrite, "", 0), false, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resume, err := tt.policy.ResumeProgress(10)
+			if err != nil || resume != tt.resume {
+				t.Errorf("ResumeProgress() = %v, %v, want %v", resume, err, tt.resume)
+			}
+			startLine, err := tt.policy.StartLine(10)
+			if (err != nil) != tt.wantErrors || startLine != tt.startLine {
+				t.Errorf("StartLine() = %d, %v, want %d", startLine, err, tt.startLine)
+			}
+		})
+	}
+
+	if _, err := NonInteractive("", TokenLimitFail, 0).BatchSize(300, 1000, 900); err == nil {
+		t.Error("Expected on-token-limit=fail to fail")
+	}
+	if _, err := NonInteractive("", "", 5).SubtitleTrack(make([]Track, 3), 0); err == nil {
+		t.Error("Expected error for a track that does not exist")
+	}
+	if index, err := NonInteractive("", "", 2).SubtitleTrack(make([]Track, 3), 0); err != nil || index != 1 {
+		t.Errorf("SubtitleTrack() = %d, %v, want 1", index, err)
+	}
+}
+
+func TestPolicy_Fallback(t *testing.T) {
+	fallback := &recordingDecider{}
+	policy := &Policy{OnTokenLimit: TokenLimitShrink, Fallback: fallback}
+
+	if resume, _ := policy.ResumeProgress(10); !resume {
+		t.Error("Expected fallback answer")
+	}
+	if line, _ := policy.StartLine(10); line != 7 {
+		t.Errorf("StartLine() = %d, want fallback answer 7", line)
+	}
+	if size, _ := policy.BatchSize(10, 1000, 900); size != 5 {
+		t.Errorf("BatchSize() = %d, want policy answer 5", size)
+	}
+	if index, _ := policy.SubtitleTrack(make([]Track, 3), 0); index != 1 {
+		t.Errorf("SubtitleTrack() = %d, want fallback answer 1", index)
+	}
+
+	want := []string{"resume", "start", "track"}
+	if len(fallback.asked) != len(want) {
+		t.Fatalf("Fallback asked %v, want %v", fallback.asked, want)
+	}
+	for i := range want {
+		if fallback.asked[i] != want[i] {
+			t.Errorf("Fallback asked %v, want %v", fallback.asked, want)
+		}
+	}
+
+	// Without a fallback open questions fail instead of blocking
+	if _, err := (&Policy{}).StartLine(10); err == nil {
+		t.Error("Expected error without fallback")
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	if err := (&Policy{OnExistingOutput: "skip"}).Validate(); err == nil {
+		t.Error("Expected error for unknown existing output action")
+	}
+	if err := (&Policy{OnTokenLimit: "ignore"}).Validate(); err == nil {
+		t.Error("Expected error for unknown token limit action")
+	}
+	if err := NonInteractive(OutputResume, TokenLimitFail, 1).Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+}
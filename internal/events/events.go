@@ -0,0 +1,85 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	RunStarted     = "run_started"
+	BatchSent      = "batch_sent"
+	Thinking       = "thinking"
+	BatchCompleted = "batch_completed"
+	Retry          = "retry"
+	KeySwitched    = "key_switched"
+	RunFinished    = "run_finished"
+	RunFailed      = "run_failed"
+)
+
+// Event is one machine-readable progress event, written as a single JSON line.
+// Line numbers are 1-based; unset fields are omitted.
+type Event struct {
+	Type           string    `json:"type"`
+	Time           time.Time `json:"time"`
+	InputFile      string    `json:"input_file,omitempty"`
+	OutputFile     string    `json:"output_file,omitempty"`
+	TargetLanguage string    `json:"target_language,omitempty"`
+	Provider       string    `json:"provider,omitempty"`
+	Model          string    `json:"model,omitempty"`
+	Batch          int       `json:"batch,omitempty"`
+	BatchSize      int       `json:"batch_size,omitempty"`
+	FirstLine      int       `json:"first_line,omitempty"`
+	LastLine       int       `json:"last_line,omitempty"`
+	DoneLines      int       `json:"done_lines,omitempty"`
+	TotalLines     int       `json:"total_lines,omitempty"`
+	Attempt        int       `json:"attempt,omitempty"`
+	Key            int       `json:"key,omitempty"` // 1-based API key number
+	Requests       int       `json:"requests,omitempty"`
+	PromptTokens   int64     `json:"prompt_tokens,omitempty"`
+	OutputTokens   int64     `json:"output_tokens,omitempty"`
+	ThinkingTokens int64     `json:"thinking_tokens,omitempty"`
+	CachedTokens   int64     `json:"cached_tokens,omitempty"`
+	LatencyMS      int64     `json:"latency_ms,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+var (
+	output io.Writer
+	mutex  sync.Mutex
+)
+
+// SetOutput sends events to w as JSON lines; nil disables events
+func SetOutput(w io.Writer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	output = w
+}
+
+// Enabled reports whether events are written anywhere
+func Enabled() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return output != nil
+}
+
+// Emit writes an event, stamping the current time when none is set
+func Emit(event Event) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if output == nil {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, _ = output.Write(append(data, '\n'))
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestEmit(t *testing.T) {
+	// Disabled by default, emitting is a no-op
+	Emit(Event{Type: RunStarted})
+	if Enabled() {
+		t.Fatal("Expected events to be disabled without output")
+	}
+
+	var buffer bytes.Buffer
+	SetOutput(&buffer)
+	defer SetOutput(nil)
+
+	Emit(Event{Type: RunStarted, InputFile: "movie.srt", TotalLines: 10})
+	Emit(Event{Type: BatchCompleted, Batch: 1, FirstLine: 1, LastLine: 5, DoneLines: 5, TotalLines: 10, PromptTokens: 120})
+
+	scanner := bufio.NewScanner(&buffer)
+	var got []map[string]any
+	for scanner.Scan() {
+		var event map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, event)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(got))
+	}
+	if got[0]["type"] != RunStarted || got[0]["input_file"] != "movie.srt" {
+		t.Errorf("Unexpected first event: %v", got[0])
+	}
+	if _, ok := got[0]["time"]; !ok {
+		t.Error("Expected a time stamp")
+	}
+	if _, ok := got[0]["batch"]; ok {
+		t.Error("Expected unset fields to be omitted")
+	}
+	if got[1]["done_lines"] != float64(5) || got[1]["prompt_tokens"] != float64(120) {
+		t.Errorf("Unexpected second event: %v", got[1])
+	}
+}
@@ -2,36 +2,21 @@ package helpers
 
 import (
 	"context"
-	_ "embed"
 	"fmt"
-	"strings"
 
+	"github.com/luispater/gemini-srt-translator-go/internal/prompt"
 	"github.com/luispater/gemini-srt-translator-go/pkg/config"
 	"google.golang.org/genai"
 )
 
-//go:embed translation_instruction.md
-var instructionTemplate string
-
-// GetInstruction generates the system instruction for the translation model
+// GetInstruction renders the built-in system instruction for the translation model
 func GetInstruction(language string, thinking bool, thinkingCompatible bool, description string) string {
-	thinkingInstruction := ""
-	if thinking {
-		thinkingInstruction = "\nThink deeply and reason as much as possible before returning the response."
-	} else {
-		thinkingInstruction = "\nDo NOT think or reason."
-	}
-
-	instruction := strings.ReplaceAll(instructionTemplate, "{{TARGET_LANGUAGE}}", language)
-
-	if thinkingCompatible {
-		instruction += thinkingInstruction
-	}
-
-	if description != "" {
-		instruction += fmt.Sprintf("\n\nAdditional user instruction:\n\n%s", description)
-	}
-
+	instruction, _ := prompt.Default().Render(prompt.Data{
+		TargetLanguage:     language,
+		Description:        description,
+		Thinking:           thinking,
+		ThinkingCompatible: thinkingCompatible,
+	})
 	return instruction
 }
 
@@ -64,6 +49,41 @@ func GetResponseSchema() *genai.Schema {
 	}
 }
 
+// GetSegmentedResponseSchemaForBatch returns the response schema with an exact
+// item count for a batch in which some objects carry segments instead of content.
+func GetSegmentedResponseSchemaForBatch(itemCount int) *genai.Schema {
+	schema := GetResponseSchemaForBatch(itemCount)
+	items := schema.Items
+	items.Properties["segments"] = &genai.Schema{
+		Type:        genai.TypeArray,
+		Description: "Translated segments, present when the input object has segments",
+		Items: &genai.Schema{
+			Type: genai.TypeObject,
+			Properties: map[string]*genai.Schema{
+				"kind": {
+					Type:        genai.TypeString,
+					Description: "Segment kind copied unchanged from the input segment",
+					Enum:        []string{"speech", "sound", "music"},
+				},
+				"speaker": {
+					Type:        genai.TypeString,
+					Description: "Speaker label, present when the input segment has one",
+				},
+				"text": {
+					Type:        genai.TypeString,
+					Description: "Translated segment text",
+				},
+			},
+			Required:         []string{"kind", "text"},
+			PropertyOrdering: []string{"kind", "speaker", "text"},
+		},
+	}
+	items.PropertyOrdering = append(items.PropertyOrdering, "segments")
+	propertyCount := int64(len(items.Properties))
+	items.MaxProperties = &propertyCount
+	return schema
+}
+
 // GetResponseSchemaForBatch returns the response schema with an exact item count.
 func GetResponseSchemaForBatch(itemCount int) *genai.Schema {
 	schema := GetResponseSchema()
@@ -341,3 +341,33 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+func TestGetSegmentedResponseSchemaForBatch(t *testing.T) {
+	schema := GetSegmentedResponseSchemaForBatch(10)
+
+	if schema.MinItems == nil || *schema.MinItems != 10 {
+		t.Errorf("Expected minItems to be 10, got %v", schema.MinItems)
+	}
+	items := schema.Items
+	if items.MinProperties == nil || *items.MinProperties != 3 {
+		t.Errorf("Expected minProperties to be 3, got %v", items.MinProperties)
+	}
+	if items.MaxProperties == nil || *items.MaxProperties != 4 {
+		t.Errorf("Expected maxProperties to be 4, got %v", items.MaxProperties)
+	}
+	if contains(items.Required, "segments") {
+		t.Error("Expected 'segments' to be optional")
+	}
+	segments, ok := items.Properties["segments"]
+	if !ok || segments.Type != genai.TypeArray || segments.Items == nil {
+		t.Fatalf("Expected a segments array property, got %+v", segments)
+	}
+	if !contains(segments.Items.Required, "kind") || !contains(segments.Items.Required, "text") {
+		t.Errorf("Expected segment kind and text to be required, got %v", segments.Items.Required)
+	}
+
+	// The plain schema is not changed
+	if _, ok = GetResponseSchema().Items.Properties["segments"]; ok {
+		t.Error("Expected the plain schema to have no segments property")
+	}
+}
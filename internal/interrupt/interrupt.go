@@ -0,0 +1,76 @@
+package interrupt
+
+import (
+	"context"
+	stdErrors "errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+)
+
+// ErrStopped is returned when work ends early because a graceful stop was requested
+var ErrStopped = stdErrors.New("stopped by signal")
+
+type stopKey struct{}
+
+// WithStop returns a copy of ctx that carries a graceful stop request. Long
+// running loops finish their current unit of work and return once stop is closed.
+func WithStop(ctx context.Context, stop <-chan struct{}) context.Context {
+	return context.WithValue(ctx, stopKey{}, stop)
+}
+
+// Requested reports whether a graceful stop was requested or ctx is done
+func Requested(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	stop, ok := ctx.Value(stopKey{}).(<-chan struct{})
+	if !ok {
+		return false
+	}
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stopped returns a channel that is closed once a graceful stop is requested,
+// or nil when ctx carries no stop request
+func Stopped(ctx context.Context) <-chan struct{} {
+	stop, _ := ctx.Value(stopKey{}).(<-chan struct{})
+	return stop
+}
+
+// NotifyContext handles SIGINT and SIGTERM: the first signal requests a
+// graceful stop through the returned context, the second cancels it.
+// Calling the returned function restores the default signal behaviour.
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	stop := make(chan struct{})
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case <-signals:
+			logger.Warning("\nInterrupt received, finishing the current batch. Press Ctrl-C again to abort immediately.")
+			close(stop)
+		case <-ctx.Done():
+			return
+		}
+		select {
+		case <-signals:
+			logger.Warning("\nAborting.")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return WithStop(ctx, stop), cancel
+}
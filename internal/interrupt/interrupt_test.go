@@ -0,0 +1,38 @@
+package interrupt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequested(t *testing.T) {
+	if Requested(context.Background()) {
+		t.Error("Expected no stop request for a plain context")
+	}
+	if Stopped(context.Background()) != nil {
+		t.Error("Expected nil stop channel for a plain context")
+	}
+
+	stop := make(chan struct{})
+	ctx, cancel := context.WithCancel(WithStop(context.Background(), stop))
+	defer cancel()
+
+	if Requested(ctx) {
+		t.Error("Expected no stop request before the channel is closed")
+	}
+	close(stop)
+	if !Requested(ctx) {
+		t.Error("Expected stop request after the channel is closed")
+	}
+	select {
+	case <-Stopped(ctx):
+	default:
+		t.Error("Expected Stopped() channel to be closed")
+	}
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if !Requested(cancelled) {
+		t.Error("Expected a cancelled context to count as a stop request")
+	}
+}
@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// colorKey is the record attribute that carries the console color of a message
+const colorKey = "color"
+
+var (
+	level        = new(slog.LevelVar)
+	sinkMutex    sync.RWMutex
+	fileHandler  slog.Handler
+	logFile      io.Closer
+	secrets      []string
+	secretsMutex sync.RWMutex
+
+	// Gemini and OpenAI style API keys, redacted even when not registered
+	keyPattern = regexp.MustCompile(`AIza[0-9A-Za-z_\-]{30,}|sk-[0-9A-Za-z_\-]{20,}`)
+
+	base = slog.New(&fanoutHandler{})
+)
+
+// SetLevel sets the minimum level (debug, info, warn, error) for the console and the log file
+func SetLevel(name string) error {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("unknown log level %q, use debug, info, warn or error", name)
+	}
+	level.Set(parsed)
+	return nil
+}
+
+// SetLogFile appends every record to path as it is logged, formatted as text
+// or json. Unlike SaveLogsToFile this also keeps the log of failed runs.
+func SetLogFile(path string, format string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	var handler slog.Handler
+	switch format {
+	case "", "text":
+		handler = slog.NewTextHandler(file, options)
+	case "json":
+		handler = slog.NewJSONHandler(file, options)
+	default:
+		_ = file.Close()
+		return fmt.Errorf("unknown log format %q, use text or json", format)
+	}
+
+	if err = Close(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+	fileHandler = handler
+	logFile = file
+	return nil
+}
+
+// Close closes the log file, if one is open
+func Close() error {
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+	fileHandler = nil
+	if logFile == nil {
+		return nil
+	}
+	err := logFile.Close()
+	logFile = nil
+	return err
+}
+
+// AddSecrets registers values, e.g. API keys, that are replaced in every log output
+func AddSecrets(values ...string) {
+	secretsMutex.Lock()
+	defer secretsMutex.Unlock()
+	for _, value := range values {
+		if value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+}
+
+// Redact replaces registered secrets and anything that looks like an API key
+func Redact(text string) string {
+	secretsMutex.RLock()
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, "[REDACTED]")
+	}
+	secretsMutex.RUnlock()
+	return keyPattern.ReplaceAllString(text, "[REDACTED]")
+}
+
+// redactAttr hides the console color and redacts string values in file records
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if attr.Key == colorKey {
+		return slog.Attr{}
+	}
+	if attr.Key == slog.MessageKey || attr.Value.Kind() == slog.KindString || attr.Value.Kind() == slog.KindAny {
+		return slog.String(attr.Key, Redact(attr.Value.String()))
+	}
+	return attr
+}
+
+// logRecord logs a message with the given console color and attributes
+func logRecord(lvl slog.Level, color string, message string, args ...any) {
+	base.Log(context.Background(), lvl, message, append([]any{slog.String(colorKey, color)}, args...)...)
+}
+
+// fanoutHandler sends records to the console and to the log file
+type fanoutHandler struct {
+	steps []handlerStep // WithAttrs and WithGroup calls, replayed on the file handler in order
+}
+
+// handlerStep is one WithAttrs (group is empty) or WithGroup call
+type handlerStep struct {
+	group string
+	attrs []slog.Attr
+}
+
+func (f *fanoutHandler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return lvl >= level.Level()
+}
+
+func (f *fanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	writeConsole(record)
+
+	sinkMutex.RLock()
+	defer sinkMutex.RUnlock()
+	if fileHandler == nil {
+		return nil
+	}
+	handler := fileHandler
+	for _, step := range f.steps {
+		if step.group != "" {
+			handler = handler.WithGroup(step.group)
+		} else {
+			handler = handler.WithAttrs(step.attrs)
+		}
+	}
+	return handler.Handle(ctx, record)
+}
+
+func (f *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return f
+	}
+	return f.with(handlerStep{attrs: attrs})
+}
+
+func (f *fanoutHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return f
+	}
+	return f.with(handlerStep{group: name})
+}
+
+// with returns a copy of the handler with one more step
+func (f *fanoutHandler) with(step handlerStep) *fanoutHandler {
+	return &fanoutHandler{steps: append(append([]handlerStep{}, f.steps...), step)}
+}
+
+// writeConsole prints the colored message and keeps it for SaveLogsToFile.
+// Attributes only go to the log file.
+func writeConsole(record slog.Record) {
+	if quietMode {
+		return
+	}
+
+	color := ""
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == colorKey {
+			color = attr.Value.String()
+			return false
+		}
+		return true
+	})
+
+	message := Redact(record.Message)
+	fmt.Println(colorize(color, message))
+	storeMessage(message, strings.TrimSuffix(color, Bold))
+}
@@ -1,8 +1,11 @@
 package logger
 
 import (
+	"bufio"
 	"fmt"
 	"golang.org/x/term"
+	"io"
+	"log/slog"
 	"os"
 	"strings"
 	"sync"
@@ -25,10 +28,12 @@ const (
 var (
 	useColors    = true
 	quietMode    = false
+	drawBars     = true
 	logMessages  []LogMessage
 	logMutex     sync.RWMutex
 	loadingBars  = []string{"—", "\\", "|", "/"}
 	loadingIndex = 0
+	stdinReader  = bufio.NewReader(os.Stdin)
 )
 
 // LogMessage represents a stored log message
@@ -48,6 +53,12 @@ func SetQuietMode(enabled bool) {
 	quietMode = enabled
 }
 
+// SetProgressBars enables or disables drawing progress bars, e.g. when
+// progress is reported as JSON events instead
+func SetProgressBars(enabled bool) {
+	drawBars = enabled
+}
+
 // supportsColor checks if the terminal supports color output
 func supportsColor() bool {
 	if os.Getenv("NO_COLOR") != "" {
@@ -67,49 +78,35 @@ func colorize(color, text string) string {
 	return text
 }
 
+// Debug logs a diagnostic message, shown only at the debug level. The
+// optional key-value args are attributes that are written to the log file.
+func Debug(message string, args ...any) {
+	logRecord(slog.LevelDebug, White, message, args...)
+}
+
 // Info prints an information message in cyan
-func Info(message string) {
-	if quietMode {
-		return
-	}
-	fmt.Println(colorize(Cyan, message))
-	storeMessage(message, Cyan)
+func Info(message string, args ...any) {
+	logRecord(slog.LevelInfo, Cyan, message, args...)
 }
 
 // Warning prints a warning message in yellow
-func Warning(message string) {
-	if quietMode {
-		return
-	}
-	fmt.Println(colorize(Yellow, message))
-	storeMessage(message, Yellow)
+func Warning(message string, args ...any) {
+	logRecord(slog.LevelWarn, Yellow, message, args...)
 }
 
 // Error prints an error message in red
-func Error(message string) {
-	if quietMode {
-		return
-	}
-	fmt.Println(colorize(Red, message))
-	storeMessage(message, Red)
+func Error(message string, args ...any) {
+	logRecord(slog.LevelError, Red, message, args...)
 }
 
 // Success prints a success message in green
-func Success(message string) {
-	if quietMode {
-		return
-	}
-	fmt.Println(colorize(Green, message))
-	storeMessage(message, Green)
+func Success(message string, args ...any) {
+	logRecord(slog.LevelInfo, Green, message, args...)
 }
 
 // Highlight prints an important message in bold magenta
-func Highlight(message string) {
-	if quietMode {
-		return
-	}
-	fmt.Println(colorize(Magenta+Bold, message))
-	storeMessage(message, Magenta)
+func Highlight(message string, args ...any) {
+	logRecord(slog.LevelInfo, Magenta+Bold, message, args...)
 }
 
 // InputPrompt displays a colored input prompt
@@ -117,10 +114,20 @@ func InputPrompt(message string) string {
 	if quietMode {
 		return ""
 	}
+	input, _ := ReadInput(message)
+	return input
+}
+
+// ReadInput displays a prompt and reads one line from stdin. Unlike InputPrompt
+// the prompt is shown in quiet mode too, and a closed stdin is reported as an
+// error instead of an empty answer.
+func ReadInput(message string) (string, error) {
 	fmt.Print(colorize(White+Bold, message))
-	var input string
-	_, _ = fmt.Scanln(&input)
-	return strings.TrimSpace(input)
+	input, err := stdinReader.ReadString('\n')
+	if err != nil && (err != io.EOF || input == "") {
+		return "", err
+	}
+	return strings.TrimSpace(input), nil
 }
 
 // storeMessage stores a log message for later retrieval
@@ -158,6 +165,9 @@ type ProgressBar struct {
 	lastHeight int
 	startTime  time.Time
 	retryCount int
+	tokensIn   int64
+	tokensOut  int64
+	tokensTh   int64
 	isRunning  bool
 	stopChan   chan bool
 	mu         sync.Mutex
@@ -215,17 +225,14 @@ func (pb *ProgressBar) SetSending(sending bool) {
 	pb.isSending = sending
 }
 
-// PrintErrorAbove prints an error message above the progress bar and recreates the bar below
-func (pb *ProgressBar) PrintErrorAbove(message, color string) {
+// PrintErrorAbove prints an error message above the progress bar and recreates
+// the bar below. Red messages are logged as errors, yellow ones as warnings.
+func (pb *ProgressBar) PrintErrorAbove(message, color string, args ...any) {
 	pb.mu.Lock()
 	defer pb.mu.Unlock()
 
-	if quietMode {
-		return
-	}
-
 	// Clear the current progress bar display
-	if pb.lastHeight > 0 {
+	if pb.lastHeight > 0 && !quietMode {
 		// Move cursor up to the beginning of previous progress bar
 		fmt.Printf("\033[%dA", pb.lastHeight)
 		// Clear from cursor to end of screen
@@ -233,10 +240,14 @@ func (pb *ProgressBar) PrintErrorAbove(message, color string) {
 	}
 
 	// Print the error message (this becomes part of the terminal history)
-	fmt.Println(colorize(color, message))
-
-	// Store the message for logging
-	storeMessage(message, color)
+	lvl := slog.LevelInfo
+	switch color {
+	case Red:
+		lvl = slog.LevelError
+	case Yellow:
+		lvl = slog.LevelWarn
+	}
+	logRecord(lvl, color, message, args...)
 
 	// Reset the progress bar state so it will render fresh below the error
 	pb.lastHeight = 0
@@ -257,6 +268,15 @@ func (pb *ProgressBar) AddRetry() {
 	pb.retryCount++
 }
 
+// SetTokenUsage sets the running token totals shown in the progress bar
+func (pb *ProgressBar) SetTokenUsage(prompt, output, thinking int64) {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	pb.tokensIn = prompt
+	pb.tokensOut = output
+	pb.tokensTh = thinking
+}
+
 // Stop stops the auto-rendering goroutine
 func (pb *ProgressBar) Stop() {
 	pb.mu.Lock()
@@ -302,7 +322,7 @@ func (pb *ProgressBar) autoRender() {
 
 // renderInternal renders the progress bar (called by autoRender goroutine)
 func (pb *ProgressBar) renderInternal() {
-	if quietMode {
+	if quietMode || !drawBars {
 		return
 	}
 
@@ -338,6 +358,15 @@ func (pb *ProgressBar) renderInternal() {
 		progressText += fmt.Sprintf(" | Retries: %s", colorize(Yellow, fmt.Sprintf("%d", pb.retryCount)))
 	}
 
+	// Add token usage once the API has reported some
+	if pb.tokensIn > 0 || pb.tokensOut > 0 {
+		tokensText := fmt.Sprintf("%s in / %s out", formatTokenCount(pb.tokensIn), formatTokenCount(pb.tokensOut))
+		if pb.tokensTh > 0 {
+			tokensText += fmt.Sprintf(" / %s thinking", formatTokenCount(pb.tokensTh))
+		}
+		progressText += " | Tokens: " + colorize(Cyan, tokensText)
+	}
+
 	if pb.suffix != "" {
 		progressText += " ｜ " + pb.suffix
 	}
@@ -377,6 +406,18 @@ func (pb *ProgressBar) renderInternal() {
 	pb.lastHeight = currentHeight
 }
 
+// formatTokenCount renders a token count compactly, e.g. 950, 12.3k, 1.2M
+func formatTokenCount(count int64) string {
+	switch {
+	case count >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(count)/1_000_000)
+	case count >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(count)/1_000)
+	default:
+		return fmt.Sprintf("%d", count)
+	}
+}
+
 // SaveLogsToFile saves all stored messages to a file
 func SaveLogsToFile(filePath string) error {
 	file, err := os.Create(filePath)
@@ -527,3 +527,34 @@ func TestLoggingFunctionsNormalMode(t *testing.T) {
 	logMessages = originalMessages
 	logMutex.Unlock()
 }
+
+func TestProgressBar_SetTokenUsage(t *testing.T) {
+	pb := NewProgressBar(100, "Test")
+
+	// Set quiet mode to avoid output during test
+	originalQuiet := quietMode
+	quietMode = true
+	defer func() { quietMode = originalQuiet }()
+
+	pb.SetTokenUsage(1200, 300, 50)
+	if pb.tokensIn != 1200 || pb.tokensOut != 300 || pb.tokensTh != 50 {
+		t.Errorf("Unexpected token usage: %d/%d/%d", pb.tokensIn, pb.tokensOut, pb.tokensTh)
+	}
+}
+
+func TestFormatTokenCount(t *testing.T) {
+	tests := []struct {
+		count    int64
+		expected string
+	}{
+		{950, "950"},
+		{12345, "12.3k"},
+		{1_250_000, "1.2M"},
+	}
+
+	for _, tt := range tests {
+		if got := formatTokenCount(tt.count); got != tt.expected {
+			t.Errorf("formatTokenCount(%d) = %q, want %q", tt.count, got, tt.expected)
+		}
+	}
+}
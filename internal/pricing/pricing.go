@@ -0,0 +1,125 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Price holds the USD price per one million tokens for a model
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// Table maps model names (or name fragments) to prices
+type Table map[string]Price
+
+// DefaultTable returns the built-in list prices. Prices change often, so
+// users can override or extend them with a price table file.
+func DefaultTable() Table {
+	return Table{
+		"gemini-3-pro":          {Input: 2.00, Output: 12.00},
+		"gemini-3-flash":        {Input: 0.50, Output: 3.00},
+		"gemini-2.5-pro":        {Input: 1.25, Output: 10.00},
+		"gemini-2.5-flash":      {Input: 0.30, Output: 2.50},
+		"gemini-2.5-flash-lite": {Input: 0.10, Output: 0.40},
+		"gemini-2.0-flash":      {Input: 0.10, Output: 0.40},
+		"gpt-4o":                {Input: 2.50, Output: 10.00},
+		"gpt-4o-mini":           {Input: 0.15, Output: 0.60},
+		"gpt-4.1":               {Input: 2.00, Output: 8.00},
+		"gpt-4.1-mini":          {Input: 0.40, Output: 1.60},
+		"gpt-4.1-nano":          {Input: 0.10, Output: 0.40},
+	}
+}
+
+// LoadTable returns the default table merged with the entries of a JSON file
+// shaped like {"model-name": {"input": 1.25, "output": 10}}
+func LoadTable(filePath string) (Table, error) {
+	table := DefaultTable()
+	if filePath == "" {
+		return table, nil
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price table: %w", err)
+	}
+
+	var overrides Table
+	if err = json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse price table: %w", err)
+	}
+
+	for model, price := range overrides {
+		table[strings.ToLower(model)] = price
+	}
+
+	return table, nil
+}
+
+// Lookup finds the price for a model. The longest table key contained in the
+// model name wins, so "gemini-2.5-flash-lite" is not priced as "gemini-2.5-flash".
+func (t Table) Lookup(modelName string) (Price, bool) {
+	modelName = strings.ToLower(modelName)
+	bestKey := ""
+	for key := range t {
+		if strings.Contains(modelName, strings.ToLower(key)) && len(key) > len(bestKey) {
+			bestKey = key
+		}
+	}
+	if bestKey == "" {
+		return Price{}, false
+	}
+	return t[bestKey], true
+}
+
+// version matches the version numbers in a model name, such as 2.5 or 4.1
+var version = regexp.MustCompile(`\d+(?:\.\d+)*`)
+
+// LookupFamily prices a model that has no entry of its own like the newest
+// entry of its family, the entries whose names only differ in the version:
+// "gemini-3.5-flash" is priced as "gemini-3-flash". It returns the entry used.
+func (t Table) LookupFamily(modelName string) (Price, string, bool) {
+	family := version.ReplaceAllString(strings.ToLower(modelName), "#")
+	bestKey, bestFamily := "", ""
+	for key := range t {
+		keyFamily := version.ReplaceAllString(strings.ToLower(key), "#")
+		if !strings.Contains(keyFamily, "#") || !strings.Contains(family, keyFamily) {
+			continue
+		}
+		if len(keyFamily) > len(bestFamily) || (len(keyFamily) == len(bestFamily) && newerVersion(key, bestKey)) {
+			bestKey, bestFamily = key, keyFamily
+		}
+	}
+	if bestKey == "" {
+		return Price{}, "", false
+	}
+	return t[bestKey], bestKey, true
+}
+
+// newerVersion reports whether the first version in name is above the one in other
+func newerVersion(name string, other string) bool {
+	left, right := strings.Split(version.FindString(name), "."), strings.Split(version.FindString(other), ".")
+	for i := 0; i < max(len(left), len(right)); i++ {
+		var l, r int
+		if i < len(left) {
+			l, _ = strconv.Atoi(left[i])
+		}
+		if i < len(right) {
+			r, _ = strconv.Atoi(right[i])
+		}
+		if l != r {
+			return l > r
+		}
+	}
+	return false
+}
+
+// Cost returns the USD cost of the given token counts
+func (p Price) Cost(inputTokens, outputTokens int64) float64 {
+	return float64(inputTokens)/1_000_000*p.Input + float64(outputTokens)/1_000_000*p.Output
+}
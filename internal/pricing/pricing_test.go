@@ -0,0 +1,95 @@
+package pricing
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTable_Lookup(t *testing.T) {
+	table := DefaultTable()
+
+	tests := []struct {
+		model     string
+		wantInput float64
+		wantOK    bool
+	}{
+		{"gemini-2.5-flash", 0.30, true},
+		{"models/gemini-2.5-flash-lite", 0.10, true},
+		{"gpt-4o-mini-2024-07-18", 0.15, true},
+		{"unknown-model", 0, false},
+	}
+
+	for _, tt := range tests {
+		price, ok := table.Lookup(tt.model)
+		if ok != tt.wantOK {
+			t.Errorf("Lookup(%q) ok = %v, want %v", tt.model, ok, tt.wantOK)
+			continue
+		}
+		if price.Input != tt.wantInput {
+			t.Errorf("Lookup(%q) input = %v, want %v", tt.model, price.Input, tt.wantInput)
+		}
+	}
+}
+
+func TestTable_LookupFamily(t *testing.T) {
+	table := DefaultTable()
+
+	tests := []struct {
+		model   string
+		wantKey string
+	}{
+		{"gemini-3.5-flash", "gemini-3-flash"},
+		{"gemini-3.5-flash-lite", "gemini-2.5-flash-lite"},
+		{"models/gemini-4-pro-preview", "gemini-3-pro"},
+		{"gpt-4.5-mini", "gpt-4.1-mini"},
+		{"claude-sonnet", ""},
+	}
+
+	for _, tt := range tests {
+		price, key, ok := table.LookupFamily(tt.model)
+		if key != tt.wantKey || ok != (tt.wantKey != "") {
+			t.Errorf("LookupFamily(%q) = %q, %v, want %q", tt.model, key, ok, tt.wantKey)
+			continue
+		}
+		if ok && price != table[tt.wantKey] {
+			t.Errorf("LookupFamily(%q) price = %+v, want %+v", tt.model, price, table[tt.wantKey])
+		}
+	}
+}
+
+func TestPrice_Cost(t *testing.T) {
+	price := Price{Input: 1.25, Output: 10}
+	got := price.Cost(2_000_000, 500_000)
+	if math.Abs(got-7.5) > 1e-9 {
+		t.Errorf("Cost() = %v, want 7.5", got)
+	}
+}
+
+func TestLoadTable(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "prices.json")
+	content := `{"my-model": {"input": 1, "output": 2}, "gpt-4o": {"input": 3, "output": 4}}`
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write price table: %v", err)
+	}
+
+	table, err := LoadTable(filePath)
+	if err != nil {
+		t.Fatalf("LoadTable() failed: %v", err)
+	}
+
+	if price, ok := table.Lookup("my-model"); !ok || price.Output != 2 {
+		t.Errorf("Expected my-model price from file, got %+v (found %v)", price, ok)
+	}
+	if price, _ := table.Lookup("gpt-4o"); price.Input != 3 {
+		t.Errorf("Expected gpt-4o override, got %+v", price)
+	}
+	if _, ok := table.Lookup("gemini-2.5-pro"); !ok {
+		t.Error("Expected defaults to be kept")
+	}
+
+	if _, err = LoadTable(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
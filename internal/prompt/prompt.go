@@ -0,0 +1,204 @@
+// Package prompt renders the system instruction sent to every provider from a
+// text/template and per-target-language rule packs.
+package prompt
+
+import (
+	"bytes"
+	"embed"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/languages"
+)
+
+//go:embed templates/*.md rules/*.md
+var builtin embed.FS
+
+// DefaultTemplate is the name of the built-in English instruction template
+const DefaultTemplate = "default"
+
+// Data holds the variables available to a prompt template
+type Data struct {
+	SourceLanguage     string // Empty when the source language is not known
+	TargetLanguage     string
+	Description        string // The --description of the show
+	Glossary           []GlossaryEntry
+	StyleGuide         string
+	Rules              string // Rule pack of the target language, filled in by Render
+	Model              string
+	Thinking           bool // Thinking is enabled
+	ThinkingCompatible bool // The model follows the thinking instruction
+	Batch              Batch
+}
+
+// Batch describes the batch an instruction is rendered for
+type Batch struct {
+	Number     int // 1-based batch number
+	FirstLine  int // 1-based first subtitle line
+	LastLine   int
+	Lines      int
+	TotalLines int
+	Segments   bool // Some objects carry segments instead of content
+}
+
+// GlossaryEntry is a term and its fixed translation. An empty translation
+// means the term is kept as is.
+type GlossaryEntry struct {
+	Term        string
+	Translation string
+}
+
+// Engine renders instructions from a template and rule packs
+type Engine struct {
+	template *template.Template
+	rules    map[string]string // Rule packs by normalized language name
+}
+
+var defaultEngine = sync.OnceValues(func() (*Engine, error) { return New("", "") })
+
+// Default returns the engine with the built-in template and rule packs
+func Default() *Engine {
+	engine, err := defaultEngine()
+	if err != nil {
+		panic(err)
+	}
+	return engine
+}
+
+// New loads a template and rule packs. templateName is a template file or the
+// name of a built-in template (default, zh); empty selects the default. Rule
+// packs in rulesDir, named after the target language (e.g. japanese.md),
+// replace the built-in pack of the same language.
+func New(templateName string, rulesDir string) (*Engine, error) {
+	if templateName == "" {
+		templateName = DefaultTemplate
+	}
+
+	var source []byte
+	var err error
+	if _, errStat := os.Stat(templateName); errStat == nil {
+		if source, err = os.ReadFile(templateName); err != nil {
+			return nil, errors.NewFileError("failed to read prompt template", err).WithContext("file_path", templateName)
+		}
+	} else if source, err = builtin.ReadFile("templates/" + templateName + ".md"); err != nil {
+		return nil, errors.NewConfigurationError("prompt template not found", errStat).
+			WithContext("template", templateName).
+			WithContext("built_in", strings.Join(BuiltinTemplates(), ", "))
+	}
+
+	parsed, err := template.New(filepath.Base(templateName)).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return nil, errors.NewConfigurationError("invalid prompt template", err).WithContext("template", templateName)
+	}
+
+	engine := &Engine{template: parsed, rules: map[string]string{}}
+	entries, _ := builtin.ReadDir("rules")
+	for _, entry := range entries {
+		data, _ := builtin.ReadFile("rules/" + entry.Name())
+		engine.addRules(entry.Name(), data)
+	}
+
+	if rulesDir != "" {
+		entries, err = os.ReadDir(rulesDir)
+		if err != nil {
+			return nil, errors.NewFileError("failed to read rules directory", err).WithContext("dir_path", rulesDir)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !isRulesFile(entry.Name()) {
+				continue
+			}
+			data, errRead := os.ReadFile(filepath.Join(rulesDir, entry.Name()))
+			if errRead != nil {
+				return nil, errors.NewFileError("failed to read rule pack", errRead).WithContext("file_path", filepath.Join(rulesDir, entry.Name()))
+			}
+			engine.addRules(entry.Name(), data)
+		}
+	}
+	return engine, nil
+}
+
+// Render executes the template. The rule pack of the target language is added
+// unless data already carries rules.
+func (e *Engine) Render(data Data) (string, error) {
+	if data.Rules == "" {
+		data.Rules = e.Rules(data.TargetLanguage)
+	}
+
+	var buffer bytes.Buffer
+	if err := e.template.Execute(&buffer, data); err != nil {
+		return "", errors.NewConfigurationError("failed to render prompt template", err).WithContext("template", e.template.Name())
+	}
+	return buffer.String(), nil
+}
+
+// Rules returns the rule pack of a target language, or "" when there is none.
+// A language given by tag or alias ("zh-Hans") finds the pack of its name.
+func (e *Engine) Rules(targetLanguage string) string {
+	if rules, ok := e.rules[normalizeLanguage(targetLanguage)]; ok {
+		return rules
+	}
+	if language, ok := languages.Lookup(targetLanguage); ok {
+		return e.rules[normalizeLanguage(language.Name)]
+	}
+	return ""
+}
+
+// addRules registers a rule pack file
+func (e *Engine) addRules(fileName string, data []byte) {
+	language := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	e.rules[normalizeLanguage(language)] = strings.TrimSpace(string(data))
+}
+
+// BuiltinTemplates lists the names of the built-in templates
+func BuiltinTemplates() []string {
+	entries, _ := builtin.ReadDir("templates")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".md"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseGlossary parses "term = translation" entries; a bare term is kept as is
+func ParseGlossary(entries []string) []GlossaryEntry {
+	glossary := make([]GlossaryEntry, 0, len(entries))
+	for _, entry := range entries {
+		term, translation, _ := strings.Cut(entry, "=")
+		if term = strings.TrimSpace(term); term != "" {
+			glossary = append(glossary, GlossaryEntry{Term: term, Translation: strings.TrimSpace(translation)})
+		}
+	}
+	return glossary
+}
+
+// LoadStyleGuide reads a style guide file, or returns "" when path is empty
+func LoadStyleGuide(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.NewFileError("failed to read style guide", err).WithContext("file_path", path)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// isRulesFile reports whether a file in the rules directory is a rule pack
+func isRulesFile(name string) bool {
+	extension := strings.ToLower(filepath.Ext(name))
+	return extension == ".md" || extension == ".txt"
+}
+
+// normalizeLanguage matches "Simplified Chinese", "simplified_chinese" and
+// "simplified-chinese" to the same rule pack
+func normalizeLanguage(language string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(language), func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	}), "-")
+}
@@ -0,0 +1,111 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultRender(t *testing.T) {
+	instruction, err := Default().Render(Data{
+		TargetLanguage:     "Simplified Chinese",
+		Description:        "A cooking show",
+		Glossary:           ParseGlossary([]string{"Nakama = 伙伴", "Senpai", " "}),
+		StyleGuide:         "Keep it casual.",
+		Thinking:           true,
+		ThinkingCompatible: true,
+	})
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"translates subtitles from any language to Simplified Chinese",
+		"Additional rules for Simplified Chinese:\nYou *MUST* Replace all of the \",\" \".\" \"!\" \"?\" to four spaces.",
+		"Style guide:\n\nKeep it casual.",
+		"Glossary, always translate these terms as given:\n- Nakama: 伙伴\n- Senpai: keep as is\n",
+		"Think deeply and reason as much as possible",
+		"Additional user instruction:\n\nA cooking show",
+	} {
+		if !strings.Contains(instruction, want) {
+			t.Errorf("Instruction is missing %q", want)
+		}
+	}
+
+	instruction, err = Default().Render(Data{SourceLanguage: "English", TargetLanguage: "French"})
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !strings.Contains(instruction, "from English to French") {
+		t.Error("Instruction is missing the source language")
+	}
+	for _, unwanted := range []string{"Additional rules", "Glossary", "Style guide", "Do NOT think", "Additional user instruction"} {
+		if strings.Contains(instruction, unwanted) {
+			t.Errorf("Instruction contains %q", unwanted)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	templatePath := filepath.Join(dir, "short.tmpl")
+	template := "To {{.TargetLanguage}}, lines {{.Batch.FirstLine}}-{{.Batch.LastLine}} of {{.Batch.TotalLines}}.{{with .Rules}} {{.}}{{end}}"
+	if err := os.WriteFile(templatePath, []byte(template), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	rulesDir := filepath.Join(dir, "rules")
+	if err := os.Mkdir(rulesDir, 0755); err != nil {
+		t.Fatalf("Failed to create rules dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(rulesDir, "simplified_chinese.md"), []byte("Use full-width punctuation.\n"), 0644); err != nil {
+		t.Fatalf("Failed to write rule pack: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(rulesDir, "Japanese.txt"), []byte("Use polite forms."), 0644); err != nil {
+		t.Fatalf("Failed to write rule pack: %v", err)
+	}
+
+	engine, err := New(templatePath, rulesDir)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	instruction, err := engine.Render(Data{TargetLanguage: "Simplified Chinese", Batch: Batch{FirstLine: 301, LastLine: 600, TotalLines: 1400}})
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if want := "To Simplified Chinese, lines 301-600 of 1400. Use full-width punctuation."; instruction != want {
+		t.Errorf("Render() = %q, want %q", instruction, want)
+	}
+	if rules := engine.Rules("japanese"); rules != "Use polite forms." {
+		t.Errorf("Rules(japanese) = %q", rules)
+	}
+	if rules := engine.Rules("ja"); rules != "Use polite forms." {
+		t.Errorf("Rules(ja) = %q", rules)
+	}
+
+	zh, err := New("zh", "")
+	if err != nil {
+		t.Fatalf("New(zh) failed: %v", err)
+	}
+	instruction, _ = zh.Render(Data{TargetLanguage: "Simplified Chinese"})
+	if !strings.Contains(instruction, "翻译为 Simplified Chinese 的专家") {
+		t.Errorf("Unexpected zh instruction: %.80q", instruction)
+	}
+
+	if _, err = New("missing", ""); err == nil {
+		t.Error("Expected error for an unknown template")
+	}
+	badPath := filepath.Join(dir, "bad.tmpl")
+	_ = os.WriteFile(badPath, []byte("{{.TargetLanguage"), 0644)
+	if _, err = New(badPath, ""); err == nil {
+		t.Error("Expected error for an invalid template")
+	}
+	unknownField := filepath.Join(dir, "field.tmpl")
+	_ = os.WriteFile(unknownField, []byte("{{.Speaker}}"), 0644)
+	if engine, err = New(unknownField, ""); err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if _, err = engine.Render(Data{}); err == nil {
+		t.Error("Expected error for an unknown template variable")
+	}
+}
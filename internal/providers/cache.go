@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"context"
+	"sync"
+)
+
+// CachingProvider wraps a provider shared by several translations and caches
+// the model list and token limits so they are fetched only once per run. Use
+// AsKeySwitcher to reach the optional interfaces of the wrapped provider.
+type CachingProvider struct {
+	TranslationProvider
+
+	mu          sync.Mutex
+	models      []string
+	tokenLimits map[string]int32
+}
+
+// NewCachingProvider wraps the given provider
+func NewCachingProvider(provider TranslationProvider) *CachingProvider {
+	return &CachingProvider{
+		TranslationProvider: provider,
+		tokenLimits:         make(map[string]int32),
+	}
+}
+
+// GetModels returns the cached model list, fetching it on first use
+func (c *CachingProvider) GetModels(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.models != nil {
+		return c.models, nil
+	}
+	models, err := c.TranslationProvider.GetModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.models = models
+	return models, nil
+}
+
+// GetTokenLimit returns the cached token limit for a model, fetching it on first use
+func (c *CachingProvider) GetTokenLimit(ctx context.Context, modelName string) (int32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if limit, ok := c.tokenLimits[modelName]; ok {
+		return limit, nil
+	}
+	limit, err := c.TranslationProvider.GetTokenLimit(ctx, modelName)
+	if err != nil {
+		return 0, err
+	}
+	c.tokenLimits[modelName] = limit
+	return limit, nil
+}
+
+// Unwrap returns the wrapped provider, so optional interfaces such as
+// KeySwitcher are found only when the wrapped provider implements them
+func (c *CachingProvider) Unwrap() TranslationProvider {
+	return c.TranslationProvider
+}
@@ -0,0 +1,29 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+)
+
+func TestCachingProvider_keySwitcher(t *testing.T) {
+	replay := NewCachingProvider(&ReplayProvider{})
+	if _, ok := AsKeySwitcher(replay); ok {
+		t.Error("a wrapped replay provider should not switch keys")
+	}
+	if _, ok := any(replay).(KeySwitcher); ok {
+		t.Error("CachingProvider should not implement KeySwitcher itself")
+	}
+
+	openai, err := NewOpenAIProvider(&config.Config{APIKeys: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider() failed: %v", err)
+	}
+	keySwitcher, ok := AsKeySwitcher(NewCachingProvider(openai))
+	if !ok {
+		t.Fatal("a wrapped OpenAI provider should switch keys")
+	}
+	if !keySwitcher.SwitchAPIKey() || keySwitcher.GetCurrentAPIKeyIndex() != 1 {
+		t.Errorf("SwitchAPIKey() did not switch to the second key")
+	}
+}
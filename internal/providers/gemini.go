@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"google.golang.org/genai"
 
@@ -113,14 +114,9 @@ func (g *GeminiProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 
 	// Create generation config
 	thinkingCompatible := strings.Contains(config.ModelName, "2.5") || strings.Contains(config.ModelName, "gemini-3")
-	instruction := helpers.GetInstruction(
-		config.TargetLanguage,
-		config.Thinking,
-		thinkingCompatible,
-		config.Description,
-	)
-	if config.RetryInstruction != "" {
-		instruction += "\n\nRetry correction instruction:\n\n" + config.RetryInstruction
+	instruction, err := config.Instruction(thinkingCompatible)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build content parts
@@ -128,6 +124,7 @@ func (g *GeminiProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal batch: %w", err)
 	}
+	config.Exchange.recordRequest(g.GetName(), config.ModelName, instruction, previousContext, string(batchData))
 
 	var parts []*genai.Part
 	parts = append(parts, &genai.Part{Text: string(batchData)})
@@ -173,6 +170,9 @@ func (g *GeminiProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 	}
 	if schema, hasSchema := genConfig["response_schema"].(*genai.Schema); hasSchema {
 		genContentConfig.ResponseSchema = schema
+		if config.Batch.Segments {
+			genContentConfig.ResponseSchema = helpers.GetSegmentedResponseSchemaForBatch(len(batch))
+		}
 	}
 
 	genContentConfig.MaxOutputTokens = 65536
@@ -185,14 +185,25 @@ func (g *GeminiProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 		IncludeThoughts: config.Thinking,
 	}
 
-	var responseText string
+	var responseText, thoughtsText string
+	var usageMetadata *genai.GenerateContentResponseUsageMetadata
+	startTime := time.Now()
 
 	if config.Streaming {
 		stream := g.client.Models.GenerateContentStream(ctx, config.ModelName, contents, genContentConfig)
 
 		for chunk, errRange := range stream {
 			if errRange != nil {
-				return nil, fmt.Errorf("stream receive failed: %v", errRange)
+				config.Exchange.recordResponse(responseText, thoughtsText, nil, Usage{Latency: time.Since(startTime)})
+				return nil, fmt.Errorf("stream receive failed: %w", errRange)
+			}
+			if err = ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			// Usage metadata is cumulative, the last chunk carries the totals
+			if chunk.UsageMetadata != nil {
+				usageMetadata = chunk.UsageMetadata
 			}
 
 			if len(chunk.Candidates) == 0 {
@@ -203,6 +214,7 @@ func (g *GeminiProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 				if candidate.Content != nil {
 					for _, part := range candidate.Content.Parts {
 						if part.Thought {
+							thoughtsText += part.Text
 							if config.ProgressUpdater != nil {
 								config.ProgressUpdater.SetThinking(true)
 							}
@@ -222,22 +234,35 @@ func (g *GeminiProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 		// Non-streaming mode
 		result, errGenerateContent := g.client.Models.GenerateContent(ctx, config.ModelName, contents, genContentConfig)
 		if errGenerateContent != nil {
-			return nil, fmt.Errorf("generation failed: %v", errGenerateContent)
+			return nil, fmt.Errorf("generation failed: %w", errGenerateContent)
 		}
+		usageMetadata = result.UsageMetadata
 
 		if len(result.Candidates) > 0 && result.Candidates[0].Content != nil {
 			for _, part := range result.Candidates[0].Content.Parts {
-				if !part.Thought && part.Text != "" {
+				if part.Thought {
+					thoughtsText += part.Text
+				} else if part.Text != "" {
 					responseText += part.Text
 				}
 			}
 		}
 	}
 
+	usage := Usage{Latency: time.Since(startTime)}
+	if usageMetadata != nil {
+		usage.PromptTokens = int64(usageMetadata.PromptTokenCount)
+		usage.OutputTokens = int64(usageMetadata.CandidatesTokenCount)
+		usage.ThinkingTokens = int64(usageMetadata.ThoughtsTokenCount)
+		usage.CachedTokens = int64(usageMetadata.CachedContentTokenCount)
+	}
+
 	// Parse response
-	translatedBatch, parsedResponseText, errParse := parseTranslatedBatch(responseText)
+	translatedBatch, parsedResponseText, repairs, errParse := parseResponse(responseText)
+	config.Exchange.recordResponse(responseText, thoughtsText, repairs, usage)
 	if errParse != nil {
-		return nil, errors.NewTranslationError("failed to parse response", errParse).WithContext("response_text", responseText)
+		// The tokens of the answer are billed even though it cannot be used
+		return &TranslationResponse{Usage: usage, Thoughts: thoughtsText}, errors.NewTranslationError("failed to parse response", errParse).WithContext("response_text", responseText)
 	}
 	responseText = parsedResponseText
 
@@ -250,6 +275,8 @@ func (g *GeminiProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 	return &TranslationResponse{
 		TranslatedBatch: translatedBatch,
 		Context:         newContext,
+		Usage:           usage,
+		Thoughts:        thoughtsText,
 	}, nil
 }
 
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
+	"github.com/openai/openai-go/packages/respjson"
 
 	"github.com/luispater/gemini-srt-translator-go/pkg/config"
 	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
@@ -118,9 +120,9 @@ func (o *OpenAIProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 	}
 
 	// Build system instruction
-	instruction := o.getInstruction(config.TargetLanguage, config.Description)
-	if config.RetryInstruction != "" {
-		instruction += "\n\nRetry correction instruction:\n\n" + config.RetryInstruction
+	instruction, err := config.Instruction(false)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build messages
@@ -144,6 +146,7 @@ func (o *OpenAIProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 		return nil, fmt.Errorf("failed to marshal batch: %w", err)
 	}
 	messages = append(messages, openai.UserMessage(string(batchData)))
+	config.Exchange.recordRequest(o.GetName(), config.ModelName, instruction, previousContext, string(batchData))
 
 	// Prepare request parameters
 	params := openai.ChatCompletionNewParams{
@@ -169,20 +172,43 @@ func (o *OpenAIProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 		defer config.ProgressUpdater.SetLoading(false)
 	}
 
-	var responseText string
+	var responseText, reasoningText string
+	var completionUsage openai.CompletionUsage
+	startTime := time.Now()
 
 	if config.Streaming {
-		// Streaming mode
+		// Streaming mode, asking for a final usage chunk
+		params.StreamOptions = openai.ChatCompletionStreamOptionsParam{IncludeUsage: openai.Bool(true)}
 		stream := o.client.Chat.Completions.NewStreaming(ctx, params)
 
 		for stream.Next() {
+			if err = ctx.Err(); err != nil {
+				_ = stream.Close()
+				return nil, err
+			}
 			chunk := stream.Current()
-			if len(chunk.Choices) > 0 && len(chunk.Choices[0].Delta.Content) > 0 {
-				responseText += chunk.Choices[0].Delta.Content
+			if chunk.Usage.TotalTokens > 0 {
+				completionUsage = chunk.Usage
+			}
+			if len(chunk.Choices) > 0 {
+				delta := chunk.Choices[0].Delta
+				if reasoning := reasoningField(delta.JSON.ExtraFields); reasoning != "" {
+					reasoningText += reasoning
+					if config.ProgressUpdater != nil {
+						config.ProgressUpdater.SetThinking(true)
+					}
+				}
+				if delta.Content != "" {
+					if config.ProgressUpdater != nil {
+						config.ProgressUpdater.SetThinking(false)
+					}
+					responseText += delta.Content
+				}
 			}
 		}
 
 		if err = stream.Err(); err != nil {
+			config.Exchange.recordResponse(responseText, reasoningText, nil, Usage{Latency: time.Since(startTime)})
 			return nil, fmt.Errorf("streaming failed: %w", err)
 		}
 	} else {
@@ -192,15 +218,28 @@ func (o *OpenAIProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 			return nil, fmt.Errorf("completion failed: %w", errNew)
 		}
 
+		completionUsage = completion.Usage
 		if len(completion.Choices) > 0 {
 			responseText = completion.Choices[0].Message.Content
+			reasoningText = reasoningField(completion.Choices[0].Message.JSON.ExtraFields)
 		}
 	}
 
+	// Completion tokens include reasoning tokens, report them separately
+	usage := Usage{
+		PromptTokens:   completionUsage.PromptTokens,
+		OutputTokens:   completionUsage.CompletionTokens - completionUsage.CompletionTokensDetails.ReasoningTokens,
+		ThinkingTokens: completionUsage.CompletionTokensDetails.ReasoningTokens,
+		CachedTokens:   completionUsage.PromptTokensDetails.CachedTokens,
+		Latency:        time.Since(startTime),
+	}
+
 	// Parse response
-	translatedBatch, parsedResponseText, errParse := parseTranslatedBatch(responseText)
+	translatedBatch, parsedResponseText, repairs, errParse := parseResponse(responseText)
+	config.Exchange.recordResponse(responseText, reasoningText, repairs, usage)
 	if errParse != nil {
-		return nil, errors.NewTranslationError("failed to parse response", errParse).WithContext("response_text", responseText)
+		// The tokens of the answer are billed even though it cannot be used
+		return &TranslationResponse{Usage: usage, Thoughts: reasoningText}, errors.NewTranslationError("failed to parse response", errParse).WithContext("response_text", responseText)
 	}
 	responseText = parsedResponseText
 
@@ -213,44 +252,25 @@ func (o *OpenAIProvider) TranslateBatch(ctx context.Context, batch []srt.Subtitl
 	return &TranslationResponse{
 		TranslatedBatch: translatedBatch,
 		Context:         newContext,
+		Usage:           usage,
+		Thoughts:        reasoningText,
 	}, nil
 }
 
-// getInstruction generates the system instruction for OpenAI translation
-func (o *OpenAIProvider) getInstruction(language string, description string) string {
-	fields := "- index: an integer translation index\n- content: the text to translate\n- guard: a line guard token that must be copied unchanged\n"
-
-	instruction := fmt.Sprintf(`You are an assistant that translates subtitles from any language to %s.
-You will receive a list of objects, each with these fields:
-
-%s
-
-Translate the 'content' field of each object.
-Copy the 'guard' field of each object exactly as received.
-If the 'content' field is empty, leave it as is.
-Preserve the original meaning, formatting intent, and special characters, but do not emit literal line breaks in JSON strings.
-Each input object represents one complete subtitle; even if its 'content' appears to contain \n, \r\n, \r, or multiple visual lines, translate it as one object.
-Do NOT move or merge 'content' between objects.
-Do NOT split one object's 'content' into multiple output objects.
-Do NOT add or remove any objects.
-Do NOT alter the 'index' field.
-Do NOT alter, translate, remove, or move the 'guard' field.
-
-Return the result as a JSON array with the same structure.
-
-If the target language is *Simplified Chinese*, please follow these instructions:
-Replace all of the "," "." "!" "?" to four spaces.
-Replace all of the \n, \r, \r\n, and literal line breaks to four spaces.
-Trim all the invisible characters at the beginning and end of the 'content' field.
-Remove all tags like <i></i>, but keep their content.
-Remove all invisible characters after ":" or "：" in the 'content' field.
-`, language, fields)
-
-	if description != "" {
-		instruction += fmt.Sprintf("\n\nAdditional user instruction:\n\n%s", description)
+// reasoningField returns the reasoning text that OpenAI-compatible servers
+// (DeepSeek, vLLM, OpenRouter, Ollama) send next to the content
+func reasoningField(fields map[string]respjson.Field) string {
+	for _, key := range []string{"reasoning_content", "reasoning"} {
+		field, ok := fields[key]
+		if !ok {
+			continue
+		}
+		var text string
+		if err := json.Unmarshal([]byte(field.Raw()), &text); err == nil && text != "" {
+			return text
+		}
 	}
-
-	return instruction
+	return ""
 }
 
 // SwitchAPIKey switches to the next available API key
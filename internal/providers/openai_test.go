@@ -0,0 +1,30 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/openai/openai-go"
+)
+
+func TestReasoningField(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"reasoning_content", `{"role":"assistant","content":"","reasoning_content":"Check the names."}`, "Check the names."},
+		{"reasoning", `{"role":"assistant","content":"","reasoning":"Keep it short."}`, "Keep it short."},
+		{"none", `{"role":"assistant","content":"[]"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var delta openai.ChatCompletionChunkChoiceDelta
+			if err := delta.UnmarshalJSON([]byte(tt.json)); err != nil {
+				t.Fatalf("UnmarshalJSON() failed: %v", err)
+			}
+			if got := reasoningField(delta.JSON.ExtraFields); got != tt.want {
+				t.Errorf("reasoningField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
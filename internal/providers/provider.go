@@ -2,7 +2,9 @@ package providers
 
 import (
 	"context"
+	"time"
 
+	"github.com/luispater/gemini-srt-translator-go/internal/prompt"
 	"github.com/luispater/gemini-srt-translator-go/pkg/config"
 	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
 )
@@ -18,7 +20,9 @@ type TranslationProvider interface {
 	// CountTokens counts tokens in the given content for a model
 	CountTokens(ctx context.Context, modelName string, content string) (int32, error)
 
-	// TranslateBatch translates a batch of subtitle objects
+	// TranslateBatch translates a batch of subtitle objects. When an answer
+	// arrived but cannot be parsed, the error comes with a response holding
+	// only its Usage and Thoughts.
 	TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []ContextMessage, config *TranslationConfig) (*TranslationResponse, error)
 
 	// GetName returns the provider name
@@ -44,12 +48,67 @@ type TranslationConfig struct {
 	Thinking         bool
 	ThinkingLevel    string
 	ProgressUpdater  ProgressUpdater
+	Exchange         *Exchange // Filled with the raw request and response when set
+
+	// Prompt template variables
+	Prompt         *prompt.Engine // Renders the instruction, the built-in template when nil
+	SourceLanguage string
+	Glossary       []prompt.GlossaryEntry
+	StyleGuide     string
+	Batch          prompt.Batch
+}
+
+// Instruction renders the system instruction for the batch, followed by the
+// retry correction when there is one
+func (c *TranslationConfig) Instruction(thinkingCompatible bool) (string, error) {
+	engine := c.Prompt
+	if engine == nil {
+		engine = prompt.Default()
+	}
+	instruction, err := engine.Render(prompt.Data{
+		SourceLanguage:     c.SourceLanguage,
+		TargetLanguage:     c.TargetLanguage,
+		Description:        c.Description,
+		Glossary:           c.Glossary,
+		StyleGuide:         c.StyleGuide,
+		Model:              c.ModelName,
+		Thinking:           c.Thinking,
+		ThinkingCompatible: thinkingCompatible,
+		Batch:              c.Batch,
+	})
+	if err != nil {
+		return "", err
+	}
+	if c.RetryInstruction != "" {
+		instruction += "\n\nRetry correction instruction:\n\n" + c.RetryInstruction
+	}
+	return instruction, nil
 }
 
 // TranslationResponse holds the response from translation
 type TranslationResponse struct {
 	TranslatedBatch []srt.SubtitleObject
 	Context         []ContextMessage
+	Usage           Usage
+	Thoughts        string // Thinking text or reasoning summary, when the model returned it
+}
+
+// Usage holds the token counts reported by the API and the request latency
+type Usage struct {
+	PromptTokens   int64         `json:"prompt_tokens"`
+	OutputTokens   int64         `json:"output_tokens"`
+	ThinkingTokens int64         `json:"thinking_tokens"`
+	CachedTokens   int64         `json:"cached_tokens"`
+	Latency        time.Duration `json:"latency"`
+}
+
+// Add accumulates another usage record into u
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.OutputTokens += other.OutputTokens
+	u.ThinkingTokens += other.ThinkingTokens
+	u.CachedTokens += other.CachedTokens
+	u.Latency += other.Latency
 }
 
 // KeySwitcher interface for providers that support multiple API keys
@@ -58,6 +117,27 @@ type KeySwitcher interface {
 	GetCurrentAPIKeyIndex() int
 }
 
+// Wrapper is implemented by providers that add behaviour around another provider
+type Wrapper interface {
+	Unwrap() TranslationProvider
+}
+
+// AsKeySwitcher returns the KeySwitcher of a provider, looking through
+// wrappers, and false when the provider underneath does not switch keys
+func AsKeySwitcher(provider TranslationProvider) (KeySwitcher, bool) {
+	for provider != nil {
+		if keySwitcher, ok := provider.(KeySwitcher); ok {
+			return keySwitcher, true
+		}
+		wrapper, ok := provider.(Wrapper)
+		if !ok {
+			break
+		}
+		provider = wrapper.Unwrap()
+	}
+	return nil, false
+}
+
 // ProgressUpdater interface for updating translation progress
 type ProgressUpdater interface {
 	SetLoading(loading bool)
@@ -72,6 +152,8 @@ func (f *ProviderFactory) NewProvider(cfg *config.Config) (TranslationProvider,
 	switch cfg.Provider {
 	case "openai":
 		return NewOpenAIProvider(cfg)
+	case "replay":
+		return NewReplayProvider(cfg)
 	case "gemini":
 		fallthrough
 	default:
@@ -0,0 +1,146 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// ReplayProvider answers batches with responses recorded by --record-dir, so a
+// failing run can be reproduced offline. A batch gets the first unused
+// transcript with the same payload, or else, with a warning, the next unused
+// one; in strict mode a batch without a matching transcript fails.
+type ReplayProvider struct {
+	config      *config.Config
+	transcripts []*Transcript
+	used        []bool
+	mu          sync.Mutex
+}
+
+// NewReplayProvider loads the transcripts in cfg.ReplayDir
+func NewReplayProvider(cfg *config.Config) (*ReplayProvider, error) {
+	if cfg.ReplayDir == "" {
+		return nil, errors.NewConfigurationError("the replay provider needs --replay-dir", nil)
+	}
+	transcripts, err := ReadTranscripts(cfg.ReplayDir)
+	if err != nil {
+		return nil, errors.NewFileError("failed to read transcripts", err).WithContext("replay_dir", cfg.ReplayDir)
+	}
+	if len(transcripts) == 0 {
+		return nil, errors.NewFileError("no transcripts found", nil).WithContext("replay_dir", cfg.ReplayDir)
+	}
+
+	return &ReplayProvider{
+		config:      cfg,
+		transcripts: transcripts,
+		used:        make([]bool, len(transcripts)),
+	}, nil
+}
+
+// GetName returns the provider name
+func (r *ReplayProvider) GetName() string {
+	return "replay"
+}
+
+// GetModels returns the recorded models and the configured one
+func (r *ReplayProvider) GetModels(_ context.Context) ([]string, error) {
+	models := []string{r.config.ModelName}
+	for _, transcript := range r.transcripts {
+		if transcript.Model != "" && !slices.Contains(models, transcript.Model) {
+			models = append(models, transcript.Model)
+		}
+	}
+	return models, nil
+}
+
+// GetTokenLimit returns a limit large enough for any recorded batch
+func (r *ReplayProvider) GetTokenLimit(_ context.Context, _ string) (int32, error) {
+	return 1_000_000, nil
+}
+
+// CountTokens estimates tokens like the OpenAI provider
+func (r *ReplayProvider) CountTokens(_ context.Context, _ string, content string) (int32, error) {
+	return int32(len(content)/4 + 1), nil
+}
+
+// TranslateBatch returns the next matching recorded response
+func (r *ReplayProvider) TranslateBatch(_ context.Context, batch []srt.SubtitleObject, previousContext []ContextMessage, config *TranslationConfig) (*TranslationResponse, error) {
+	batchData, err := json.Marshal(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch: %w", err)
+	}
+
+	transcript, matched := r.next(string(batchData), !r.config.ReplayStrict)
+	switch {
+	case transcript == nil && r.config.ReplayStrict:
+		return nil, errors.NewTranslationError("no recorded transcript matches the batch payload", nil).WithContext("replay_dir", r.config.ReplayDir).WithContext("payload", string(batchData))
+	case transcript == nil:
+		return nil, errors.NewTranslationError("no recorded response left to replay", nil).WithContext("replay_dir", r.config.ReplayDir)
+	case !matched:
+		logger.Warning(fmt.Sprintf("No recorded transcript matches the batch payload, replaying transcript %d of batch %d instead", transcript.Sequence, transcript.Batch))
+	}
+
+	if config.Exchange != nil {
+		*config.Exchange = transcript.Exchange
+		config.Exchange.Provider = r.GetName()
+		config.Exchange.Context = previousContext
+		config.Exchange.Payload = string(batchData)
+		config.Exchange.Repairs = nil
+	}
+
+	if transcript.Response == "" && transcript.Error != "" {
+		return nil, errors.NewAPIError("recorded request failed: "+transcript.Error, nil).WithContext("sequence", transcript.Sequence)
+	}
+
+	translatedBatch, parsedResponseText, repairs, errParse := parseResponse(transcript.Response)
+	if config.Exchange != nil {
+		config.Exchange.Repairs = repairs
+	}
+	if errParse != nil {
+		return &TranslationResponse{Usage: transcript.Usage, Thoughts: transcript.Thoughts}, errors.NewTranslationError("failed to parse response", errParse).WithContext("response_text", transcript.Response)
+	}
+
+	return &TranslationResponse{
+		TranslatedBatch: translatedBatch,
+		Context: []ContextMessage{
+			{Role: "user", Content: string(batchData)},
+			{Role: "model", Content: parsedResponseText},
+		},
+		Usage:    transcript.Usage,
+		Thoughts: transcript.Thoughts,
+	}, nil
+}
+
+// next claims the first unused transcript for a payload and reports whether
+// it was recorded for that payload. Without a match it claims the next unused
+// transcript when fallback is set, and returns nil otherwise.
+func (r *ReplayProvider) next(payload string, fallback bool) (*Transcript, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	first := -1
+	for i, transcript := range r.transcripts {
+		if r.used[i] {
+			continue
+		}
+		if transcript.Payload == payload {
+			r.used[i] = true
+			return transcript, true
+		}
+		if first < 0 {
+			first = i
+		}
+	}
+	if first < 0 || !fallback {
+		return nil, false
+	}
+	r.used[first] = true
+	return r.transcripts[first], false
+}
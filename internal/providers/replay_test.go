@@ -0,0 +1,111 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+func TestTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	if next := NextTranscriptSequence(dir); next != 1 {
+		t.Fatalf("NextTranscriptSequence() = %d on an empty directory, want 1", next)
+	}
+
+	for _, transcript := range []*Transcript{
+		{Sequence: 2, Batch: 1, Attempt: 2, Exchange: Exchange{Response: "second"}},
+		{Sequence: 1, Batch: 1, Attempt: 1, Exchange: Exchange{Response: "first"}},
+	} {
+		if err := WriteTranscript(dir, transcript); err != nil {
+			t.Fatalf("WriteTranscript() failed: %v", err)
+		}
+	}
+
+	if next := NextTranscriptSequence(dir); next != 3 {
+		t.Errorf("NextTranscriptSequence() = %d, want 3", next)
+	}
+	transcripts, err := ReadTranscripts(dir)
+	if err != nil {
+		t.Fatalf("ReadTranscripts() failed: %v", err)
+	}
+	if len(transcripts) != 2 || transcripts[0].Response != "first" || transcripts[1].Response != "second" {
+		t.Errorf("Unexpected transcripts: %+v", transcripts)
+	}
+}
+
+func TestReplayProvider(t *testing.T) {
+	dir := t.TempDir()
+	batch := []srt.SubtitleObject{{Index: 0, Content: "Hello", Guard: "GST_LINE_000000"}}
+	payload, _ := json.Marshal(batch)
+
+	recorded := []*Transcript{
+		// An unrelated request that failed
+		{Sequence: 1, Batch: 1, Attempt: 1, Exchange: Exchange{Payload: `[{"index":5}]`}, Error: "quota exceeded"},
+		// A response that needs a repair before it parses
+		{Sequence: 2, Batch: 1, Attempt: 1, Exchange: Exchange{
+			Model:    "gemini-test",
+			Payload:  string(payload),
+			Response: `[{"index":0,"Bonjour","guard":"GST_LINE_000000"}]`,
+			Usage:    Usage{PromptTokens: 10},
+		}},
+	}
+	for _, transcript := range recorded {
+		if err := WriteTranscript(dir, transcript); err != nil {
+			t.Fatalf("WriteTranscript() failed: %v", err)
+		}
+	}
+
+	if _, err := NewReplayProvider(&config.Config{ReplayDir: t.TempDir()}); err == nil {
+		t.Error("Expected error for a directory without transcripts")
+	}
+	provider, err := NewReplayProvider(&config.Config{ReplayDir: dir, ModelName: "any-model"})
+	if err != nil {
+		t.Fatalf("NewReplayProvider() failed: %v", err)
+	}
+
+	models, _ := provider.GetModels(context.Background())
+	if len(models) != 2 || models[0] != "any-model" || models[1] != "gemini-test" {
+		t.Errorf("GetModels() = %v", models)
+	}
+
+	// The matching payload is served first, with the repair applied again
+	exchange := &Exchange{}
+	response, err := provider.TranslateBatch(context.Background(), batch, nil, &TranslationConfig{Exchange: exchange})
+	if err != nil {
+		t.Fatalf("TranslateBatch() failed: %v", err)
+	}
+	if len(response.TranslatedBatch) != 1 || response.TranslatedBatch[0].Content != "Bonjour" {
+		t.Errorf("Unexpected batch: %+v", response.TranslatedBatch)
+	}
+	if response.Usage.PromptTokens != 10 {
+		t.Errorf("Usage = %+v, want recorded usage", response.Usage)
+	}
+	if len(exchange.Repairs) != 1 || exchange.Repairs[0] != "missing content key before guard" {
+		t.Errorf("Repairs = %v", exchange.Repairs)
+	}
+
+	// Then the remaining transcript, which replays the recorded failure
+	if _, err = provider.TranslateBatch(context.Background(), batch, nil, &TranslationConfig{}); err == nil {
+		t.Error("Expected the recorded error")
+	}
+	if _, err = provider.TranslateBatch(context.Background(), batch, nil, &TranslationConfig{}); err == nil {
+		t.Error("Expected error when no transcripts are left")
+	}
+
+	// In strict mode a batch that matches no transcript fails and claims nothing
+	strict, err := NewReplayProvider(&config.Config{ReplayDir: dir, ReplayStrict: true})
+	if err != nil {
+		t.Fatalf("NewReplayProvider() failed: %v", err)
+	}
+	drifted := []srt.SubtitleObject{{Index: 0, Content: "Hello!", Guard: "GST_LINE_000000"}}
+	if _, err = strict.TranslateBatch(context.Background(), drifted, nil, &TranslationConfig{}); err == nil || !strings.Contains(err.Error(), "matches the batch payload") {
+		t.Errorf("Expected a payload mismatch error, got %v", err)
+	}
+	if response, err = strict.TranslateBatch(context.Background(), batch, nil, &TranslationConfig{}); err != nil || response.TranslatedBatch[0].Content != "Bonjour" {
+		t.Errorf("Expected the matching transcript after a mismatch, got %+v, %v", response, err)
+	}
+}
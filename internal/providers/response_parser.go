@@ -15,25 +15,32 @@ var indexCommentBeforeContentPattern = regexp.MustCompile(`\{\s*"index"\s*:\s*(-
 var indexCommentBeforeContentValuePattern = regexp.MustCompile(`\{\s*"index"\s*:\s*(-?\d+)\s*://[^\r\n]*(?:\r?\n|\r)\s*("(?:(?:\\.)|[^"\\])*")\s*,\s*"guard"\s*:`)
 
 func parseTranslatedBatch(responseText string) ([]srt.SubtitleObject, string, error) {
+	translatedBatch, parsedText, _, err := parseResponse(responseText)
+	return translatedBatch, parsedText, err
+}
+
+// parseResponse parses the model response like parseTranslatedBatch and also
+// names the repairs that were needed to make it valid
+func parseResponse(responseText string) ([]srt.SubtitleObject, string, []string, error) {
 	var translatedBatch []srt.SubtitleObject
 	if err := json.Unmarshal([]byte(responseText), &translatedBatch); err != nil {
-		repairedResponseText := repairMissingContentKeys(responseText)
+		repairedResponseText, repairs := applyRepairs(responseText)
 		if repairedResponseText == responseText {
 			if decodedBatch, firstArrayText, ok := decodeFirstRepeatedArray(responseText); ok {
-				return decodedBatch, firstArrayText, nil
+				return decodedBatch, firstArrayText, []string{repairRepeatedArray}, nil
 			}
-			return nil, responseText, err
+			return nil, responseText, nil, err
 		}
 		if errRepair := json.Unmarshal([]byte(repairedResponseText), &translatedBatch); errRepair != nil {
 			if decodedBatch, firstArrayText, ok := decodeFirstRepeatedArray(repairedResponseText); ok {
-				return decodedBatch, firstArrayText, nil
+				return decodedBatch, firstArrayText, append(repairs, repairRepeatedArray), nil
 			}
-			return nil, responseText, err
+			return nil, responseText, repairs, err
 		}
-		return translatedBatch, repairedResponseText, nil
+		return translatedBatch, repairedResponseText, repairs, nil
 	}
 
-	return translatedBatch, responseText, nil
+	return translatedBatch, responseText, nil, nil
 }
 
 func decodeFirstRepeatedArray(responseText string) ([]srt.SubtitleObject, string, bool) {
@@ -55,11 +62,39 @@ func decodeFirstRepeatedArray(responseText string) ([]srt.SubtitleObject, string
 	return translatedBatch, strings.TrimSpace(responseText[:offset]), true
 }
 
+// repairRepeatedArray names the repair that keeps only the first of several JSON arrays
+const repairRepeatedArray = "repeated array dropped"
+
+// repairSteps are the text repairs for malformed responses, in the order they are applied
+var repairSteps = []struct {
+	name  string
+	apply func(string) string
+}{
+	{"index comment before content value", repairIndexCommentBeforeContentValue},
+	{"index comment before content", repairIndexCommentBeforeContent},
+	{"stray key before content", repairStrayContentKeyBeforeContent},
+	{"missing content key before guard", repairMissingContentKeyBeforeGuard},
+	{"missing content key", repairMissingContentKey},
+}
+
 func repairMissingContentKeys(responseText string) string {
-	responseText = repairIndexCommentBeforeContentValue(responseText)
-	responseText = repairIndexCommentBeforeContent(responseText)
-	responseText = repairStrayContentKeyBeforeContent(responseText)
-	responseText = repairMissingContentKeyBeforeGuard(responseText)
+	repaired, _ := applyRepairs(responseText)
+	return repaired
+}
+
+// applyRepairs runs every repair step and names the ones that changed the text
+func applyRepairs(responseText string) (string, []string) {
+	var applied []string
+	for _, step := range repairSteps {
+		if repaired := step.apply(responseText); repaired != responseText {
+			applied = append(applied, step.name)
+			responseText = repaired
+		}
+	}
+	return responseText, applied
+}
+
+func repairMissingContentKey(responseText string) string {
 	matches := missingContentKeyPattern.FindAllStringSubmatchIndex(responseText, -1)
 	if len(matches) == 0 {
 		return responseText
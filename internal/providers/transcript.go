@@ -0,0 +1,119 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exchange captures the raw request and response of one TranslateBatch call.
+// Providers fill it when TranslationConfig.Exchange is set.
+type Exchange struct {
+	Provider    string           `json:"provider"`
+	Model       string           `json:"model"`
+	Instruction string           `json:"instruction"`
+	Context     []ContextMessage `json:"context,omitempty"`
+	Payload     string           `json:"payload"`
+	Response    string           `json:"response"`           // Response text as received, before any repair
+	Thoughts    string           `json:"thoughts,omitempty"` // Thinking text, when the model returned it
+	Repairs     []string         `json:"repairs,omitempty"`  // Repairs applied to parse the response
+	Usage       Usage            `json:"usage"`
+}
+
+// Transcript is one recorded exchange with the batch it belongs to and its outcome
+type Transcript struct {
+	Sequence  int       `json:"sequence"`
+	Time      time.Time `json:"time"`
+	InputFile string    `json:"input_file,omitempty"`
+	Batch     int       `json:"batch"`
+	Attempt   int       `json:"attempt"`
+	FirstLine int       `json:"first_line"`
+	LastLine  int       `json:"last_line"`
+	Exchange
+	Error      string `json:"error,omitempty"`      // Request or parse error
+	Validation string `json:"validation,omitempty"` // "ok", or why the translated lines were rejected
+}
+
+// transcriptName returns the file name of a transcript, ordered by sequence
+func transcriptName(transcript *Transcript) string {
+	return fmt.Sprintf("%04d-batch%03d-attempt%d.json", transcript.Sequence, transcript.Batch, transcript.Attempt)
+}
+
+// WriteTranscript writes a transcript as a numbered JSON file into dir
+func WriteTranscript(dir string, transcript *Transcript) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, transcriptName(transcript)), data, 0644)
+}
+
+// NextTranscriptSequence returns the sequence number after the last transcript in dir
+func NextTranscriptSequence(dir string) int {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	next := 1
+	for _, path := range paths {
+		prefix, _, _ := strings.Cut(filepath.Base(path), "-")
+		if sequence, err := strconv.Atoi(prefix); err == nil && sequence >= next {
+			next = sequence + 1
+		}
+	}
+	return next
+}
+
+// ReadTranscripts reads every transcript in dir in recording order
+func ReadTranscripts(dir string) ([]*Transcript, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var transcripts []*Transcript
+	for _, path := range paths {
+		data, errRead := os.ReadFile(path)
+		if errRead != nil {
+			return nil, errRead
+		}
+		var transcript Transcript
+		if errUnmarshal := json.Unmarshal(data, &transcript); errUnmarshal != nil {
+			return nil, fmt.Errorf("invalid transcript %s: %w", path, errUnmarshal)
+		}
+		transcripts = append(transcripts, &transcript)
+	}
+
+	sort.SliceStable(transcripts, func(i, j int) bool {
+		return transcripts[i].Sequence < transcripts[j].Sequence
+	})
+	return transcripts, nil
+}
+
+// recordRequest stores the request side; it does nothing on a nil exchange
+func (e *Exchange) recordRequest(provider string, model string, instruction string, context []ContextMessage, payload string) {
+	if e == nil {
+		return
+	}
+	e.Provider = provider
+	e.Model = model
+	e.Instruction = instruction
+	e.Context = context
+	e.Payload = payload
+}
+
+// recordResponse stores the response side; it does nothing on a nil exchange
+func (e *Exchange) recordResponse(response string, thoughts string, repairs []string, usage Usage) {
+	if e == nil {
+		return
+	}
+	e.Response = response
+	e.Thoughts = thoughts
+	e.Repairs = repairs
+	e.Usage = usage
+}
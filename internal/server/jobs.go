@@ -0,0 +1,550 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/batch"
+	"github.com/luispater/gemini-srt-translator-go/internal/decision"
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/internal/translator"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+)
+
+// JobStatus describes where a job is in its lifecycle
+type JobStatus string
+
+const (
+	StatusQueued    JobStatus = "queued"
+	StatusRunning   JobStatus = "running"
+	StatusDone      JobStatus = "done"
+	StatusFailed    JobStatus = "failed"
+	StatusCancelled JobStatus = "cancelled"
+)
+
+// Finished reports whether the job reached a final status
+func (s JobStatus) Finished() bool {
+	return s == StatusDone || s == StatusFailed || s == StatusCancelled
+}
+
+var (
+	// ErrQueueFull is returned when the job queue has no free slot
+	ErrQueueFull = stdErrors.New("job queue is full")
+	// ErrJobNotFound is returned for unknown job IDs
+	ErrJobNotFound = stdErrors.New("job not found")
+	// ErrJobFinished is returned when cancelling a job that already finished
+	ErrJobFinished = stdErrors.New("job already finished")
+	// ErrNoResult is returned when downloading the result of an unfinished or failed job
+	ErrNoResult = stdErrors.New("no result available")
+)
+
+// JobRequest holds the per-job settings. Empty fields fall back to the server configuration.
+type JobRequest struct {
+	TargetLanguage string `json:"target_language"`
+	Provider       string `json:"provider,omitempty"`
+	Model          string `json:"model,omitempty"`
+	Description    string `json:"description,omitempty"`
+	Path           string `json:"path,omitempty"` // File on the server, requires Options.AllowPaths
+}
+
+// JobInfo is a snapshot of a job as returned by the API
+type JobInfo struct {
+	ID             string          `json:"id"`
+	Status         JobStatus       `json:"status"`
+	InputFile      string          `json:"input_file"`
+	TargetLanguage string          `json:"target_language"`
+	Provider       string          `json:"provider"`
+	Model          string          `json:"model"`
+	Progress       float64         `json:"progress"`
+	DoneLines      int             `json:"done_lines"`
+	TotalLines     int             `json:"total_lines"`
+	Error          string          `json:"error,omitempty"`
+	Usage          providers.Usage `json:"usage"`
+	CreatedAt      time.Time       `json:"created_at"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+	FinishedAt     *time.Time      `json:"finished_at,omitempty"`
+}
+
+// Options controls the job manager
+type Options struct {
+	Workers       int           // Number of jobs translated concurrently
+	QueueSize     int           // Number of jobs that may wait for a worker
+	WorkDir       string        // Directory holding uploads and results, one sub-directory per job
+	AllowPaths    bool          // Allow jobs to reference files on the server by path
+	MaxUploadSize int64         // Largest accepted request body in bytes
+	Retention     time.Duration // How long finished jobs and their files are kept
+}
+
+const (
+	// DefaultMaxUploadSize is the request body limit when none is set
+	DefaultMaxUploadSize = 2 << 30
+	// DefaultRetention is how long finished jobs are kept when not set
+	DefaultRetention = 24 * time.Hour
+)
+
+// job is the internal state of one translation job, guarded by Manager.mu
+type job struct {
+	info        JobInfo
+	config      *config.Config
+	outputFile  string
+	cancel      context.CancelFunc
+	subscribers map[chan struct{}]struct{}
+}
+
+// Manager queues translation jobs and runs them on a fixed number of workers
+type Manager struct {
+	config  *config.Config
+	options Options
+
+	mu    sync.Mutex
+	jobs  map[string]*job
+	queue chan *job
+
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+	closed bool
+
+	// newTranslator creates the translator of a job, replaceable in tests
+	newTranslator func(cfg *config.Config) *translator.Translator
+}
+
+// NewManager creates a job manager. The configuration is the template every job starts from.
+func NewManager(cfg *config.Config, opts Options) (*Manager, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 16
+	}
+	if opts.WorkDir == "" {
+		opts.WorkDir = filepath.Join(os.TempDir(), "gst-serve")
+	}
+	if opts.MaxUploadSize <= 0 {
+		opts.MaxUploadSize = DefaultMaxUploadSize
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultRetention
+	}
+	if err := os.MkdirAll(opts.WorkDir, 0755); err != nil {
+		return nil, errors.NewFileError("failed to create work directory", err).WithContext("dir_path", opts.WorkDir)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	m := &Manager{
+		config:        cfg,
+		options:       opts,
+		jobs:          make(map[string]*job),
+		queue:         make(chan *job, opts.QueueSize),
+		ctx:           ctx,
+		stop:          stop,
+		newTranslator: translator.NewTranslator,
+	}
+
+	for i := 0; i < opts.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+	m.wg.Add(1)
+	go m.evictor()
+	return m, nil
+}
+
+// WorkDir returns the directory holding the job files
+func (m *Manager) WorkDir() string {
+	return m.options.WorkDir
+}
+
+// Close cancels running jobs and waits for the workers to stop
+func (m *Manager) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	close(m.queue)
+	m.mu.Unlock()
+
+	m.stop()
+	m.wg.Wait()
+}
+
+// Submit queues a job. When input is nil the job translates req.Path,
+// otherwise the upload is stored as fileName in the job directory.
+func (m *Manager) Submit(req JobRequest, fileName string, input io.Reader) (JobInfo, error) {
+	jobCfg, err := m.jobConfig(req)
+	if err != nil {
+		return JobInfo{}, err
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return JobInfo{}, err
+	}
+	dir := filepath.Join(m.options.WorkDir, id)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return JobInfo{}, errors.NewFileError("failed to create job directory", err).WithContext("dir_path", dir)
+	}
+
+	inputFile, err := m.storeInput(dir, req.Path, fileName, input)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return JobInfo{}, err
+	}
+	jobCfg.InputFile = inputFile
+
+	j := &job{
+		info: JobInfo{
+			ID:             id,
+			Status:         StatusQueued,
+			InputFile:      filepath.Base(inputFile),
+			TargetLanguage: jobCfg.TargetLanguage,
+			Provider:       jobCfg.Provider,
+			Model:          jobCfg.ModelName,
+			CreatedAt:      time.Now(),
+		},
+		config:      jobCfg,
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		_ = os.RemoveAll(dir)
+		return JobInfo{}, ErrQueueFull
+	}
+	select {
+	case m.queue <- j:
+	default:
+		_ = os.RemoveAll(dir)
+		return JobInfo{}, ErrQueueFull
+	}
+	m.jobs[id] = j
+
+	logger.Info(fmt.Sprintf("Queued job %s: %s -> %s", id, j.info.InputFile, j.info.TargetLanguage))
+	return j.info, nil
+}
+
+// jobConfig derives the configuration of a job from the server configuration
+func (m *Manager) jobConfig(req JobRequest) (*config.Config, error) {
+	jobCfg := *m.config
+	jobCfg.OutputFile = ""
+	jobCfg.StartLine = 0
+	jobCfg.LineSelection = ""
+	jobCfg.TimeSelection = ""
+	jobCfg.DryRun = false
+	resume := false
+	jobCfg.Resume = &resume
+	// Nobody is at the terminal to answer questions for a job
+	jobCfg.NonInteractive = true
+	jobCfg.OnExistingOutput = decision.OutputOverwrite
+
+	if req.TargetLanguage != "" {
+		jobCfg.TargetLanguage = req.TargetLanguage
+	}
+	if jobCfg.TargetLanguage == "" {
+		return nil, errors.NewValidationError("please provide a target language", nil)
+	}
+	if req.Description != "" {
+		jobCfg.Description = req.Description
+	}
+
+	provider := strings.ToLower(strings.TrimSpace(req.Provider))
+	if provider != "" && provider != jobCfg.Provider {
+		if provider != "gemini" && provider != "openai" {
+			return nil, errors.NewValidationError(fmt.Sprintf("unsupported provider: %s", req.Provider), nil).WithContext("provider", req.Provider)
+		}
+		if req.Model == "" {
+			return nil, errors.NewValidationError("a model is required when overriding the provider", nil).WithContext("provider", provider)
+		}
+		// The keys of the server provider do not apply, load the ones for this provider
+		jobCfg.Provider = provider
+		jobCfg.APIKeys = nil
+		jobCfg.BaseURL = ""
+		jobCfg.LoadEnvironmentForProvider()
+		if len(jobCfg.APIKeys) == 0 {
+			return nil, errors.NewConfigurationError(fmt.Sprintf("no API key configured for provider %s", provider), nil).WithContext("provider", provider)
+		}
+	}
+	if req.Model != "" {
+		jobCfg.ModelName = req.Model
+	}
+
+	return &jobCfg, nil
+}
+
+// storeInput places the job input in the job directory and returns its path
+func (m *Manager) storeInput(dir string, path string, fileName string, input io.Reader) (string, error) {
+	if input == nil {
+		if path == "" {
+			return "", errors.NewValidationError("please upload a file or provide a path", nil)
+		}
+		if !m.options.AllowPaths {
+			return "", errors.NewValidationError("submitting files by path is disabled (see --allow-paths)", nil).WithContext("path", path)
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return "", errors.NewFileError("invalid path", err).WithContext("path", path)
+		}
+		info, err := os.Stat(absPath)
+		if err != nil || info.IsDir() {
+			return "", errors.NewFileError(fmt.Sprintf("input file %s does not exist", path), err).WithContext("file_path", path)
+		}
+		if !isSupported(absPath) {
+			return "", errors.NewValidationError("file must have .srt or .mkv extension", nil).WithContext("file_path", path)
+		}
+
+		// Link the file into the job directory so progress and extracted files stay there
+		inputFile := filepath.Join(dir, filepath.Base(absPath))
+		if err = os.Symlink(absPath, inputFile); err != nil {
+			return "", errors.NewFileError("failed to link input file", err).WithContext("file_path", path)
+		}
+		return inputFile, nil
+	}
+
+	name := filepath.Base(filepath.Clean("/" + fileName))
+	if name == "/" || name == "." {
+		return "", errors.NewValidationError("uploaded file has no name", nil)
+	}
+	if !isSupported(name) {
+		return "", errors.NewValidationError("file must have .srt or .mkv extension", nil).WithContext("file_name", fileName)
+	}
+
+	inputFile := filepath.Join(dir, name)
+	file, err := os.Create(inputFile)
+	if err != nil {
+		return "", errors.NewFileError("failed to store upload", err).WithContext("file_path", inputFile)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	if _, err = io.Copy(file, input); err != nil {
+		return "", errors.NewFileError("failed to store upload", err).WithContext("file_path", inputFile)
+	}
+	return inputFile, nil
+}
+
+// Get returns a snapshot of a job
+func (m *Manager) Get(id string) (JobInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return JobInfo{}, ErrJobNotFound
+	}
+	return j.info, nil
+}
+
+// List returns snapshots of all jobs, oldest first
+func (m *Manager) List() []JobInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	infos := make([]JobInfo, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		infos = append(infos, j.info)
+	}
+	sort.Slice(infos, func(i, k int) bool {
+		return infos[i].CreatedAt.Before(infos[k].CreatedAt)
+	})
+	return infos
+}
+
+// Cancel stops a queued or running job
+func (m *Manager) Cancel(id string) (JobInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return JobInfo{}, ErrJobNotFound
+	}
+
+	switch j.info.Status {
+	case StatusQueued:
+		// The worker skips it when it is dequeued
+		m.finishLocked(j, StatusCancelled, "")
+	case StatusRunning:
+		// The worker marks it cancelled once the translator returns
+		j.cancel()
+	default:
+		return j.info, ErrJobFinished
+	}
+	return j.info, nil
+}
+
+// ResultFile returns the translated file of a finished job
+func (m *Manager) ResultFile(id string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return "", ErrJobNotFound
+	}
+	if j.info.Status != StatusDone {
+		return "", fmt.Errorf("%w: job is %s", ErrNoResult, j.info.Status)
+	}
+	return j.outputFile, nil
+}
+
+// Subscribe returns a channel that is signalled whenever the job changes.
+// The returned function must be called to unsubscribe.
+func (m *Manager) Subscribe(id string) (<-chan struct{}, func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, nil, ErrJobNotFound
+	}
+
+	ch := make(chan struct{}, 1)
+	j.subscribers[ch] = struct{}{}
+	return ch, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		delete(j.subscribers, ch)
+	}, nil
+}
+
+// worker translates queued jobs until the queue is closed
+func (m *Manager) worker() {
+	defer m.wg.Done()
+	for j := range m.queue {
+		m.run(j)
+	}
+}
+
+// run translates one job
+func (m *Manager) run(j *job) {
+	m.mu.Lock()
+	if j.info.Status != StatusQueued {
+		m.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	defer cancel()
+	j.cancel = cancel
+	now := time.Now()
+	j.info.Status = StatusRunning
+	j.info.StartedAt = &now
+	m.notifyLocked(j)
+	m.mu.Unlock()
+
+	logger.Info(fmt.Sprintf("Starting job %s", j.info.ID))
+
+	t := m.newTranslator(j.config)
+	t.SetProgressFunc(func(done int, total int) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		j.info.DoneLines = done
+		j.info.TotalLines = total
+		if total > 0 {
+			j.info.Progress = float64(done) * 100 / float64(total)
+		}
+		j.info.Usage = t.Usage()
+		m.notifyLocked(j)
+	})
+
+	err := t.Translate(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j.info.Usage = t.Usage()
+	j.outputFile = t.OutputFile()
+	switch {
+	case ctx.Err() != nil:
+		m.finishLocked(j, StatusCancelled, "")
+		logger.Warning(fmt.Sprintf("Job %s cancelled", j.info.ID))
+	case err != nil:
+		m.finishLocked(j, StatusFailed, err.Error())
+		logger.Error(fmt.Sprintf("Job %s failed: %v", j.info.ID, err))
+	default:
+		j.info.Progress = 100
+		j.info.DoneLines = j.info.TotalLines
+		m.finishLocked(j, StatusDone, "")
+		logger.Success(fmt.Sprintf("Job %s finished", j.info.ID))
+	}
+}
+
+// finishLocked moves a job into a final status. m.mu must be held.
+func (m *Manager) finishLocked(j *job, status JobStatus, message string) {
+	now := time.Now()
+	j.info.Status = status
+	j.info.Error = message
+	j.info.FinishedAt = &now
+	m.notifyLocked(j)
+}
+
+// notifyLocked wakes every subscriber of a job. m.mu must be held.
+func (m *Manager) notifyLocked(j *job) {
+	for ch := range j.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// evictor removes finished jobs once they are older than the retention
+// period, until the manager is closed
+func (m *Manager) evictor() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(min(time.Minute, m.options.Retention))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.evict(now)
+		}
+	}
+}
+
+// evict forgets the jobs that finished before now minus the retention period
+// and removes their directories
+func (m *Manager) evict(now time.Time) {
+	m.mu.Lock()
+	var dirs []string
+	for id, j := range m.jobs {
+		if j.info.Status.Finished() && j.info.FinishedAt != nil && now.Sub(*j.info.FinishedAt) >= m.options.Retention {
+			delete(m.jobs, id)
+			dirs = append(dirs, filepath.Join(m.options.WorkDir, id))
+		}
+	}
+	m.mu.Unlock()
+
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Warning(fmt.Sprintf("Failed to remove job directory %s: %v", dir, err))
+			continue
+		}
+		logger.Info(fmt.Sprintf("Removed expired job %s", filepath.Base(dir)))
+	}
+}
+
+// isSupported reports whether the file has a translatable extension
+func isSupported(path string) bool {
+	return slices.Contains(batch.SupportedExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// newJobID returns a random job identifier
+func newJobID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+)
+
+// maxMemory is the part of a multipart upload kept in memory, the rest is spooled to disk
+const maxMemory = 32 << 20
+
+// Handler exposes the job manager as a REST API:
+//
+//	POST   /jobs              submit a job (multipart upload or JSON with a path)
+//	GET    /jobs              list jobs
+//	GET    /jobs/{id}         job status
+//	GET    /jobs/{id}/events  progress as Server-Sent Events
+//	GET    /jobs/{id}/result  download the translated file
+//	DELETE /jobs/{id}         cancel a job
+type Handler struct {
+	manager *Manager
+	mux     *http.ServeMux
+}
+
+// NewHandler creates the HTTP handler for a job manager
+func NewHandler(manager *Manager) *Handler {
+	h := &Handler{manager: manager, mux: http.NewServeMux()}
+	h.mux.HandleFunc("POST /jobs", h.submit)
+	h.mux.HandleFunc("GET /jobs", h.list)
+	h.mux.HandleFunc("GET /jobs/{id}", h.status)
+	h.mux.HandleFunc("GET /jobs/{id}/events", h.events)
+	h.mux.HandleFunc("GET /jobs/{id}/result", h.result)
+	h.mux.HandleFunc("DELETE /jobs/{id}", h.cancel)
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// submit accepts either multipart/form-data with a "file" field or a JSON JobRequest
+func (h *Handler) submit(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.manager.options.MaxUploadSize)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var req JobRequest
+	var fileName string
+	var input io.Reader
+
+	switch mediaType {
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			writeError(w, errors.NewValidationError("invalid multipart form", err))
+			return
+		}
+		defer func() {
+			_ = r.MultipartForm.RemoveAll()
+		}()
+
+		req = JobRequest{
+			TargetLanguage: r.FormValue("target_language"),
+			Provider:       r.FormValue("provider"),
+			Model:          r.FormValue("model"),
+			Description:    r.FormValue("description"),
+			Path:           r.FormValue("path"),
+		}
+
+		file, header, err := r.FormFile("file")
+		if err == nil {
+			defer func() {
+				_ = file.Close()
+			}()
+			fileName = header.Filename
+			input = file
+		} else if !stdErrors.Is(err, http.ErrMissingFile) {
+			writeError(w, errors.NewValidationError("invalid file upload", err))
+			return
+		}
+	case "application/json":
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, errors.NewValidationError("invalid JSON body", err))
+			return
+		}
+	default:
+		writeError(w, errors.NewValidationError("content type must be multipart/form-data or application/json", nil))
+		return
+	}
+
+	info, err := h.manager.Submit(req, fileName, input)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/jobs/"+info.ID)
+	writeJSON(w, http.StatusAccepted, info)
+}
+
+func (h *Handler) list(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.manager.List())
+}
+
+func (h *Handler) status(w http.ResponseWriter, r *http.Request) {
+	info, err := h.manager.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// events streams a snapshot of the job on every change until it finishes
+func (h *Handler) events(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	updates, unsubscribe, err := h.manager.Subscribe(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer unsubscribe()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, stdErrors.New("streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		info, errGet := h.manager.Get(id)
+		if errGet != nil {
+			return
+		}
+		data, _ := json.Marshal(info)
+
+		event := "progress"
+		if info.Status.Finished() {
+			event = "done"
+		}
+		if _, errWrite := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); errWrite != nil {
+			return
+		}
+		flusher.Flush()
+		if info.Status.Finished() {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-updates:
+		}
+	}
+}
+
+func (h *Handler) result(w http.ResponseWriter, r *http.Request) {
+	outputFile, err := h.manager.ResultFile(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	file, err := os.Open(outputFile)
+	if err != nil {
+		writeError(w, errors.NewFileError("failed to open result", err))
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	info, err := file.Stat()
+	if err != nil {
+		writeError(w, errors.NewFileError("failed to open result", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-subrip; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(outputFile)}))
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
+
+func (h *Handler) cancel(w http.ResponseWriter, r *http.Request) {
+	info, err := h.manager.Cancel(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError maps an error to a status code and writes it as {"error": "..."}
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var translatorErr *errors.TranslatorError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case stdErrors.As(err, &maxBytesErr):
+		status = http.StatusRequestEntityTooLarge
+	case stdErrors.Is(err, ErrJobNotFound):
+		status = http.StatusNotFound
+	case stdErrors.Is(err, ErrQueueFull):
+		status = http.StatusServiceUnavailable
+		w.Header().Set("Retry-After", "30")
+	case stdErrors.Is(err, ErrJobFinished), stdErrors.Is(err, ErrNoResult):
+		status = http.StatusConflict
+	case stdErrors.As(err, &translatorErr):
+		switch translatorErr.Type {
+		case errors.ErrorTypeValidation, errors.ErrorTypeFile, errors.ErrorTypeConfiguration:
+			status = http.StatusBadRequest
+		}
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,275 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/internal/translator"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+const testSRT = "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n"
+
+func newTestServer(t *testing.T, provider providers.TranslationProvider, opts Options) (*Manager, *httptest.Server) {
+	t.Helper()
+	logger.SetQuietMode(true)
+	t.Cleanup(func() { logger.SetQuietMode(false) })
+
+	opts.WorkDir = t.TempDir()
+	manager, err := NewManager(&config.Config{
+		Provider:       "gemini",
+		TargetLanguage: "Klingon",
+		ModelName:      "mock-model",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+	}, opts)
+	if err != nil {
+		t.Fatalf("NewManager() failed: %v", err)
+	}
+	manager.newTranslator = func(cfg *config.Config) *translator.Translator {
+		return translator.NewTranslatorWithProvider(cfg, provider)
+	}
+
+	srv := httptest.NewServer(NewHandler(manager))
+	t.Cleanup(func() {
+		srv.Close()
+		manager.Close()
+	})
+	return manager, srv
+}
+
+func upload(t *testing.T, url string, fileName string, fields map[string]string) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for key, value := range fields {
+		_ = writer.WriteField(key, value)
+	}
+	part, _ := writer.CreateFormFile("file", fileName)
+	_, _ = part.Write([]byte(testSRT))
+	_ = writer.Close()
+
+	resp, err := http.Post(url+"/jobs", writer.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatalf("POST /jobs failed: %v", err)
+	}
+	return resp
+}
+
+func decodeJob(t *testing.T, resp *http.Response) JobInfo {
+	t.Helper()
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	var info JobInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatalf("Failed to decode job: %v", err)
+	}
+	return info
+}
+
+func waitForStatus(t *testing.T, manager *Manager, id string, want JobStatus) JobInfo {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		info, err := manager.Get(id)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if info.Status == want {
+			return info
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	info, _ := manager.Get(id)
+	t.Fatalf("Job %s has status %s (%s), want %s", id, info.Status, info.Error, want)
+	return info
+}
+
+func TestServer_TranslateUpload(t *testing.T) {
+	manager, srv := newTestServer(t, &mockProvider{}, Options{})
+
+	resp := upload(t, srv.URL, "episode.srt", map[string]string{"target_language": "French"})
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST /jobs status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+	job := decodeJob(t, resp)
+	if job.TargetLanguage != "French" || job.InputFile != "episode.srt" {
+		t.Errorf("Unexpected job: %+v", job)
+	}
+
+	info := waitForStatus(t, manager, job.ID, StatusDone)
+	if info.Progress != 100 || info.TotalLines != 2 {
+		t.Errorf("Progress = %.0f%% of %d lines, want 100%% of 2", info.Progress, info.TotalLines)
+	}
+
+	// Events of a finished job end with a done event
+	resp, err := http.Get(srv.URL + "/jobs/" + job.ID + "/events")
+	if err != nil {
+		t.Fatalf("GET events failed: %v", err)
+	}
+	events, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if !strings.Contains(string(events), "event: done") {
+		t.Errorf("Expected done event, got %q", events)
+	}
+
+	resp, err = http.Get(srv.URL + "/jobs/" + job.ID + "/result")
+	if err != nil {
+		t.Fatalf("GET result failed: %v", err)
+	}
+	result, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(result), "[French] Hello") {
+		t.Errorf("GET result = %d %q", resp.StatusCode, result)
+	}
+	if disposition := resp.Header.Get("Content-Disposition"); !strings.Contains(disposition, "episode.fr.srt") {
+		t.Errorf("Content-Disposition = %q", disposition)
+	}
+}
+
+func TestServer_CancelAndQueueLimit(t *testing.T) {
+	provider := &mockProvider{block: true}
+	manager, srv := newTestServer(t, provider, Options{Workers: 1, QueueSize: 1})
+
+	running := decodeJob(t, upload(t, srv.URL, "a.srt", nil))
+	waitForStatus(t, manager, running.ID, StatusRunning)
+
+	queued := decodeJob(t, upload(t, srv.URL, "b.srt", nil))
+	resp := upload(t, srv.URL, "c.srt", nil)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Full queue status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	for _, id := range []string{queued.ID, running.ID} {
+		req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/jobs/"+id, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("DELETE failed: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("DELETE status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		waitForStatus(t, manager, id, StatusCancelled)
+	}
+
+	resp, _ = http.Get(srv.URL + "/jobs/" + running.ID + "/result")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Result of cancelled job status = %d, want %d", resp.StatusCode, http.StatusConflict)
+	}
+}
+
+func TestServer_SubmitErrors(t *testing.T) {
+	_, srv := newTestServer(t, &mockProvider{}, Options{})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        int
+	}{
+		{"path disabled", "application/json", `{"path": "/tmp/movie.srt"}`, http.StatusBadRequest},
+		{"no input", "application/json", `{"target_language": "French"}`, http.StatusBadRequest},
+		{"provider without model", "application/json", `{"provider": "openai", "path": "x.srt"}`, http.StatusBadRequest},
+		{"unsupported content type", "text/plain", "hello", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(srv.URL+"/jobs", tt.contentType, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("POST failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	resp, _ := http.Get(srv.URL + "/jobs/unknown")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Unknown job status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestServer_uploadLimit(t *testing.T) {
+	_, srv := newTestServer(t, &mockProvider{}, Options{MaxUploadSize: 64})
+
+	resp := upload(t, srv.URL, "episode.srt", nil)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Oversized upload status = %d, want %d", resp.StatusCode, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestManager_evict(t *testing.T) {
+	manager, srv := newTestServer(t, &mockProvider{}, Options{Retention: time.Hour})
+
+	job := decodeJob(t, upload(t, srv.URL, "episode.srt", nil))
+	info := waitForStatus(t, manager, job.ID, StatusDone)
+	dir := filepath.Join(manager.WorkDir(), job.ID)
+
+	manager.evict(info.FinishedAt.Add(time.Minute))
+	if _, err := manager.Get(job.ID); err != nil {
+		t.Fatalf("Job evicted before the retention period: %v", err)
+	}
+
+	manager.evict(info.FinishedAt.Add(time.Hour))
+	if _, err := manager.Get(job.ID); err != ErrJobNotFound {
+		t.Errorf("Get() after eviction error = %v, want %v", err, ErrJobNotFound)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Job directory %s was not removed: %v", dir, err)
+	}
+}
+
+// mockProvider prefixes every line with the target language, or blocks until cancelled
+type mockProvider struct {
+	block bool
+}
+
+func (p *mockProvider) GetModels(ctx context.Context) ([]string, error) {
+	return []string{"mock-model"}, nil
+}
+
+func (p *mockProvider) GetTokenLimit(ctx context.Context, modelName string) (int32, error) {
+	return 100000, nil
+}
+
+func (p *mockProvider) CountTokens(ctx context.Context, modelName string, content string) (int32, error) {
+	return int32(len(content)), nil
+}
+
+func (p *mockProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []providers.ContextMessage, config *providers.TranslationConfig) (*providers.TranslationResponse, error) {
+	if p.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	translated := make([]srt.SubtitleObject, len(batch))
+	for i, item := range batch {
+		item.Content = "[" + config.TargetLanguage + "] " + item.Content
+		translated[i] = item
+	}
+	return &providers.TranslationResponse{TranslatedBatch: translated}, nil
+}
+
+func (p *mockProvider) GetName() string {
+	return "mock"
+}
@@ -0,0 +1,225 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/pricing"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// CostEstimate summarizes the requests a translation run would make
+type CostEstimate struct {
+	InputFile    string
+	Lines        int
+	Requests     int
+	InputTokens  int64
+	OutputTokens int64
+	Cost         float64
+	Priced       bool   // false when the model has no entry in the price table
+	PricedAs     string // Table entry of the same model family used when the model has none of its own
+}
+
+// EstimateCost builds every batch the way performTranslation would and counts
+// tokens through the provider without calling TranslateBatch.
+// Output tokens are estimated as the size of the echoed JSON batch; thinking
+// tokens are not included.
+func (t *Translator) EstimateCost(ctx context.Context) (*CostEstimate, error) {
+	if err := t.validatePrerequisites(); err != nil {
+		return nil, err
+	}
+	if err := t.validateConfig(); err != nil {
+		return nil, err
+	}
+
+	priceTable, err := pricing.LoadTable(t.config.PriceTableFile)
+	if err != nil {
+		return nil, errors.NewConfigurationError("failed to load price table", err).WithContext("file_path", t.config.PriceTableFile)
+	}
+
+	// A track extracted by an earlier run belongs to its resumable progress
+	kept := ""
+	if t.isMKVInput() {
+		if _, errStat := os.Stat(t.getExtractedSRTPath()); errStat == nil {
+			kept = t.getExtractedSRTPath()
+		}
+	}
+	srtFile, err := t.prepareSRTFile()
+	if err != nil {
+		return nil, err
+	}
+	defer t.cleanup()
+	if kept != "" {
+		t.cleanupFiles = slices.DeleteFunc(t.cleanupFiles, func(file string) bool { return file == kept })
+	}
+
+	originalData, err := os.ReadFile(srtFile)
+	if err != nil {
+		return nil, errors.NewFileError("failed to read input file", err).WithContext("file_path", srtFile)
+	}
+
+	originalSubtitles, err := t.parseSource(originalData, srtFile)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &CostEstimate{InputFile: t.config.InputFile}
+	if len(originalSubtitles) == 0 {
+		return estimate, nil
+	}
+
+	t.resolveSourceLanguage(originalSubtitles)
+
+	batches, err := t.planBatches(originalSubtitles)
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) == 0 {
+		return estimate, nil
+	}
+
+	// Batches with segments carry an extra section in the instruction
+	thinkingCompatible := strings.Contains(t.config.ModelName, "2.5") || strings.Contains(t.config.ModelName, "gemini-3")
+	instructionTokens := make(map[bool]int32)
+	countInstruction := func(batch []srt.SubtitleObject) (int32, error) {
+		segmented := hasSegments(batch)
+		if tokens, ok := instructionTokens[segmented]; ok {
+			return tokens, nil
+		}
+		instruction, errInstruction := t.translationConfig(batch, len(originalSubtitles), "").Instruction(thinkingCompatible)
+		if errInstruction != nil {
+			return 0, errInstruction
+		}
+		tokens, errCount := t.provider.CountTokens(ctx, t.config.ModelName, instruction)
+		if errCount != nil {
+			return 0, errors.NewAPIError("failed to count tokens", errCount)
+		}
+		instructionTokens[segmented] = tokens
+		return tokens, nil
+	}
+
+	contextMessages := t.resumeContext
+	for _, batch := range batches {
+		guardedBatch := t.withLineGuards(batch)
+		batchInstructionTokens, errInstruction := countInstruction(guardedBatch)
+		if errInstruction != nil {
+			return nil, errInstruction
+		}
+		batchData, errMarshal := json.Marshal(guardedBatch)
+		if errMarshal != nil {
+			return nil, errors.NewTranslationError("failed to marshal batch", errMarshal)
+		}
+
+		var requestText strings.Builder
+		for _, msg := range contextMessages {
+			requestText.WriteString(msg.Content)
+			requestText.WriteString("\n")
+		}
+		requestText.WriteString(string(batchData))
+
+		requestTokens, errCount := t.provider.CountTokens(ctx, t.config.ModelName, requestText.String())
+		if errCount != nil {
+			return nil, errors.NewAPIError("failed to count tokens", errCount)
+		}
+		batchTokens, errCount := t.provider.CountTokens(ctx, t.config.ModelName, string(batchData))
+		if errCount != nil {
+			return nil, errors.NewAPIError("failed to count tokens", errCount)
+		}
+
+		estimate.Requests++
+		estimate.Lines += len(batch)
+		estimate.InputTokens += int64(batchInstructionTokens) + int64(requestTokens)
+		estimate.OutputTokens += int64(batchTokens)
+
+		// The next request carries this batch and its (similarly sized) answer
+		contextMessages = []providers.ContextMessage{
+			{Role: "user", Content: string(batchData)},
+			{Role: "model", Content: string(batchData)},
+		}
+	}
+
+	if price, ok := priceTable.Lookup(t.config.ModelName); ok {
+		estimate.Priced = true
+		estimate.Cost = price.Cost(estimate.InputTokens, estimate.OutputTokens)
+	} else if price, key, ok := priceTable.LookupFamily(t.config.ModelName); ok {
+		estimate.Priced, estimate.PricedAs = true, key
+		estimate.Cost = price.Cost(estimate.InputTokens, estimate.OutputTokens)
+	}
+
+	return estimate, nil
+}
+
+// planBatches builds the batches performTranslation would send: the selected
+// cues of a selective re-translation, or the cues from the line the run would
+// start or resume at, leaving out cues already in the target language
+func (t *Translator) planBatches(subtitles []srt.Subtitle) ([][]srt.SubtitleObject, error) {
+	if t.selection != nil {
+		if _, err := os.Stat(t.outputFile); err != nil {
+			return nil, errors.NewValidationError("selective re-translation requires an existing translated output file", nil).WithContext("output_file", t.outputFile)
+		}
+		indices := t.selection.indices(subtitles)
+		if len(indices) == 0 {
+			return nil, errors.NewValidationError("no subtitle lines match the selection", nil).WithContext("lines", t.config.LineSelection).WithContext("time", t.config.TimeSelection)
+		}
+		var batches [][]srt.SubtitleObject
+		for _, group := range groupSelectedIndices(indices, t.config.BatchSize) {
+			batch := make([]srt.SubtitleObject, 0, len(group))
+			for _, index := range group {
+				batch = append(batch, srt.SubtitleObject{Index: index, Content: subtitles[index].Content})
+			}
+			batches = append(batches, batch)
+		}
+		return batches, nil
+	}
+
+	start := t.plannedStartLine()
+	if start > len(subtitles) {
+		return nil, errors.NewValidationError(fmt.Sprintf("start line must be between 1 and %d", len(subtitles)), nil).WithContext("start_line", start).WithContext("max_lines", len(subtitles))
+	}
+
+	batchSize := t.config.BatchSize
+	if batchSize <= 0 || batchSize > len(subtitles) {
+		batchSize = len(subtitles)
+	}
+
+	keep := t.targetLanguageCues(subtitles)
+	var batches [][]srt.SubtitleObject
+	var batch []srt.SubtitleObject
+	for i := start - 1; i < len(subtitles); i++ {
+		if keep != nil && keep[i] {
+			continue
+		}
+		batch = append(batch, srt.SubtitleObject{Index: i, Content: subtitles[i].Content})
+		if len(batch) == batchSize {
+			batches = append(batches, batch)
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// plannedStartLine returns the line performTranslation would start at without
+// asking: with an existing translated output, the configured start line or the
+// line of verified saved progress, and the first line otherwise
+func (t *Translator) plannedStartLine() int {
+	if _, err := os.Stat(t.outputFile); t.outputFile == "" || err != nil {
+		return 1
+	}
+	if t.config.StartLine == 0 && (t.config.Resume == nil || *t.config.Resume) {
+		if progress := t.verifiedProgress(); progress != nil && progress.Line > 1 {
+			t.restoreProgress(progress)
+			logger.Info(fmt.Sprintf("Estimating the rest of the saved progress from line %d", t.config.StartLine))
+		}
+	}
+	return max(t.config.StartLine, 1)
+}
@@ -0,0 +1,143 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+func TestTranslator_EstimateCost(t *testing.T) {
+	inputPath := filepath.Join(t.TempDir(), "episode.srt")
+	var subtitles []srt.Subtitle
+	for i := 0; i < 5; i++ {
+		subtitles = append(subtitles, srt.Subtitle{
+			Index:   i + 1,
+			Start:   time.Duration(i) * time.Second,
+			End:     time.Duration(i)*time.Second + 500*time.Millisecond,
+			Content: fmt.Sprintf("Line %d", i+1),
+		})
+	}
+	if err := os.WriteFile(inputPath, []byte(srt.ComposeSRT(subtitles)), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	translator := &Translator{
+		config: &config.Config{
+			InputFile:      inputPath,
+			TargetLanguage: "French",
+			ModelName:      "gpt-4o",
+			BatchSize:      2,
+			ThinkingLevel:  "high",
+		},
+		provider: &mockProvider{},
+	}
+
+	estimate, err := translator.EstimateCost(context.Background())
+	if err != nil {
+		t.Fatalf("EstimateCost() failed: %v", err)
+	}
+
+	if estimate.Requests != 3 {
+		t.Errorf("Requests = %d, want 3", estimate.Requests)
+	}
+	if estimate.Lines != 5 {
+		t.Errorf("Lines = %d, want 5", estimate.Lines)
+	}
+	if estimate.InputTokens <= estimate.OutputTokens || estimate.OutputTokens == 0 {
+		t.Errorf("Unexpected token counts: input %d, output %d", estimate.InputTokens, estimate.OutputTokens)
+	}
+	if !estimate.Priced || estimate.Cost <= 0 {
+		t.Errorf("Expected a priced estimate, got %+v", estimate)
+	}
+}
+
+func TestTranslator_EstimateCostKeepsExtractedTrack(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "episode.mkv")
+	extractedPath := filepath.Join(dir, "episode_extracted.srt")
+	if err := os.WriteFile(inputPath, []byte("not a real mkv"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	subtitles := []srt.Subtitle{{Index: 1, Start: time.Second, End: 2 * time.Second, Content: "Hello"}}
+	if err := os.WriteFile(extractedPath, []byte(srt.ComposeSRT(subtitles)), 0644); err != nil {
+		t.Fatalf("Failed to write extracted track: %v", err)
+	}
+
+	translator := &Translator{
+		config: &config.Config{
+			InputFile:      inputPath,
+			TargetLanguage: "French",
+			ModelName:      "gpt-4o",
+			BatchSize:      2,
+			ThinkingLevel:  "high",
+		},
+		provider: &mockProvider{},
+	}
+
+	if _, err := translator.EstimateCost(context.Background()); err != nil {
+		t.Fatalf("EstimateCost() failed: %v", err)
+	}
+	// The track left by an earlier run is needed to resume its progress
+	if _, err := os.Stat(extractedPath); err != nil {
+		t.Errorf("Expected the extracted track to be kept: %v", err)
+	}
+}
+
+func TestTranslator_planBatches(t *testing.T) {
+	subtitles := make([]srt.Subtitle, 7)
+	for i := range subtitles {
+		subtitles[i] = srt.Subtitle{Index: i + 1, Start: time.Duration(i) * time.Second, End: time.Duration(i)*time.Second + 500*time.Millisecond}
+	}
+	outputFile := filepath.Join(t.TempDir(), "episode.fr.srt")
+
+	tests := []struct {
+		name   string
+		config config.Config
+		output bool
+		want   [][]int
+	}{
+		{"start line", config.Config{BatchSize: 3, StartLine: 2}, true, [][]int{{1, 2, 3}, {4, 5, 6}}},
+		{"start line without output", config.Config{BatchSize: 3, StartLine: 2}, false, [][]int{{0, 1, 2}, {3, 4, 5}, {6}}},
+		{"selection", config.Config{BatchSize: 3, LineSelection: "2-3,6"}, true, [][]int{{1, 2}, {5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Remove(outputFile)
+			if tt.output {
+				if err := os.WriteFile(outputFile, []byte(srt.ComposeSRT(subtitles)), 0644); err != nil {
+					t.Fatalf("Failed to write output: %v", err)
+				}
+			}
+			cfg := tt.config
+			translator := &Translator{config: &cfg, outputFile: outputFile}
+			selection, err := parseCueSelection(cfg.LineSelection, cfg.TimeSelection)
+			if err != nil {
+				t.Fatal(err)
+			}
+			translator.selection = selection
+
+			batches, err := translator.planBatches(subtitles)
+			if err != nil {
+				t.Fatalf("planBatches() failed: %v", err)
+			}
+			var got [][]int
+			for _, batch := range batches {
+				var indices []int
+				for _, item := range batch {
+					indices = append(indices, item.Index)
+				}
+				got = append(got, indices)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planBatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package translator
+
+import (
+	"github.com/luispater/gemini-srt-translator-go/internal/events"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// emit writes a progress event tagged with the file being translated
+func (t *Translator) emit(event events.Event) {
+	if !events.Enabled() {
+		return
+	}
+	if !t.headless {
+		event.InputFile = t.config.InputFile
+	}
+	events.Emit(event)
+}
+
+// emitRunStarted announces the lines that are about to be translated
+func (t *Translator) emitRunStarted(done int, total int) {
+	event := events.Event{
+		Type:           events.RunStarted,
+		TargetLanguage: t.config.TargetLanguage,
+		Model:          t.config.ModelName,
+		BatchSize:      t.config.BatchSize,
+		DoneLines:      done,
+		TotalLines:     total,
+	}
+	if !t.headless {
+		event.OutputFile = t.outputFile
+	}
+	if t.provider != nil {
+		event.Provider = t.provider.GetName()
+	}
+	t.emit(event)
+}
+
+// emitBatchCompleted reports a finished batch with the usage it added since before
+func (t *Translator) emitBatchCompleted(batch []srt.SubtitleObject, done int, total int, before providers.Usage) {
+	first, last := batchLines(batch)
+	t.emit(events.Event{
+		Type:           events.BatchCompleted,
+		Batch:          t.batchNumber - 1, // Already advanced to the next batch
+		FirstLine:      first,
+		LastLine:       last,
+		DoneLines:      done,
+		TotalLines:     total,
+		PromptTokens:   t.usage.PromptTokens - before.PromptTokens,
+		OutputTokens:   t.usage.OutputTokens - before.OutputTokens,
+		ThinkingTokens: t.usage.ThinkingTokens - before.ThinkingTokens,
+		CachedTokens:   t.usage.CachedTokens - before.CachedTokens,
+		LatencyMS:      (t.usage.Latency - before.Latency).Milliseconds(),
+	})
+}
+
+// emitRunEnd reports the end of a run with its total usage
+func (t *Translator) emitRunEnd(err error) {
+	event := events.Event{
+		Type:           events.RunFinished,
+		Requests:       t.requestCount,
+		PromptTokens:   t.usage.PromptTokens,
+		OutputTokens:   t.usage.OutputTokens,
+		ThinkingTokens: t.usage.ThinkingTokens,
+		CachedTokens:   t.usage.CachedTokens,
+		LatencyMS:      t.usage.Latency.Milliseconds(),
+	}
+	if err != nil {
+		event.Type = events.RunFailed
+		event.Error = err.Error()
+	}
+	t.emit(event)
+}
+
+// batchLines returns the 1-based first and last line of a batch
+func batchLines(batch []srt.SubtitleObject) (int, int) {
+	if len(batch) == 0 {
+		return 0, 0
+	}
+	return batch[0].Index + 1, batch[len(batch)-1].Index + 1
+}
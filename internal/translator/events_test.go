@@ -0,0 +1,86 @@
+package translator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/events"
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+func TestTranslator_events(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	var buffer bytes.Buffer
+	events.SetOutput(&buffer)
+	defer events.SetOutput(nil)
+
+	inputPath := filepath.Join(t.TempDir(), "episode.srt")
+	var subtitles []srt.Subtitle
+	for i := 0; i < 5; i++ {
+		subtitles = append(subtitles, srt.Subtitle{
+			Index:   i + 1,
+			Start:   time.Duration(i) * time.Second,
+			End:     time.Duration(i)*time.Second + 500*time.Millisecond,
+			Content: "line",
+		})
+	}
+	if err := os.WriteFile(inputPath, []byte(srt.ComposeSRT(subtitles)), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	translator := NewTranslatorWithProvider(&config.Config{
+		InputFile:      inputPath,
+		TargetLanguage: "French",
+		ModelName:      "mock-model",
+		BatchSize:      3,
+		ThinkingLevel:  "high",
+		NonInteractive: true,
+	}, &mockProvider{})
+	if err := translator.Translate(context.Background()); err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+
+	var got []events.Event
+	scanner := bufio.NewScanner(&buffer)
+	for scanner.Scan() {
+		var event events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Invalid event %q: %v", scanner.Text(), err)
+		}
+		got = append(got, event)
+	}
+
+	want := []string{events.RunStarted, events.BatchSent, events.BatchCompleted, events.BatchSent, events.BatchCompleted, events.RunFinished}
+	if len(got) != len(want) {
+		t.Fatalf("Got %d events, want %d: %+v", len(got), len(want), got)
+	}
+	for i, event := range got {
+		if event.Type != want[i] {
+			t.Errorf("event %d type = %s, want %s", i, event.Type, want[i])
+		}
+		if event.InputFile != inputPath {
+			t.Errorf("event %d input file = %q", i, event.InputFile)
+		}
+	}
+
+	if got[0].TotalLines != 5 || got[0].Provider != "mock" {
+		t.Errorf("Unexpected run_started event: %+v", got[0])
+	}
+	last := got[4]
+	if last.Batch != 2 || last.FirstLine != 4 || last.LastLine != 5 || last.DoneLines != 5 || last.TotalLines != 5 {
+		t.Errorf("Unexpected batch_completed event: %+v", last)
+	}
+	if got[5].Requests != 2 {
+		t.Errorf("run_finished requests = %d, want 2", got[5].Requests)
+	}
+}
@@ -0,0 +1,76 @@
+package translator
+
+import (
+	"fmt"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/pkg/languages"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// resolveSourceLanguage takes the source language from the configuration or
+// detects it from the subtitles, and warns when it is the target language
+func (t *Translator) resolveSourceLanguage(subtitles []srt.Subtitle) {
+	t.sourceLanguage, t.sourceCode = t.config.SourceLanguage, ""
+	if language, ok := languages.Lookup(t.config.SourceLanguage); ok {
+		t.sourceCode = language.Tag
+	}
+	if t.sourceLanguage == "" {
+		texts := make([]string, len(subtitles))
+		for i, subtitle := range subtitles {
+			texts[i] = subtitle.Content
+		}
+		detection := languages.DetectAll(texts)
+		if !detection.Reliable() {
+			if !t.headless {
+				logger.Info("Could not detect the source language; the model will identify it.")
+			}
+			return
+		}
+		t.sourceLanguage, t.sourceCode = detection.Name, detection.Code
+		if !t.headless {
+			logger.Info(fmt.Sprintf("Detected source language: %s (%.0f%% confidence)", detection.Name, detection.Confidence*100))
+		}
+	}
+
+	if !t.headless && languages.Matches(t.sourceCode, t.config.TargetLanguage) {
+		logger.Warning(fmt.Sprintf("The source language %s is the same as the target language %s; the subtitles may already be translated.", t.sourceLanguage, t.config.TargetLanguage))
+	}
+}
+
+// targetLanguageCues marks the cues of a mixed-language file that are clearly
+// in the target language. It returns nil when skipping is off, the language of
+// the file is unknown or the whole file is in the target language.
+func (t *Translator) targetLanguageCues(subtitles []srt.Subtitle) []bool {
+	if !t.config.SkipTargetCues || t.sourceCode == "" || languages.Matches(t.sourceCode, t.config.TargetLanguage) {
+		return nil
+	}
+
+	var keep []bool
+	count := 0
+	for i, subtitle := range subtitles {
+		if !languages.CueIn(subtitle.Content, t.config.TargetLanguage, t.sourceCode) {
+			continue
+		}
+		if keep == nil {
+			keep = make([]bool, len(subtitles))
+		}
+		keep[i] = true
+		count++
+	}
+	if count > 0 && !t.headless {
+		logger.Info(fmt.Sprintf("Keeping %d lines that are already in %s.", count, t.config.TargetLanguage))
+	}
+	return keep
+}
+
+// skipKeptCues advances index past cues that keep their original text, marks
+// them completed and returns the next cue to translate
+func (t *Translator) skipKeptCues(keep []bool, index int, originalSubtitles []srt.Subtitle, translatedSubtitles []srt.Subtitle) int {
+	for keep != nil && index < len(keep) && keep[index] {
+		translatedSubtitles[index].Content = originalSubtitles[index].Content
+		t.completed[index] = true
+		index++
+	}
+	return index
+}
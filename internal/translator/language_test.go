@@ -0,0 +1,143 @@
+package translator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// indexRecordingProvider remembers which cues were sent
+type indexRecordingProvider struct {
+	mockProvider
+	sent           []int
+	sourceLanguage string
+}
+
+func (r *indexRecordingProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []providers.ContextMessage, config *providers.TranslationConfig) (*providers.TranslationResponse, error) {
+	for _, item := range batch {
+		r.sent = append(r.sent, item.Index)
+	}
+	r.sourceLanguage = config.SourceLanguage
+	return r.mockProvider.TranslateBatch(ctx, batch, previousContext, config)
+}
+
+func TestTranslator_skipTargetLanguageCues(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	lines := []string{
+		"Where were you last night? I waited for hours.",
+		"I told you, I was working late at the office.",
+		"Je ne sais pas ce que tu veux dire par là, mon ami.",
+		"Nobody believes that story anymore, you know it.",
+		"Il faut partir maintenant, avant qu'ils arrivent ici.",
+		"Then tell me the truth for once in your life.",
+	}
+	var subtitles []srt.Subtitle
+	for i, line := range lines {
+		subtitles = append(subtitles, srt.Subtitle{
+			Index:   i + 1,
+			Start:   time.Duration(i) * time.Second,
+			End:     time.Duration(i)*time.Second + 500*time.Millisecond,
+			Content: line,
+		})
+	}
+
+	tests := []struct {
+		name string
+		skip bool
+		want []int
+	}{
+		{"skip", true, []int{0, 1, 3, 5}},
+		{"translate all", false, []int{0, 1, 2, 3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputPath := filepath.Join(t.TempDir(), "episode.srt")
+			if err := os.WriteFile(inputPath, []byte(srt.ComposeSRT(subtitles)), 0644); err != nil {
+				t.Fatalf("Failed to write input: %v", err)
+			}
+
+			provider := &indexRecordingProvider{}
+			translator := NewTranslatorWithProvider(&config.Config{
+				InputFile:      inputPath,
+				TargetLanguage: "French",
+				ModelName:      "mock-model",
+				BatchSize:      2,
+				ThinkingLevel:  "high",
+				NonInteractive: true,
+				SkipTargetCues: tt.skip,
+			}, provider)
+			if err := translator.Translate(context.Background()); err != nil {
+				t.Fatalf("Translate() failed: %v", err)
+			}
+
+			if len(provider.sent) != len(tt.want) {
+				t.Fatalf("Sent cues %v, want %v", provider.sent, tt.want)
+			}
+			for i := range tt.want {
+				if provider.sent[i] != tt.want[i] {
+					t.Fatalf("Sent cues %v, want %v", provider.sent, tt.want)
+				}
+			}
+			if provider.sourceLanguage != "English" {
+				t.Errorf("Source language = %q, want English", provider.sourceLanguage)
+			}
+
+			data, err := os.ReadFile(translator.outputFile)
+			if err != nil {
+				t.Fatalf("Failed to read output: %v", err)
+			}
+			translated, err := srt.ParseSRT(string(data))
+			if err != nil || len(translated) != len(lines) || translated[4].Content != lines[4] {
+				t.Errorf("Output does not keep the French cue: %v, %+v", err, translated)
+			}
+		})
+	}
+}
+
+func TestTranslator_skipTargetLanguageCues_foreignNames(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	lines := []string{
+		"Where were you last night? I waited for hours.",
+		"Senator Alvarez has arrived.",
+		"Welcome to the Hotel Bellagio, Señor Garcia.",
+		"We ate paella at Casa Lucio in Madrid.",
+		"Then tell me the truth for once in your life.",
+	}
+	var subtitles []srt.Subtitle
+	for i, line := range lines {
+		subtitles = append(subtitles, srt.Subtitle{Index: i + 1, Start: time.Duration(i) * time.Second, End: time.Duration(i)*time.Second + 500*time.Millisecond, Content: line})
+	}
+	inputPath := filepath.Join(t.TempDir(), "episode.srt")
+	if err := os.WriteFile(inputPath, []byte(srt.ComposeSRT(subtitles)), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	provider := &indexRecordingProvider{}
+	translator := NewTranslatorWithProvider(&config.Config{
+		InputFile:      inputPath,
+		TargetLanguage: "Spanish",
+		ModelName:      "mock-model",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+		NonInteractive: true,
+		SkipTargetCues: true,
+	}, provider)
+	if err := translator.Translate(context.Background()); err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+	if len(provider.sent) != len(lines) {
+		t.Errorf("Sent cues %v, want all %d English cues", provider.sent, len(lines))
+	}
+}
@@ -0,0 +1,106 @@
+package translator
+
+import (
+	"context"
+	stdErrors "errors"
+	"strings"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/interrupt"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// TranslateSubtitles translates subtitles in memory. It reads and writes no
+// files and prints nothing; progress is only reported through the callback
+// registered with SetProgressFunc. Use a non-interactive configuration or
+// decider so no question is asked on the terminal.
+func (t *Translator) TranslateSubtitles(ctx context.Context, subtitles []srt.Subtitle) (_ []srt.Subtitle, err error) {
+	t.headless = true
+	defer func() { t.emitRunEnd(err) }()
+
+	if err := t.validatePrerequisites(); err != nil {
+		return nil, err
+	}
+	if err := t.validateModelOptions(); err != nil {
+		return nil, err
+	}
+	if err := t.parseTiming(); err != nil {
+		return nil, err
+	}
+	if t.config.BatchSize <= 0 {
+		return nil, errors.NewConfigurationError("batch size must be a positive integer", nil).WithContext("batch_size", t.config.BatchSize)
+	}
+	if err := t.validateModel(ctx); err != nil {
+		return nil, err
+	}
+	if err := t.getTokenLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	subtitles = t.stripSDH(subtitles, "")
+	translatedSubtitles := make([]srt.Subtitle, len(subtitles))
+	copy(translatedSubtitles, subtitles)
+	if err := srt.ApplyTiming(translatedSubtitles, t.timingPre); err != nil {
+		return nil, errors.NewConfigurationError("failed to apply timing steps", err)
+	}
+	if len(subtitles) == 0 {
+		return translatedSubtitles, nil
+	}
+	t.resolveSourceLanguage(subtitles)
+
+	// Same pacing as the CLI for pro models on the free quota (only for Gemini)
+	delay := t.provider.GetName() == "gemini" && strings.Contains(t.config.ModelName, "pro") && t.config.FreeQuota
+	delayTime := 15 * time.Second
+
+	total := len(subtitles)
+	t.startedAt = time.Now()
+	t.reportProgress(0, total)
+	t.emitRunStarted(0, total)
+
+	for start := 0; start < total; {
+		end := min(start+t.config.BatchSize, total)
+
+		batch := make([]srt.SubtitleObject, 0, end-start)
+		for i := start; i < end; i++ {
+			batch = append(batch, srt.SubtitleObject{
+				Index:   i,
+				Content: subtitles[i].Content,
+			})
+		}
+
+		guardedBatch := t.withLineGuards(batch)
+		if err := t.validateTokenSize(ctx, guardedBatch); err != nil {
+			if stdErrors.Is(err, errBatchTooLarge) && t.config.BatchSize < len(batch) {
+				// Retry the same start with the reduced batch size
+				continue
+			}
+			return nil, err
+		}
+
+		startTime := time.Now()
+		usageBefore := t.usage
+		newContext, err := t.processBatch(ctx, guardedBatch, translatedSubtitles, nil)
+		if err != nil {
+			return nil, err
+		}
+		t.context = newContext
+		t.reportProgress(end, total)
+		t.emitBatchCompleted(batch, end, total, usageBefore)
+		start = end
+
+		if start < total && interrupt.Requested(ctx) {
+			return nil, errors.NewTranslationError("translation interrupted", interrupt.ErrStopped).WithContext("translated_lines", start)
+		}
+
+		if delay && end < total {
+			if elapsed := time.Since(startTime); elapsed < delayTime {
+				if err = waitOrStop(ctx, delayTime-elapsed); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return t.applyTimingPost(translatedSubtitles)
+}
@@ -0,0 +1,194 @@
+package translator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/languages"
+)
+
+// TrackFlags are the subtitle track flags available to output templates
+var TrackFlags = []string{"forced", "sdh", "default", "ai"}
+
+// outputVariable matches {name} and {.name}; the dotted form expands to
+// ".value", or to nothing when the value is empty
+var outputVariable = regexp.MustCompile(`\{(\.?)([a-z0-9_]+)\}`)
+
+// outputVariables returns the values of the output template variables for the
+// input file and target language of cfg
+func outputVariables(cfg *config.Config) map[string]string {
+	dir := filepath.Dir(cfg.InputFile)
+	if cfg.OutputDir != "" {
+		dir = cfg.OutputDir
+	}
+	name := strings.TrimSuffix(filepath.Base(cfg.InputFile), filepath.Ext(cfg.InputFile))
+	variables := map[string]string{
+		"dir":  dir,
+		"name": name,
+		"stem": fileStem(name),
+		"ext":  "srt",
+	}
+
+	if language, ok := languages.Lookup(cfg.TargetLanguage); ok {
+		iso6391 := language.ISO6391
+		if iso6391 == "" {
+			iso6391 = language.MatroskaCode()
+		}
+		variables["lang"] = language.Tag
+		variables["lang_bcp47"] = language.Tag
+		variables["lang_iso639_1"] = iso6391
+		variables["lang_iso639_2"] = language.MatroskaCode()
+		variables["lang_iso639_2t"] = firstNonEmpty(language.ISO6392T, language.MatroskaCode())
+		variables["lang_iso639_3"] = firstNonEmpty(language.ISO6393, language.MatroskaCode())
+		variables["lang_plex"] = iso6391
+		variables["lang_jellyfin"] = language.MatroskaCode()
+		variables["lang_name"] = language.Name
+	} else {
+		// Unknown languages are spelled the same way in every style
+		code := strings.Join(strings.Fields(strings.ToLower(cfg.TargetLanguage)), "-")
+		for _, key := range []string{"lang", "lang_bcp47", "lang_iso639_1", "lang_iso639_2", "lang_iso639_2t", "lang_iso639_3", "lang_plex", "lang_jellyfin"} {
+			variables[key] = code
+		}
+		variables["lang_name"] = cfg.TargetLanguage
+	}
+
+	for _, flag := range TrackFlags {
+		variables[flag] = ""
+	}
+	for _, flag := range trackFlags(cfg) {
+		variables[flag] = flag
+	}
+	return variables
+}
+
+// trackFlags returns the flags of the translated track: the configured ones,
+// those in the input file name (movie.en.forced.srt) and ai
+func trackFlags(cfg *config.Config) []string {
+	flags := []string{"ai"}
+	add := func(flag string) {
+		if flag == "cc" || flag == "hi" {
+			flag = "sdh"
+		}
+		if slices.Contains(TrackFlags, flag) && !slices.Contains(flags, flag) {
+			flags = append(flags, flag)
+		}
+	}
+	for _, flag := range cfg.TrackFlags {
+		add(strings.ToLower(strings.TrimSpace(flag)))
+	}
+	name := strings.TrimSuffix(filepath.Base(cfg.InputFile), filepath.Ext(cfg.InputFile))
+	for _, part := range strings.Split(name, ".")[1:] {
+		add(strings.ToLower(part))
+	}
+	return flags
+}
+
+// fileStem returns a file name without the language and track flag parts
+// that end it, so Movie.en.forced.cc gives Movie
+func fileStem(name string) string {
+	parts := strings.Split(name, ".")
+	for len(parts) > 1 {
+		last := strings.ToLower(parts[len(parts)-1])
+		if !slices.Contains(TrackFlags, last) && last != "cc" && last != "hi" && !isLanguageCode(last) {
+			break
+		}
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, ".")
+}
+
+// isLanguageCode reports whether a file name part is a language tag or code,
+// such as en, pt-BR or fre, and not just close to a language name
+func isLanguageCode(part string) bool {
+	language, ok := languages.Lookup(part)
+	if !ok {
+		return false
+	}
+	base, _, _ := strings.Cut(part, "-")
+	for _, code := range []string{language.Base(), language.ISO6391, language.ISO6392B, language.ISO6392T, language.ISO6393, language.MatroskaCode()} {
+		if code != "" && strings.EqualFold(base, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderOutputTemplate expands the variables of an output template
+func renderOutputTemplate(template string, variables map[string]string) string {
+	path := outputVariable.ReplaceAllStringFunc(template, func(match string) string {
+		parts := outputVariable.FindStringSubmatch(match)
+		value, ok := variables[parts[2]]
+		switch {
+		case !ok:
+			return match
+		case parts[1] == "." && value != "":
+			return "." + value
+		case parts[1] == ".":
+			return ""
+		}
+		return value
+	})
+	return filepath.Clean(path)
+}
+
+// defaultOutputPath names the output <name>.<lang>.srt, or <name>_translated.srt
+// when the target language is not in the registry
+func defaultOutputPath(cfg *config.Config) string {
+	template := "{dir}/{name}.{lang}.{ext}"
+	if _, ok := languages.Lookup(cfg.TargetLanguage); !ok {
+		template = "{dir}/{name}_translated.{ext}"
+	}
+	return renderOutputTemplate(template, outputVariables(cfg))
+}
+
+// ValidateOutputTemplate checks that an output template only uses known
+// variables and names every input differently, through {name} or {stem}
+func ValidateOutputTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	variables := outputVariables(&config.Config{TargetLanguage: "en"})
+	for _, parts := range outputVariable.FindAllStringSubmatch(template, -1) {
+		if _, ok := variables[parts[2]]; !ok {
+			names := make([]string, 0, len(variables))
+			for name := range variables {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			return errors.NewValidationError(fmt.Sprintf("unknown output template variable {%s}", parts[2]), nil).
+				WithContext("template", template).
+				WithContext("variables", strings.Join(names, ", "))
+		}
+	}
+	if !strings.Contains(template, "{name}") && !strings.Contains(template, "{stem}") {
+		return errors.NewValidationError("output template must contain {name} or {stem}", nil).WithContext("template", template)
+	}
+	return nil
+}
+
+// validateTrackFlags checks the configured track flags
+func validateTrackFlags(flags []string) error {
+	for _, flag := range flags {
+		switch strings.ToLower(strings.TrimSpace(flag)) {
+		case "forced", "sdh", "cc", "hi", "default":
+		default:
+			return errors.NewValidationError(fmt.Sprintf("unknown track flag %q, use forced, sdh or default", flag), nil)
+		}
+	}
+	return nil
+}
+
+// firstNonEmpty returns the first value that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package translator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+)
+
+func TestNewTranslator_outputTemplate(t *testing.T) {
+	input := filepath.Join("media", "Movie (2024).srt")
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want string
+	}{
+		{"default", config.Config{TargetLanguage: "Simplified Chinese"}, "media/Movie (2024).zh-Hans.srt"},
+		{"unknown language", config.Config{TargetLanguage: "Klingon"}, "media/Movie (2024)_translated.srt"},
+		{"output dir", config.Config{TargetLanguage: "pt-BR", OutputDir: "out"}, "out/Movie (2024).pt-BR.srt"},
+		{
+			"plex with flags",
+			config.Config{TargetLanguage: "German", OutputTemplate: "{dir}/{name}.{lang_plex}{.forced}{.sdh}.{ext}", TrackFlags: []string{"forced"}},
+			"media/Movie (2024).de.forced.srt",
+		},
+		{
+			"bcp47 with ai",
+			config.Config{TargetLanguage: "Traditional Chinese", OutputTemplate: "{dir}/{name}.{lang_bcp47}{.forced}{.ai}.{ext}"},
+			"media/Movie (2024).zh-Hant.ai.srt",
+		},
+		{
+			"iso 639 styles",
+			config.Config{TargetLanguage: "French", OutputTemplate: "{dir}/{lang_name}/{name}.{lang_iso639_1}.{lang_iso639_2}.{lang_iso639_2t}.{ext}"},
+			"media/French/Movie (2024).fr.fre.fra.srt",
+		},
+		{
+			"explicit output file wins",
+			config.Config{TargetLanguage: "French", OutputFile: "custom.srt", OutputTemplate: "{dir}/{name}.x.{ext}"},
+			"custom.srt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.InputFile = input
+			got := NewTranslatorWithProvider(&cfg, nil).OutputFile()
+			if got != filepath.FromSlash(tt.want) {
+				t.Errorf("OutputFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackFlagsFromFileName(t *testing.T) {
+	cfg := &config.Config{InputFile: "Movie.en.forced.cc.srt", TargetLanguage: "French", OutputTemplate: "{stem}.{lang}{.forced}{.sdh}{.default}.{ext}"}
+	if got := NewTranslatorWithProvider(cfg, nil).OutputFile(); got != "Movie.fr.forced.sdh.srt" {
+		t.Errorf("OutputFile() = %q", got)
+	}
+}
+
+func TestFileStem(t *testing.T) {
+	tests := map[string]string{
+		"Movie.en.forced.cc":      "Movie",
+		"Movie (2024).pt-BR.sdh":  "Movie (2024)",
+		"Show.S01E01.eng":         "Show.S01E01",
+		"Show.S01E01.Portugese":   "Show.S01E01.Portugese",
+		"Mr. Robot.S01E01.hi":     "Mr. Robot.S01E01",
+		"en":                      "en",
+		"The.Office.S02E03.1080p": "The.Office.S02E03.1080p",
+	}
+	for name, want := range tests {
+		if got := fileStem(name); got != want {
+			t.Errorf("fileStem(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestTranslator_outputOverwritesInput(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "Movie.srt")
+	if err := os.WriteFile(input, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	translator := NewTranslatorWithProvider(&config.Config{InputFile: input, TargetLanguage: "French", ThinkingLevel: "high", OutputTemplate: "{dir}/{name}.{ext}"}, nil)
+	if err := translator.validateConfig(); err == nil || !strings.Contains(err.Error(), "overwrite the input") {
+		t.Errorf("validateConfig() error = %v, want an overwrite error", err)
+	}
+}
+
+func TestValidateOutputTemplate(t *testing.T) {
+	for _, template := range []string{"", "{dir}/{name}.{lang_bcp47}{.forced}{.ai}.{ext}", "{name}.{lang_jellyfin}.srt", "{dir}/{stem}.{lang_plex}.{ext}"} {
+		if err := ValidateOutputTemplate(template); err != nil {
+			t.Errorf("ValidateOutputTemplate(%q) failed: %v", template, err)
+		}
+	}
+	for _, template := range []string{"{dir}/{name}.{language}.srt", "{dir}/subtitle.{lang}.srt"} {
+		if err := ValidateOutputTemplate(template); err == nil {
+			t.Errorf("ValidateOutputTemplate(%q) succeeded, want an error", template)
+		}
+	}
+	if err := validateTrackFlags([]string{"forced", "SDH"}); err != nil {
+		t.Errorf("validateTrackFlags() failed: %v", err)
+	}
+	if err := validateTrackFlags([]string{"commentary"}); err == nil {
+		t.Error("validateTrackFlags() succeeded for an unknown flag")
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "movie.srt")
+	if err := WriteFileAtomic(path, []byte("new")); err != nil {
+		t.Fatalf("WriteFileAtomic() failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Fatalf("new file mode = %v, want 0644", info.Mode().Perm())
+	}
+
+	// A replaced file keeps its permissions
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte("replaced")); err != nil {
+		t.Fatalf("WriteFileAtomic() failed: %v", err)
+	}
+	if info, err = os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("replaced file mode = %v, want 0600", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "replaced" {
+		t.Errorf("content = %q, want %q", data, "replaced")
+	}
+}
@@ -0,0 +1,48 @@
+package translator
+
+import (
+	"fmt"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// maxLoggedDiagnostics is how many parsing problems are printed before the
+// rest are only counted
+const maxLoggedDiagnostics = 5
+
+// parseSource parses the source subtitles in the input encoding, keeps their
+// layout for PreserveFormat and strips them with StripSDH. Problems are
+// repaired and printed as warnings, or fail the run with StrictParsing.
+func (t *Translator) parseSource(data []byte, path string) ([]srt.Subtitle, error) {
+	// Subtitles extracted from MKV files were already converted to UTF-8
+	charset := t.config.InputEncoding
+	if t.isMKVInput() {
+		charset = "UTF-8"
+	}
+	subtitles, layout, diagnostics, err := srt.ParseLayout(data, charset)
+	if err != nil {
+		return nil, errors.NewFileError("failed to parse SRT file", err).WithContext("file_path", path)
+	}
+	t.sourceLayout = nil
+	if t.config.PreserveFormat {
+		t.sourceLayout = layout
+	}
+	if t.config.StrictParsing && len(diagnostics) > 0 {
+		return nil, errors.NewValidationError("the subtitle file has problems (see gst lint)", &srt.ParseError{Diagnostics: diagnostics}).WithContext("file_path", path)
+	}
+
+	subtitles = t.stripSDH(subtitles, path)
+
+	if len(diagnostics) == 0 || t.headless {
+		return subtitles, nil
+	}
+	for _, diagnostic := range diagnostics[:min(len(diagnostics), maxLoggedDiagnostics)] {
+		logger.Warning(fmt.Sprintf("%s: %s", path, diagnostic))
+	}
+	if len(diagnostics) > maxLoggedDiagnostics {
+		logger.Warning(fmt.Sprintf("%s: %d more problems; run gst lint for the full list, or use --strict to stop on them.", path, len(diagnostics)-maxLoggedDiagnostics))
+	}
+	return subtitles, nil
+}
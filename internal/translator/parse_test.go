@@ -0,0 +1,161 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+func TestTranslator_strictParsing(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	// The second cue has no number
+	source := "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n00:00:03,000 --> 00:00:04,000\nWorld\n"
+
+	for _, strict := range []bool{false, true} {
+		inputPath := filepath.Join(t.TempDir(), "episode.srt")
+		if err := os.WriteFile(inputPath, []byte(source), 0644); err != nil {
+			t.Fatalf("Failed to write input: %v", err)
+		}
+
+		provider := &indexRecordingProvider{}
+		translator := NewTranslatorWithProvider(&config.Config{
+			InputFile:      inputPath,
+			TargetLanguage: "French",
+			ModelName:      "mock-model",
+			BatchSize:      10,
+			ThinkingLevel:  "high",
+			NonInteractive: true,
+			StrictParsing:  strict,
+		}, provider)
+		err := translator.Translate(context.Background())
+
+		switch {
+		case strict && err == nil:
+			t.Error("Translate() succeeded in strict mode")
+		case !strict && err != nil:
+			t.Errorf("Translate() failed: %v", err)
+		case !strict && len(provider.sent) != 2:
+			t.Errorf("Translated cues %v, want both", provider.sent)
+		}
+	}
+}
+
+func TestTranslator_encodings(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	source, err := srt.EncodeText("1\n00:00:01,000 --> 00:00:02,000\n你昨天晚上去哪儿了？我等了你好几个小时。\n", "gbk", false)
+	if err != nil {
+		t.Fatalf("EncodeText() failed: %v", err)
+	}
+	inputPath := filepath.Join(t.TempDir(), "episode.srt")
+	if err = os.WriteFile(inputPath, source, 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	translator := NewTranslatorWithProvider(&config.Config{
+		InputFile:      inputPath,
+		TargetLanguage: "French",
+		ModelName:      "mock-model",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+		NonInteractive: true,
+		OutputEncoding: "utf-16le",
+		OutputBOM:      true,
+	}, &mockProvider{})
+	if err = translator.Translate(context.Background()); err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(translator.OutputFile())
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	text, charset, err := srt.DecodeText(data, "")
+	if err != nil || charset != "UTF-16LE" || !bytes.HasPrefix(data, []byte{0xFF, 0xFE}) || !strings.Contains(text, "你昨天晚上去哪儿了") {
+		t.Errorf("Unexpected output %s: %q (%v)", charset, text, err)
+	}
+
+	translator = NewTranslatorWithProvider(&config.Config{
+		InputFile:      inputPath,
+		TargetLanguage: "French",
+		ThinkingLevel:  "high",
+		OutputEncoding: "gbk",
+		OutputBOM:      true,
+	}, &mockProvider{})
+	if err = translator.validateConfig(); err == nil {
+		t.Error("validateConfig() accepted a byte order mark for GBK")
+	}
+}
+
+func TestTranslator_preserveFormat(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	// A Windows-1252 file with CRLF line endings, loose timestamps, trailing
+	// spaces, odd cue numbers and an empty cue
+	source, err := srt.EncodeText("001\r\n00:00:01.5 --> 00:00:03,000  \r\nÇa va très bien, merci.  \r\n\r\n\r\n7\r\n00:00:04,000 --> 00:00:06,000\r\nL'été à Noël, déjà fini ?\r\n\r\n8\r\n00:00:07,000 --> 00:00:08,000\r\n\r\n9\r\n00:00:09,000 --> 00:00:10,000\r\nÀ bientôt.\r\n", "windows-1252", false)
+	if err != nil {
+		t.Fatalf("EncodeText() failed: %v", err)
+	}
+
+	for _, preserve := range []bool{false, true} {
+		inputPath := filepath.Join(t.TempDir(), "episode.srt")
+		if err = os.WriteFile(inputPath, source, 0644); err != nil {
+			t.Fatalf("Failed to write input: %v", err)
+		}
+		outputEncoding := "utf-8"
+		if preserve {
+			outputEncoding = ""
+		}
+
+		// The mock provider returns every cue untranslated
+		translator := NewTranslatorWithProvider(&config.Config{
+			InputFile:      inputPath,
+			TargetLanguage: "French",
+			ModelName:      "mock-model",
+			BatchSize:      10,
+			ThinkingLevel:  "high",
+			NonInteractive: true,
+			OutputEncoding: outputEncoding,
+			PreserveFormat: preserve,
+		}, &mockProvider{})
+		if err = translator.Translate(context.Background()); err != nil {
+			t.Fatalf("Translate() failed: %v", err)
+		}
+
+		data, err := os.ReadFile(translator.OutputFile())
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		if identical := bytes.Equal(data, source); identical != preserve {
+			t.Errorf("PreserveFormat %v: output identical to the input = %v, output %q", preserve, identical, data)
+		}
+	}
+}
+
+func TestTranslator_preserveFormatEmptyTranslation(t *testing.T) {
+	batch := []srt.SubtitleObject{{Index: 0, Content: "..."}}
+	for _, tt := range []struct {
+		preserve bool
+		want     string
+	}{{false, " "}, {true, "..."}} {
+		translator := NewTranslatorWithProvider(&config.Config{TargetLanguage: "French", PreserveFormat: tt.preserve}, nil)
+		translated := []srt.Subtitle{{Index: 1, Content: "..."}}
+		if err := translator.processTranslatedLines([]srt.SubtitleObject{{Index: 0, Content: ""}}, translated, batch); err != nil {
+			t.Fatalf("processTranslatedLines() failed: %v", err)
+		}
+		if translated[0].Content != tt.want {
+			t.Errorf("PreserveFormat %v: content = %q, want %q", tt.preserve, translated[0].Content, tt.want)
+		}
+	}
+}
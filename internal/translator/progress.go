@@ -0,0 +1,337 @@
+package translator
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// progressVersion is the current .progress file format version. Version 1
+// files only stored the line and the input file, version 2 files did not
+// store the settings.
+const progressVersion = 3
+
+// ProgressInfo stores information about translation progress
+type ProgressInfo struct {
+	Version        int                        `json:"version"`
+	Line           int                        `json:"line"`
+	InputFile      string                     `json:"input_file"`
+	SourceHash     string                     `json:"source_hash,omitempty"`     // SHA-256 of the translated subtitle source
+	TargetLanguage string                     `json:"target_language,omitempty"` // Language the output is translated into
+	Provider       string                     `json:"provider,omitempty"`
+	Model          string                     `json:"model,omitempty"`
+	PromptHash     string                     `json:"prompt_hash,omitempty"` // SHA-256 of the instruction and description
+	Settings       map[string]string          `json:"settings,omitempty"`    // Options that change the cues sent and written
+	BatchSize      int                        `json:"batch_size,omitempty"`  // Batch size in use, after any shrinking
+	TotalLines     int                        `json:"total_lines,omitempty"`
+	Completed      string                     `json:"completed,omitempty"` // Base64 bitmap of translated lines, bit i is line i+1
+	Context        []providers.ContextMessage `json:"context,omitempty"`   // Context messages of the last translated batch
+}
+
+// checkSavedProgress checks for saved progress and asks whether to resume.
+// Progress that does not match the current run is ignored with the reasons.
+func (t *Translator) checkSavedProgress() error {
+	if t.progressFile == "" || t.config.StartLine != 0 || t.selection != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(t.progressFile)
+	if err != nil {
+		return nil
+	}
+
+	var progress ProgressInfo
+	if err = json.Unmarshal(data, &progress); err != nil {
+		logger.Warning(fmt.Sprintf("Error reading progress file: %v", err))
+		return nil
+	}
+
+	if reasons := t.progressMismatches(&progress); len(reasons) > 0 {
+		logger.Warning(fmt.Sprintf("Ignoring saved progress in %s:", t.progressFile))
+		for _, reason := range reasons {
+			logger.Warning("  - " + reason)
+		}
+		t.progressRejection = errors.NewValidationError("saved progress was rejected: "+strings.Join(reasons, "; "), nil).WithContext("progress_file", t.progressFile)
+		return nil
+	}
+
+	// Progress saved before the first batch finished has nothing to resume,
+	// but the output it belongs to is continued rather than refused
+	if progress.Line <= 1 && (t.config.Resume == nil || *t.config.Resume) {
+		t.restoreProgress(&progress)
+		logger.Info("No batch of the saved progress was finished, starting from line 1")
+		return nil
+	}
+
+	if progress.Line > 1 {
+		var resume bool
+		if t.config.Resume != nil {
+			resume = *t.config.Resume
+		} else if resume, err = t.decider.ResumeProgress(progress.Line); err != nil {
+			return err
+		}
+
+		if resume {
+			t.restoreProgress(&progress)
+			logger.Info(fmt.Sprintf("Resuming from line %d", t.config.StartLine))
+		} else {
+			logger.Info("Starting from the beginning")
+			// Remove the existing output file
+			if err = os.Remove(t.outputFile); err != nil && !os.IsNotExist(err) {
+				logger.Warning(fmt.Sprintf("Failed to remove output file: %v", err))
+			}
+			// Remove existing progress file
+			if err = os.Remove(t.progressFile); err != nil && !os.IsNotExist(err) {
+				logger.Warning(fmt.Sprintf("Failed to remove progress file: %v", err))
+			}
+			// For MKV files, also remove extracted SRT file when restarting
+			if t.isMKVInput() {
+				extractedPath := t.getExtractedSRTPath()
+				if err = os.Remove(extractedPath); err != nil && !os.IsNotExist(err) {
+					logger.Warning(fmt.Sprintf("Failed to remove extracted SRT file: %v", err))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// progressMismatches lists every reason why saved progress cannot be resumed
+// by the current run, or nil when it matches
+func (t *Translator) progressMismatches(progress *ProgressInfo) []string {
+	if progress.Version < progressVersion {
+		return []string{"the progress file was written by an older version that does not record the source, language, model and settings, so it cannot be verified"}
+	}
+	if progress.Version > progressVersion {
+		return []string{fmt.Sprintf("the progress file format version %d is newer than the supported version %d", progress.Version, progressVersion)}
+	}
+
+	var reasons []string
+	if progress.InputFile != t.config.InputFile {
+		reasons = append(reasons, fmt.Sprintf("it belongs to a different input file (%s)", progress.InputFile))
+	}
+
+	sourcePath := t.subtitleSourcePath()
+	if source, err := os.ReadFile(sourcePath); err != nil {
+		reasons = append(reasons, fmt.Sprintf("the subtitle source %s cannot be read to verify it", sourcePath))
+	} else if hashBytes(source) != progress.SourceHash {
+		reasons = append(reasons, fmt.Sprintf("the subtitle source %s changed since the progress was saved", sourcePath))
+	}
+
+	if !strings.EqualFold(progress.TargetLanguage, t.config.TargetLanguage) {
+		reasons = append(reasons, fmt.Sprintf("the target language was %q, now %q", progress.TargetLanguage, t.config.TargetLanguage))
+	}
+	if t.provider != nil && progress.Provider != t.provider.GetName() {
+		reasons = append(reasons, fmt.Sprintf("the provider was %q, now %q", progress.Provider, t.provider.GetName()))
+	}
+	if progress.Model != t.config.ModelName {
+		reasons = append(reasons, fmt.Sprintf("the model was %q, now %q", progress.Model, t.config.ModelName))
+	}
+	if progress.PromptHash != t.promptHash() {
+		reasons = append(reasons, "the translation instructions or --description changed")
+	}
+	settings := t.progressSettings()
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if saved, ok := progress.Settings[name]; !ok || saved != settings[name] {
+			reasons = append(reasons, fmt.Sprintf("--%s was %q, now %q", name, saved, settings[name]))
+		}
+	}
+
+	if progress.TotalLines < 1 || progress.Line < 1 || progress.Line > progress.TotalLines+1 {
+		reasons = append(reasons, fmt.Sprintf("the saved line %d is outside the %d lines of the source", progress.Line, progress.TotalLines))
+	} else if _, err := decodeCompletion(progress.Completed, progress.TotalLines); err != nil {
+		reasons = append(reasons, fmt.Sprintf("the completion map is damaged: %v", err))
+	}
+	return reasons
+}
+
+// verifiedProgress reads the saved progress without asking or warning,
+// returning nil when there is none or it does not match the current run
+func (t *Translator) verifiedProgress() *ProgressInfo {
+	if t.progressFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(t.progressFile)
+	if err != nil {
+		return nil
+	}
+	var progress ProgressInfo
+	if err = json.Unmarshal(data, &progress); err != nil || len(t.progressMismatches(&progress)) > 0 {
+		return nil
+	}
+	return &progress
+}
+
+// restoreProgress continues from verified saved progress: the first line that
+// is not translated yet, the last context and a batch size that was shrunk
+func (t *Translator) restoreProgress(progress *ProgressInfo) {
+	completed, _ := decodeCompletion(progress.Completed, progress.TotalLines)
+	t.completed = completed
+
+	t.config.StartLine = progress.Line
+	for i, done := range completed {
+		if !done && i+1 < t.config.StartLine {
+			t.config.StartLine = i + 1
+			break
+		}
+	}
+	if t.config.StartLine > progress.TotalLines {
+		t.config.StartLine = progress.TotalLines
+	}
+
+	t.resumeContext = progress.Context
+
+	if progress.BatchSize > 0 && progress.BatchSize < t.config.BatchSize {
+		logger.Info(fmt.Sprintf("Using batch size %d from the saved progress", progress.BatchSize))
+		t.config.BatchSize = progress.BatchSize
+	}
+}
+
+// saveProgress saves current progress to file
+func (t *Translator) saveProgress(line int, translatedSubtitles []srt.Subtitle) {
+	if t.progressFile == "" {
+		return
+	}
+
+	progress := ProgressInfo{
+		Version:        progressVersion,
+		Line:           line,
+		InputFile:      t.config.InputFile,
+		SourceHash:     t.sourceHash,
+		TargetLanguage: t.config.TargetLanguage,
+		Model:          t.config.ModelName,
+		PromptHash:     t.promptHash(),
+		Settings:       t.progressSettings(),
+		BatchSize:      t.config.BatchSize,
+		TotalLines:     len(translatedSubtitles),
+		Completed:      encodeCompletion(t.completed),
+		Context:        t.context,
+	}
+	if t.provider != nil {
+		progress.Provider = t.provider.GetName()
+	}
+
+	data, err := json.Marshal(progress)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Failed to marshal progress: %v", err))
+		return
+	}
+
+	// Write translated subtitles to the file
+	if err = t.writeOutput(translatedSubtitles); err != nil {
+		logger.Warning(fmt.Sprintf("failed to write output file: %v", err))
+	}
+
+	if err = WriteFileAtomic(t.progressFile, data); err != nil {
+		logger.Warning(fmt.Sprintf("Failed to save progress: %v", err))
+	}
+}
+
+// markCompleted records translated lines in the completion map
+func (t *Translator) markCompleted(batch []srt.SubtitleObject) {
+	for _, item := range batch {
+		if item.Index >= 0 && item.Index < len(t.completed) {
+			t.completed[item.Index] = true
+		}
+	}
+}
+
+// subtitleSourcePath returns the SRT file that is translated, which is the
+// extracted subtitle file for MKV input
+func (t *Translator) subtitleSourcePath() string {
+	if t.isMKVInput() {
+		return t.getExtractedSRTPath()
+	}
+	return t.config.InputFile
+}
+
+// isMKVInput reports whether the input is an MKV video
+func (t *Translator) isMKVInput() bool {
+	return strings.HasSuffix(strings.ToLower(t.config.InputFile), ".mkv")
+}
+
+// promptHash fingerprints the instruction sent to the model, so progress is
+// not resumed after the instruction or the user description changed
+func (t *Translator) promptHash() string {
+	// The detected source language follows from the source, which has its own hash
+	translationConfig := t.translationConfig(nil, 0, "")
+	translationConfig.SourceLanguage = t.config.SourceLanguage
+	instruction, err := translationConfig.Instruction(false)
+	if err != nil {
+		return ""
+	}
+	return hashBytes([]byte(instruction))
+}
+
+// progressSettings returns the options that change which cues are sent, how
+// they are split and how the output is timed, so progress is only resumed
+// with the same ones
+func (t *Translator) progressSettings() map[string]string {
+	annotations := strings.ToLower(strings.TrimSpace(t.config.SDHAnnotations))
+	if annotations == "" {
+		annotations = "keep"
+	}
+	return map[string]string{
+		"dialogue-segments": strconv.FormatBool(t.config.DialogueSegments),
+		"sdh-annotations":   annotations,
+		"strip-sdh":         strconv.FormatBool(t.config.StripSDH),
+		"timing-pre":        strings.Join(t.config.TimingPre, " "),
+		"timing-post":       strings.Join(t.config.TimingPost, " "),
+	}
+}
+
+// hashBytes returns the SHA-256 of data in "sha256:<hex>" form
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// encodeCompletion packs the completion flags into a base64 bitmap
+func encodeCompletion(completed []bool) string {
+	if len(completed) == 0 {
+		return ""
+	}
+	bits := make([]byte, (len(completed)+7)/8)
+	for i, done := range completed {
+		if done {
+			bits[i/8] |= 1 << (i % 8)
+		}
+	}
+	return base64.StdEncoding.EncodeToString(bits)
+}
+
+// decodeCompletion unpacks a bitmap written by encodeCompletion for total lines
+func decodeCompletion(encoded string, total int) ([]bool, error) {
+	completed := make([]bool, total)
+	if encoded == "" {
+		return completed, nil
+	}
+
+	bits, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(bits) != (total+7)/8 {
+		return nil, fmt.Errorf("it covers %d lines, the source has %d", len(bits)*8, total)
+	}
+	for i := range completed {
+		completed[i] = bits[i/8]&(1<<(i%8)) != 0
+	}
+	return completed, nil
+}
@@ -0,0 +1,153 @@
+package translator
+
+import (
+	"context"
+	stdErrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/interrupt"
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+func TestCompletionBitmap(t *testing.T) {
+	completed := []bool{true, false, true, true, false, false, false, false, true, true}
+	decoded, err := decodeCompletion(encodeCompletion(completed), len(completed))
+	if err != nil {
+		t.Fatalf("decodeCompletion() failed: %v", err)
+	}
+	for i := range completed {
+		if decoded[i] != completed[i] {
+			t.Errorf("line %d: got %v, want %v", i+1, decoded[i], completed[i])
+		}
+	}
+
+	if _, err = decodeCompletion(encodeCompletion(completed), 30); err == nil {
+		t.Error("Expected error for a bitmap that does not cover the source")
+	}
+	if _, err = decodeCompletion("not base64!", 10); err == nil {
+		t.Error("Expected error for an invalid bitmap")
+	}
+}
+
+func TestTranslator_checkSavedProgress(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	tempDir := t.TempDir()
+	inputPath := filepath.Join(tempDir, "episode.srt")
+	var subtitles []srt.Subtitle
+	for i := 0; i < 10; i++ {
+		subtitles = append(subtitles, srt.Subtitle{
+			Index:   i + 1,
+			Start:   time.Duration(i) * time.Second,
+			End:     time.Duration(i)*time.Second + 500*time.Millisecond,
+			Content: "line",
+		})
+	}
+	source := srt.ComposeSRT(subtitles)
+	if err := os.WriteFile(inputPath, []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	resume := true
+	stripSDH := false
+	newTranslator := func(targetLanguage string) *Translator {
+		return NewTranslatorWithProvider(&config.Config{
+			StripSDH:       stripSDH,
+			InputFile:      inputPath,
+			OutputFile:     filepath.Join(tempDir, "episode.out.srt"),
+			TargetLanguage: targetLanguage,
+			ModelName:      "mock-model",
+			BatchSize:      4,
+			ThinkingLevel:  "high",
+			Resume:         &resume,
+			NonInteractive: true,
+		}, &stoppingProvider{stop: make(chan struct{})})
+	}
+
+	// Stop after the first batch so a progress file is left behind
+	first := newTranslator("French")
+	provider := first.provider.(*stoppingProvider)
+	first.SetContext([]providers.ContextMessage{{Role: "user", Content: "previous episode"}})
+	if err := first.Translate(interrupt.WithStop(context.Background(), provider.stop)); !stdErrors.Is(err, interrupt.ErrStopped) {
+		t.Fatalf("Translate() error = %v, want ErrStopped", err)
+	}
+	saved, err := os.ReadFile(first.progressFile)
+	if err != nil {
+		t.Fatalf("Expected progress file: %v", err)
+	}
+
+	restore := func() {
+		if err = os.WriteFile(first.progressFile, saved, 0644); err != nil {
+			t.Fatalf("Failed to restore progress: %v", err)
+		}
+	}
+
+	t.Run("matching progress resumes", func(t *testing.T) {
+		translator := newTranslator("French")
+		if err = translator.checkSavedProgress(); err != nil {
+			t.Fatalf("checkSavedProgress() failed: %v", err)
+		}
+		if translator.progressRejection != nil {
+			t.Fatalf("Unexpected rejection: %v", translator.progressRejection)
+		}
+		if translator.config.StartLine != 5 {
+			t.Errorf("StartLine = %d, want 5", translator.config.StartLine)
+		}
+		if len(translator.resumeContext) != 1 || translator.resumeContext[0].Content != "previous episode" {
+			t.Error("Expected the saved context to be restored")
+		}
+		for i, done := range translator.completed {
+			if done != (i < 4) {
+				t.Errorf("line %d completed = %v", i+1, done)
+			}
+		}
+	})
+
+	tests := []struct {
+		name   string
+		target string
+		change func()
+		reason string
+	}{
+		{"target language", "German", func() {}, "target language"},
+		{"source changed", "French", func() {
+			_ = os.WriteFile(inputPath, []byte(strings.Replace(source, "line", "edited", 1)), 0644)
+		}, "changed since the progress was saved"},
+		{"settings", "French", func() { stripSDH = true }, `--strip-sdh was "false", now "true"`},
+		{"legacy format", "French", func() {
+			_ = os.WriteFile(first.progressFile, []byte(`{"line":5,"input_file":"`+inputPath+`"}`), 0644)
+		}, "older version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore()
+			defer func() {
+				_ = os.WriteFile(inputPath, []byte(source), 0644)
+				stripSDH = false
+			}()
+			tt.change()
+
+			translator := newTranslator(tt.target)
+			if err = translator.checkSavedProgress(); err != nil {
+				t.Fatalf("checkSavedProgress() failed: %v", err)
+			}
+			if translator.progressRejection == nil {
+				t.Fatal("Expected saved progress to be rejected")
+			}
+			if !strings.Contains(translator.progressRejection.Error(), tt.reason) {
+				t.Errorf("Rejection %q does not mention %q", translator.progressRejection.Error(), tt.reason)
+			}
+			if translator.config.StartLine != 0 {
+				t.Errorf("Rejected progress must not set the start line, got %d", translator.config.StartLine)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package translator
+
+import (
+	"github.com/luispater/gemini-srt-translator-go/internal/prompt"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// loadPrompt loads the prompt template, rule packs and style guide once
+func (t *Translator) loadPrompt() error {
+	if t.prompt != nil {
+		return nil
+	}
+	engine, err := prompt.New(t.config.PromptTemplate, t.config.RulesDir)
+	if err != nil {
+		return err
+	}
+	styleGuide, err := prompt.LoadStyleGuide(t.config.StyleGuide)
+	if err != nil {
+		return err
+	}
+	t.prompt, t.styleGuide = engine, styleGuide
+	return nil
+}
+
+// translationConfig builds the provider settings and prompt variables for a
+// batch of a subtitle file with totalLines lines. batch may be nil when the
+// instruction is not rendered for a particular batch.
+func (t *Translator) translationConfig(batch []srt.SubtitleObject, totalLines int, retryInstruction string) *providers.TranslationConfig {
+	translationConfig := &providers.TranslationConfig{
+		ModelName:        t.config.ModelName,
+		TargetLanguage:   t.config.TargetLanguage,
+		Description:      t.config.Description,
+		RetryInstruction: retryInstruction,
+		Temperature:      t.config.Temperature,
+		TopP:             t.config.TopP,
+		TopK:             t.config.TopK,
+		Streaming:        t.config.Streaming,
+		Thinking:         t.config.Thinking,
+		ThinkingLevel:    t.config.ThinkingLevel,
+		Prompt:           t.prompt,
+		SourceLanguage:   t.sourceLanguage,
+		Glossary:         prompt.ParseGlossary(t.config.Glossary),
+		StyleGuide:       t.styleGuide,
+	}
+	if len(batch) > 0 {
+		first, last := batchLines(batch)
+		translationConfig.Batch = prompt.Batch{
+			Number:     t.batchNumber,
+			FirstLine:  first,
+			LastLine:   last,
+			Lines:      len(batch),
+			TotalLines: totalLines,
+			Segments:   hasSegments(batch),
+		}
+	}
+	return translationConfig
+}
@@ -0,0 +1,49 @@
+package translator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// recordExchange writes the transcript of one batch attempt to RecordDir.
+// errRequest is the provider error, errValidate why the lines were rejected.
+func (t *Translator) recordExchange(batch []srt.SubtitleObject, attempt int, exchange *providers.Exchange, errRequest error, errValidate error) {
+	if exchange == nil {
+		return
+	}
+	if t.recordSequence == 0 {
+		t.recordSequence = providers.NextTranscriptSequence(t.config.RecordDir)
+	}
+
+	first, last := batchLines(batch)
+	transcript := &providers.Transcript{
+		Sequence:  t.recordSequence,
+		Time:      time.Now(),
+		Batch:     t.batchNumber,
+		Attempt:   attempt + 1,
+		FirstLine: first,
+		LastLine:  last,
+		Exchange:  *exchange,
+	}
+	if !t.headless {
+		transcript.InputFile = t.config.InputFile
+	}
+	switch {
+	case errRequest != nil:
+		transcript.Error = errRequest.Error()
+	case errValidate != nil:
+		transcript.Validation = errValidate.Error()
+	default:
+		transcript.Validation = "ok"
+	}
+
+	if err := providers.WriteTranscript(t.config.RecordDir, transcript); err != nil {
+		logger.Warning(fmt.Sprintf("Failed to write transcript: %v", err))
+		return
+	}
+	t.recordSequence++
+}
@@ -0,0 +1,73 @@
+package translator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+func TestTranslator_recordReplay(t *testing.T) {
+	replayDir := t.TempDir()
+	recorded := &providers.Transcript{
+		Sequence: 1,
+		Batch:    1,
+		Attempt:  1,
+		Exchange: providers.Exchange{
+			Provider: "gemini",
+			Model:    "mock-model",
+			Response: `[{"index":0,"content":"Bonjour","guard":"GST_LINE_000000"},{"index":1,"Au revoir","guard":"GST_LINE_000001"}]`,
+		},
+		Validation: "ok",
+	}
+	if err := providers.WriteTranscript(replayDir, recorded); err != nil {
+		t.Fatalf("WriteTranscript() failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		TargetLanguage: "French",
+		ModelName:      "mock-model",
+		BatchSize:      2,
+		ThinkingLevel:  "high",
+		NonInteractive: true,
+		ReplayDir:      replayDir,
+		RecordDir:      t.TempDir(),
+	}
+	provider, err := providers.NewReplayProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewReplayProvider() failed: %v", err)
+	}
+
+	subtitles := []srt.Subtitle{
+		{Index: 1, Start: 0, End: time.Second, Content: "Hello"},
+		{Index: 2, Start: time.Second, End: 2 * time.Second, Content: "Goodbye"},
+	}
+	translated, err := NewTranslatorWithProvider(cfg, provider).TranslateSubtitles(context.Background(), subtitles)
+	if err != nil {
+		t.Fatalf("TranslateSubtitles() failed: %v", err)
+	}
+	if translated[0].Content != "Bonjour" || translated[1].Content != "Au revoir" {
+		t.Errorf("Unexpected translation: %+v", translated)
+	}
+
+	transcripts, err := providers.ReadTranscripts(cfg.RecordDir)
+	if err != nil {
+		t.Fatalf("ReadTranscripts() failed: %v", err)
+	}
+	if len(transcripts) != 1 {
+		t.Fatalf("Got %d transcripts, want 1", len(transcripts))
+	}
+	got := transcripts[0]
+	if got.Batch != 1 || got.Attempt != 1 || got.FirstLine != 1 || got.LastLine != 2 || got.Validation != "ok" {
+		t.Errorf("Unexpected transcript: %+v", got)
+	}
+	if got.Response != recorded.Response {
+		t.Errorf("Response = %q, want the raw recorded response", got.Response)
+	}
+	if len(got.Repairs) != 1 {
+		t.Errorf("Repairs = %v, want the missing content key repair", got.Repairs)
+	}
+}
@@ -0,0 +1,42 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// stripSDH removes the annotations for the hearing impaired from the source
+// cues when StripSDH is set, or the cues holding nothing but annotations when
+// segments drop them. The layout kept for PreserveFormat no longer matches
+// once cues are removed, so it is dropped then.
+func (t *Translator) stripSDH(subtitles []srt.Subtitle, path string) []srt.Subtitle {
+	var stripped []srt.Subtitle
+	var removed int
+	var message string
+	switch {
+	case t.config.StripSDH:
+		stripped, removed = srt.StripSDH(subtitles)
+		message = fmt.Sprintf("Removed SDH annotations from %s: %d of %d cues left.", path, len(stripped), len(subtitles))
+	case t.config.DialogueSegments && strings.EqualFold(strings.TrimSpace(t.config.SDHAnnotations), "drop"):
+		stripped, removed = srt.DropAnnotationCues(subtitles)
+		if removed == 0 {
+			return subtitles
+		}
+		message = fmt.Sprintf("Removed %d cues with only SDH annotations from %s: %d of %d cues left.", removed, path, len(stripped), len(subtitles))
+	default:
+		return subtitles
+	}
+	if removed > 0 && t.sourceLayout != nil {
+		t.sourceLayout = nil
+		if !t.headless {
+			logger.Warning(fmt.Sprintf("%s: --preserve-format cannot keep the layout of a file whose cues were removed by --strip-sdh or --sdh-annotations drop.", path))
+		}
+	}
+	if !t.headless {
+		logger.Info(message)
+	}
+	return stripped
+}
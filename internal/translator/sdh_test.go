@@ -0,0 +1,67 @@
+package translator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+func TestTranslator_stripSDH(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	source := "1\n00:00:01,000 --> 00:00:02,000\n[SUSPENSEFUL MUSIC]\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\nJOHN: Who's there?\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\n- [knocking]\n- It's me.\n"
+	inputPath := filepath.Join(t.TempDir(), "episode.srt")
+	if err := os.WriteFile(inputPath, []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	provider := &segmentProvider{}
+	translator := NewTranslatorWithProvider(&config.Config{
+		InputFile:        inputPath,
+		TargetLanguage:   "French",
+		ModelName:        "mock-model",
+		BatchSize:        10,
+		ThinkingLevel:    "high",
+		NonInteractive:   true,
+		StripSDH:         true,
+		DialogueSegments: true,
+	}, provider)
+	if err := translator.Translate(context.Background()); err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+
+	// Nothing but speech is sent, as plain text
+	var sent []string
+	for _, batch := range provider.batches {
+		for _, item := range batch {
+			if item.Segments != nil {
+				t.Errorf("line %d sent as segments: %+v", item.Index, item.Segments)
+			}
+			sent = append(sent, item.Content)
+		}
+	}
+	if want := []string{"Who's there?", "It's me."}; strings.Join(sent, "|") != strings.Join(want, "|") {
+		t.Errorf("sent %q, want %q", sent, want)
+	}
+
+	data, err := os.ReadFile(translator.OutputFile())
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	want := "1\n00:00:03,000 --> 00:00:04,000\nWHO'S THERE?\n\n2\n00:00:05,000 --> 00:00:06,000\nIT'S ME.\n"
+	if string(data) != want {
+		t.Errorf("output = %q, want %q", data, want)
+	}
+	if _, err = srt.ParseStrict(data); err != nil {
+		t.Errorf("output does not parse: %v", err)
+	}
+}
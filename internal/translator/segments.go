@@ -0,0 +1,64 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// validateSDHAnnotations checks the SDHAnnotations option
+func (t *Translator) validateSDHAnnotations() error {
+	switch strings.ToLower(strings.TrimSpace(t.config.SDHAnnotations)) {
+	case "", "keep", "drop":
+		return nil
+	}
+	return errors.NewConfigurationError("sdh-annotations must be one of keep, drop", nil).WithContext("sdh_annotations", t.config.SDHAnnotations)
+}
+
+// withSegments moves the content of a batch item with dialogue dashes,
+// speaker labels or SDH annotations into segments, dropping the annotations
+// when asked. Items sent this way have an empty content and non-nil segments.
+func (t *Translator) withSegments(item srt.SubtitleObject) srt.SubtitleObject {
+	if !t.config.DialogueSegments {
+		return item
+	}
+	segments := srt.SplitSegments(item.Content)
+	if segments == nil {
+		return item
+	}
+	if strings.EqualFold(strings.TrimSpace(t.config.SDHAnnotations), "drop") {
+		segments = srt.DropAnnotations(segments)
+	}
+	item.Content = ""
+	item.Segments = segments
+	return item
+}
+
+// validateSegments checks the translated segments of a segmented batch item
+func validateSegments(translated srt.SubtitleObject, original srt.SubtitleObject) error {
+	if len(translated.Segments) != len(original.Segments) {
+		return errors.NewTranslationError(fmt.Sprintf("provider returned %d segments for line %d, expected %d", len(translated.Segments), original.Index, len(original.Segments)), nil).WithContext("line_index", original.Index).WithContext("expected_segments", len(original.Segments)).WithContext("actual_segments", len(translated.Segments))
+	}
+	for i, segment := range translated.Segments {
+		source := original.Segments[i]
+		if segment.Kind != source.Kind {
+			return errors.NewTranslationError(fmt.Sprintf("provider returned a %s segment for line %d where a %s segment was sent", segment.Kind, original.Index, source.Kind), nil).WithContext("line_index", original.Index).WithContext("segment", i)
+		}
+		if strings.TrimSpace(segment.Text) == "" && strings.TrimSpace(source.Text) != "" {
+			return errors.NewTranslationError(fmt.Sprintf("provider returned an empty segment for line %d", original.Index), nil).WithContext("line_index", original.Index).WithContext("segment", i)
+		}
+	}
+	return nil
+}
+
+// hasSegments tells whether any item of a batch is sent as segments
+func hasSegments(batch []srt.SubtitleObject) bool {
+	for _, item := range batch {
+		if item.Segments != nil {
+			return true
+		}
+	}
+	return false
+}
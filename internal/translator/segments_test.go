@@ -0,0 +1,133 @@
+package translator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// segmentProvider "translates" by upper-casing content and segment texts,
+// keeping the batches it was sent
+type segmentProvider struct {
+	mockProvider
+	batches [][]srt.SubtitleObject
+}
+
+func (p *segmentProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []providers.ContextMessage, config *providers.TranslationConfig) (*providers.TranslationResponse, error) {
+	p.batches = append(p.batches, batch)
+	translated := make([]srt.SubtitleObject, len(batch))
+	for i, item := range batch {
+		item.Content = strings.ToUpper(item.Content)
+		var segments []srt.Segment
+		for _, segment := range item.Segments {
+			// Only the payload fields come back from a model
+			segments = append(segments, srt.Segment{Kind: segment.Kind, Speaker: segment.Speaker, Text: strings.ToUpper(segment.Text)})
+		}
+		item.Segments = segments
+		translated[i] = item
+	}
+	return &providers.TranslationResponse{TranslatedBatch: translated, Context: previousContext}, nil
+}
+
+func TestTranslator_dialogueSegments(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	contents := []string{
+		"- Where were you?\n- At work.",
+		"[door slams]\nJOHN: Who's there?",
+		"I told you,\nI was working late.",
+		"♪ Happy birthday ♪",
+	}
+	var subtitles []srt.Subtitle
+	for i, content := range contents {
+		subtitles = append(subtitles, srt.Subtitle{Index: i + 1, Start: time.Duration(i) * time.Second, End: time.Duration(i)*time.Second + 500*time.Millisecond, Content: content})
+	}
+
+	tests := []struct {
+		name        string
+		segments    bool
+		annotations string
+		want        []string
+	}{
+		{
+			name:     "segments",
+			segments: true,
+			want:     []string{"- WHERE WERE YOU?\n- AT WORK.", "[DOOR SLAMS]\nJOHN: WHO'S THERE?", "I TOLD YOU,    I WAS WORKING LATE.", "♪ HAPPY BIRTHDAY ♪"},
+		},
+		{
+			name:        "annotations dropped",
+			segments:    true,
+			annotations: "drop",
+			want:        []string{"- WHERE WERE YOU?\n- AT WORK.", "JOHN: WHO'S THERE?", "I TOLD YOU,    I WAS WORKING LATE."},
+		},
+		{
+			name: "plain text",
+			want: []string{"- WHERE WERE YOU?    - AT WORK.", "[DOOR SLAMS]    JOHN: WHO'S THERE?", "I TOLD YOU,    I WAS WORKING LATE.", "♪ HAPPY BIRTHDAY ♪"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &segmentProvider{}
+			translator := NewTranslatorWithProvider(&config.Config{
+				TargetLanguage:   "French",
+				ModelName:        "mock-model",
+				BatchSize:        10,
+				ThinkingLevel:    "high",
+				DialogueSegments: tt.segments,
+				SDHAnnotations:   tt.annotations,
+			}, provider)
+			translated, err := translator.TranslateSubtitles(context.Background(), subtitles)
+			if err != nil {
+				t.Fatalf("TranslateSubtitles() failed: %v", err)
+			}
+			// Cues holding nothing but dropped annotations are removed
+			if len(translated) != len(tt.want) {
+				t.Fatalf("TranslateSubtitles() returned %d cues, want %d", len(translated), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if translated[i].Content != want {
+					t.Errorf("cue %d = %q, want %q", i+1, translated[i].Content, want)
+				}
+			}
+
+			// Segmented cues are sent without content
+			sent := provider.batches[0]
+			if segmented := sent[0].Segments != nil; segmented != tt.segments || (segmented && sent[0].Content != "") {
+				t.Errorf("cue 1 sent as %+v", sent[0])
+			}
+			if sent[2].Segments != nil {
+				t.Errorf("plain cue 3 sent as segments: %+v", sent[2].Segments)
+			}
+		})
+	}
+}
+
+func TestTranslator_segmentValidation(t *testing.T) {
+	original := srt.SubtitleObject{Index: 3, Segments: srt.SplitSegments("- Hi.\n- Hello.")}
+	tests := []struct {
+		name     string
+		segments []srt.Segment
+		wantErr  bool
+	}{
+		{"matching", []srt.Segment{{Kind: srt.SegmentSpeech, Text: "Salut."}, {Kind: srt.SegmentSpeech, Text: "Bonjour."}}, false},
+		{"merged", []srt.Segment{{Kind: srt.SegmentSpeech, Text: "Salut. Bonjour."}}, true},
+		{"wrong kind", []srt.Segment{{Kind: srt.SegmentSpeech, Text: "Salut."}, {Kind: srt.SegmentSound, Text: "Bonjour."}}, true},
+		{"empty text", []srt.Segment{{Kind: srt.SegmentSpeech, Text: "Salut."}, {Kind: srt.SegmentSpeech, Text: " "}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSegments(srt.SubtitleObject{Index: 3, Segments: tt.segments}, original)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSegments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
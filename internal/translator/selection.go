@@ -0,0 +1,167 @@
+package translator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+var timeRangePattern = regexp.MustCompile(`(\d+:\d{1,2}:\d{1,2}(?:[.,]\d+)?)\s*-\s*(\d+:\d{1,2}:\d{1,2}(?:[.,]\d+)?)`)
+
+// lineRange is an inclusive range of 1-based subtitle line numbers
+type lineRange struct {
+	start int
+	end   int
+}
+
+// timeRange is an inclusive range of subtitle time
+type timeRange struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// cueSelection describes the cues chosen for selective re-translation
+type cueSelection struct {
+	lines []lineRange
+	times []timeRange
+}
+
+// parseCueSelection parses the --lines and --time selectors.
+// It returns nil when neither selector is set.
+func parseCueSelection(lineSpec, timeSpec string) (*cueSelection, error) {
+	lineSpec = strings.TrimSpace(lineSpec)
+	timeSpec = strings.TrimSpace(timeSpec)
+	if lineSpec == "" && timeSpec == "" {
+		return nil, nil
+	}
+
+	selection := &cueSelection{}
+	if lineSpec != "" {
+		for _, part := range strings.Split(lineSpec, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			r, err := parseLineRange(part)
+			if err != nil {
+				return nil, err
+			}
+			selection.lines = append(selection.lines, r)
+		}
+	}
+
+	if timeSpec != "" {
+		times, err := parseTimeRanges(timeSpec)
+		if err != nil {
+			return nil, err
+		}
+		selection.times = times
+	}
+
+	if len(selection.lines) == 0 && len(selection.times) == 0 {
+		return nil, fmt.Errorf("empty cue selection")
+	}
+
+	return selection, nil
+}
+
+// parseLineRange parses "120-180" or "455"
+func parseLineRange(part string) (lineRange, error) {
+	startStr, endStr, isRange := strings.Cut(part, "-")
+	start, err := strconv.Atoi(strings.TrimSpace(startStr))
+	if err != nil || start < 1 {
+		return lineRange{}, fmt.Errorf("invalid line selector %q", part)
+	}
+	end := start
+	if isRange {
+		end, err = strconv.Atoi(strings.TrimSpace(endStr))
+		if err != nil || end < start {
+			return lineRange{}, fmt.Errorf("invalid line selector %q", part)
+		}
+	}
+	return lineRange{start: start, end: end}, nil
+}
+
+// parseTimeRanges parses "00:12:00-00:15:30,01:02:03,500-01:02:10".
+// Ranges are matched by pattern because commas may also separate milliseconds.
+func parseTimeRanges(spec string) ([]timeRange, error) {
+	matches := timeRangePattern.FindAllStringSubmatchIndex(spec, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("invalid time selector %q, expected START-END", spec)
+	}
+
+	var ranges []timeRange
+	last := 0
+	for _, match := range matches {
+		if strings.Trim(spec[last:match[0]], " ,;") != "" {
+			return nil, fmt.Errorf("invalid time selector %q", strings.TrimSpace(spec[last:match[0]]))
+		}
+		last = match[1]
+
+		start, err := srt.ParseDuration(spec[match[2]:match[3]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid time selector %q: %w", spec[match[0]:match[1]], err)
+		}
+		end, err := srt.ParseDuration(spec[match[4]:match[5]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid time selector %q: %w", spec[match[0]:match[1]], err)
+		}
+		if end < start {
+			return nil, fmt.Errorf("invalid time selector %q, end is before start", spec[match[0]:match[1]])
+		}
+		ranges = append(ranges, timeRange{start: start, end: end})
+	}
+	if strings.Trim(spec[last:], " ,;") != "" {
+		return nil, fmt.Errorf("invalid time selector %q", strings.TrimSpace(spec[last:]))
+	}
+
+	return ranges, nil
+}
+
+// indices returns the sorted 0-based subtitle indices matched by the selection.
+// A cue matches a time range when the two overlap.
+func (s *cueSelection) indices(subtitles []srt.Subtitle) []int {
+	selected := make(map[int]bool)
+	for _, r := range s.lines {
+		for line := r.start; line <= r.end && line <= len(subtitles); line++ {
+			selected[line-1] = true
+		}
+	}
+	for _, r := range s.times {
+		for i, sub := range subtitles {
+			if sub.Start <= r.end && sub.End >= r.start {
+				selected[i] = true
+			}
+		}
+	}
+
+	result := make([]int, 0, len(selected))
+	for index := range selected {
+		result = append(result, index)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// groupSelectedIndices splits sorted indices into runs of consecutive lines
+// no longer than batchSize.
+func groupSelectedIndices(indices []int, batchSize int) [][]int {
+	var groups [][]int
+	var current []int
+	for _, index := range indices {
+		if len(current) > 0 && (index != current[len(current)-1]+1 || len(current) >= batchSize) {
+			groups = append(groups, current)
+			current = nil
+		}
+		current = append(current, index)
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
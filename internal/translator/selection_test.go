@@ -0,0 +1,213 @@
+package translator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+func TestParseCueSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    string
+		time     string
+		wantNil  bool
+		wantErr  bool
+		wantLine []lineRange
+		wantTime []timeRange
+	}{
+		{name: "empty", wantNil: true},
+		{
+			name:     "lines",
+			lines:    "120-180, 455",
+			wantLine: []lineRange{{start: 120, end: 180}, {start: 455, end: 455}},
+		},
+		{
+			name:     "time",
+			time:     "00:12:00-00:15:30",
+			wantTime: []timeRange{{start: 12 * time.Minute, end: 15*time.Minute + 30*time.Second}},
+		},
+		{name: "reversed lines", lines: "10-5", wantErr: true},
+		{name: "zero line", lines: "0", wantErr: true},
+		{name: "time without end", time: "00:12:00", wantErr: true},
+		{name: "bad time", time: "soon-later", wantErr: true},
+		{
+			name:     "time with milliseconds",
+			time:     "00:00:01,500-00:00:02,000, 00:01:00-00:02:00",
+			wantTime: []timeRange{{start: 1500 * time.Millisecond, end: 2 * time.Second}, {start: time.Minute, end: 2 * time.Minute}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selection, err := parseCueSelection(tt.lines, tt.time)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCueSelection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if selection != nil {
+					t.Fatalf("Expected nil selection, got %+v", selection)
+				}
+				return
+			}
+			if !reflect.DeepEqual(selection.lines, tt.wantLine) {
+				t.Errorf("lines = %+v, want %+v", selection.lines, tt.wantLine)
+			}
+			if !reflect.DeepEqual(selection.times, tt.wantTime) {
+				t.Errorf("times = %+v, want %+v", selection.times, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestCueSelection_indices(t *testing.T) {
+	subtitles := []srt.Subtitle{
+		{Index: 1, Start: 0, End: time.Second},
+		{Index: 2, Start: 2 * time.Second, End: 3 * time.Second},
+		{Index: 3, Start: 4 * time.Second, End: 5 * time.Second},
+		{Index: 4, Start: 6 * time.Second, End: 7 * time.Second},
+	}
+
+	selection, err := parseCueSelection("1,9", "00:00:04,500-00:00:06,000")
+	if err != nil {
+		t.Fatalf("parseCueSelection() failed: %v", err)
+	}
+
+	got := selection.indices(subtitles)
+	want := []int{0, 2, 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("indices() = %v, want %v", got, want)
+	}
+}
+
+func TestGroupSelectedIndices(t *testing.T) {
+	got := groupSelectedIndices([]int{0, 1, 2, 5, 6, 9}, 2)
+	want := [][]int{{0, 1}, {2}, {5, 6}, {9}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupSelectedIndices() = %v, want %v", got, want)
+	}
+}
+
+func TestTranslator_performSelectiveTranslation(t *testing.T) {
+	tempDir := t.TempDir()
+	inputPath := filepath.Join(tempDir, "episode.srt")
+	outputPath := filepath.Join(tempDir, "episode.fr.srt")
+
+	original := []srt.Subtitle{
+		{Index: 1, Start: time.Second, End: 2 * time.Second, Content: "One"},
+		{Index: 2, Start: 3 * time.Second, End: 4 * time.Second, Content: "Two"},
+		{Index: 3, Start: 5 * time.Second, End: 6 * time.Second, Content: "Three"},
+	}
+	translated := []srt.Subtitle{
+		{Index: 1, Start: time.Second, End: 2 * time.Second, Content: "Un"},
+		{Index: 2, Start: 3 * time.Second, End: 4 * time.Second, Content: "Deux?"},
+		{Index: 3, Start: 5 * time.Second, End: 6 * time.Second, Content: "Trois"},
+	}
+	if err := os.WriteFile(inputPath, []byte(srt.ComposeSRT(original)), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	if err := os.WriteFile(outputPath, []byte(srt.ComposeSRT(translated)), 0644); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+
+	translator := &Translator{
+		config: &config.Config{
+			InputFile:     inputPath,
+			LineSelection: "2",
+			BatchSize:     10,
+			ThinkingLevel: "high",
+		},
+		provider:   &mockProvider{},
+		outputFile: outputPath,
+	}
+	if err := translator.validateConfig(); err != nil {
+		t.Fatalf("validateConfig() failed: %v", err)
+	}
+	if err := translator.performTranslation(context.Background()); err != nil {
+		t.Fatalf("performTranslation() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	result, err := srt.ParseSRT(string(data))
+	if err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+
+	// The mock provider echoes the source, so only the selected line changes
+	want := []string{"Un", "Two", "Trois"}
+	for i, sub := range result {
+		if sub.Content != want[i] {
+			t.Errorf("line %d = %q, want %q", i+1, sub.Content, want[i])
+		}
+	}
+}
+
+// indexCountingProvider counts 100 tokens for every line of a batch
+type indexCountingProvider struct {
+	mockProvider
+	batchSizes []int
+}
+
+func (p *indexCountingProvider) CountTokens(ctx context.Context, modelName string, content string) (int32, error) {
+	return int32(100 * strings.Count(content, `"index"`)), nil
+}
+
+func (p *indexCountingProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []providers.ContextMessage, config *providers.TranslationConfig) (*providers.TranslationResponse, error) {
+	p.batchSizes = append(p.batchSizes, len(batch))
+	return p.mockProvider.TranslateBatch(ctx, batch, previousContext, config)
+}
+
+func TestTranslator_performSelectiveTranslationShrinksBatches(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	tempDir := t.TempDir()
+	inputPath := filepath.Join(tempDir, "episode.srt")
+	outputPath := filepath.Join(tempDir, "episode.fr.srt")
+	var original []srt.Subtitle
+	for i := 0; i < 10; i++ {
+		original = append(original, srt.Subtitle{Index: i + 1, Start: time.Duration(i) * time.Second, End: time.Duration(i)*time.Second + 500*time.Millisecond, Content: "line"})
+	}
+	for _, path := range []string{inputPath, outputPath} {
+		if err := os.WriteFile(path, []byte(srt.ComposeSRT(original)), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	// 10 lines take 1000 tokens, over 90% of the limit, so the batch size is halved
+	provider := &indexCountingProvider{}
+	translator := NewTranslatorWithProvider(&config.Config{
+		InputFile:      inputPath,
+		OutputFile:     outputPath,
+		TargetLanguage: "French",
+		LineSelection:  "1-10",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+		NonInteractive: true,
+	}, provider)
+	translator.tokenLimit = 1000
+	if err := translator.validateConfig(); err != nil {
+		t.Fatalf("validateConfig() failed: %v", err)
+	}
+	if err := translator.performTranslation(context.Background()); err != nil {
+		t.Fatalf("performTranslation() failed: %v", err)
+	}
+	if want := []int{5, 5}; !reflect.DeepEqual(provider.batchSizes, want) {
+		t.Errorf("batch sizes = %v, want %v", provider.batchSizes, want)
+	}
+}
@@ -0,0 +1,39 @@
+package translator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// saveThoughts appends the model thoughts of one answered batch attempt to the
+// thoughts log, under a header with the batch, its line range and token count
+func (t *Translator) saveThoughts(batch []srt.SubtitleObject, attempt int, response *providers.TranslationResponse) {
+	if !t.config.SaveThoughts || t.headless || t.thoughtsFilePath == "" {
+		return
+	}
+	thoughts := strings.TrimSpace(response.Thoughts)
+	if thoughts == "" {
+		return
+	}
+
+	first, last := batchLines(batch)
+	header := fmt.Sprintf("=== Batch %d, lines %d-%d, attempt %d, %d thinking tokens (%s) ===",
+		t.batchNumber, first, last, attempt+1, response.Usage.ThinkingTokens, time.Now().Format(time.RFC3339))
+
+	file, err := os.OpenFile(t.thoughtsFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Failed to open thoughts log: %v", err))
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	if _, err = fmt.Fprintf(file, "%s\n%s\n\n", header, thoughts); err != nil {
+		logger.Warning(fmt.Sprintf("Failed to write thoughts log: %v", err))
+	}
+}
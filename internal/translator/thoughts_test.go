@@ -0,0 +1,76 @@
+package translator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// thinkingProvider answers like mockProvider and reports thoughts for every batch
+type thinkingProvider struct {
+	mockProvider
+}
+
+func (p *thinkingProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []providers.ContextMessage, config *providers.TranslationConfig) (*providers.TranslationResponse, error) {
+	response, err := p.mockProvider.TranslateBatch(ctx, batch, previousContext, config)
+	if err != nil {
+		return nil, err
+	}
+	response.Thoughts = "Thinking about the tone.\n"
+	response.Usage.ThinkingTokens = 42
+	return response, nil
+}
+
+func TestTranslator_saveThoughts(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	inputPath := filepath.Join(t.TempDir(), "episode.srt")
+	var subtitles []srt.Subtitle
+	for i := 0; i < 5; i++ {
+		subtitles = append(subtitles, srt.Subtitle{
+			Index:   i + 1,
+			Start:   time.Duration(i) * time.Second,
+			End:     time.Duration(i)*time.Second + 500*time.Millisecond,
+			Content: "line",
+		})
+	}
+	if err := os.WriteFile(inputPath, []byte(srt.ComposeSRT(subtitles)), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	translator := NewTranslatorWithProvider(&config.Config{
+		InputFile:      inputPath,
+		TargetLanguage: "French",
+		ModelName:      "mock-model",
+		BatchSize:      3,
+		ThinkingLevel:  "high",
+		NonInteractive: true,
+		SaveThoughts:   true,
+	}, &thinkingProvider{})
+	if err := translator.Translate(context.Background()); err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(translator.thoughtsFilePath)
+	if err != nil {
+		t.Fatalf("Failed to read thoughts log: %v", err)
+	}
+	log := string(data)
+	for _, header := range []string{"=== Batch 1, lines 1-3, attempt 1, 42 thinking tokens", "=== Batch 2, lines 4-5, attempt 1, 42 thinking tokens"} {
+		if !strings.Contains(log, header) {
+			t.Errorf("Thoughts log is missing %q:\n%s", header, log)
+		}
+	}
+	if strings.Count(log, "Thinking about the tone.") != 2 {
+		t.Errorf("Expected the thoughts of both batches:\n%s", log)
+	}
+}
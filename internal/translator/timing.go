@@ -0,0 +1,46 @@
+package translator
+
+import (
+	"strings"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// parseTiming parses the configured pre and post timing steps
+func (t *Translator) parseTiming() error {
+	var err error
+	if t.timingPre, err = srt.ParseTimingSteps(t.config.TimingPre); err != nil {
+		return errors.NewConfigurationError("invalid --timing-pre step", err).WithContext("steps", strings.Join(t.config.TimingPre, " "))
+	}
+	if t.timingPost, err = srt.ParseTimingSteps(t.config.TimingPost); err != nil {
+		return errors.NewConfigurationError("invalid --timing-post step", err).WithContext("steps", strings.Join(t.config.TimingPost, " "))
+	}
+	return nil
+}
+
+// restoreSourceTimings gives a loaded output the timings of the source again,
+// so post steps already applied to it are not applied twice when resuming
+func (t *Translator) restoreSourceTimings(originalSubtitles []srt.Subtitle, translatedSubtitles []srt.Subtitle) {
+	if len(t.timingPost) == 0 {
+		return
+	}
+	for i := range translatedSubtitles {
+		translatedSubtitles[i].Start = originalSubtitles[i].Start
+		translatedSubtitles[i].End = originalSubtitles[i].End
+	}
+}
+
+// applyTimingPost returns a copy of the translated subtitles with the post
+// timing steps applied, leaving the working copy on the source timings
+func (t *Translator) applyTimingPost(translatedSubtitles []srt.Subtitle) ([]srt.Subtitle, error) {
+	if len(t.timingPost) == 0 {
+		return translatedSubtitles, nil
+	}
+	retimed := make([]srt.Subtitle, len(translatedSubtitles))
+	copy(retimed, translatedSubtitles)
+	if err := srt.ApplyTiming(retimed, t.timingPost); err != nil {
+		return nil, errors.NewConfigurationError("failed to apply timing steps", err).WithContext("file_path", t.outputFile)
+	}
+	return retimed, nil
+}
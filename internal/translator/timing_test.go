@@ -0,0 +1,61 @@
+package translator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+func TestTranslator_timingSteps(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	input := []srt.Subtitle{
+		{Index: 1, Start: time.Second, End: 3 * time.Second, Content: "Hello"},
+		{Index: 2, Start: 3 * time.Second, End: 4 * time.Second, Content: "World"},
+	}
+	inputPath := filepath.Join(t.TempDir(), "episode.srt")
+	if err := os.WriteFile(inputPath, []byte(srt.ComposeSRT(input)), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	translator := NewTranslatorWithProvider(&config.Config{
+		InputFile:      inputPath,
+		TargetLanguage: "French",
+		ModelName:      "mock-model",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+		NonInteractive: true,
+		TimingPre:      []string{"shift=+500ms"},
+		TimingPost:     []string{"min-gap=100ms"},
+	}, &mockProvider{})
+	if err := translator.Translate(context.Background()); err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(translator.OutputFile())
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	output, _ := srt.ParseSRT(string(data))
+	want := []time.Duration{1500 * time.Millisecond, 3400 * time.Millisecond, 3500 * time.Millisecond, 4500 * time.Millisecond}
+	if len(output) != 2 || output[0].Start != want[0] || output[0].End != want[1] || output[1].Start != want[2] || output[1].End != want[3] {
+		t.Errorf("Unexpected output timings:\n%s", data)
+	}
+
+	translator = NewTranslatorWithProvider(&config.Config{
+		InputFile:      inputPath,
+		TargetLanguage: "French",
+		ThinkingLevel:  "high",
+		TimingPre:      []string{"fps=24"},
+	}, &mockProvider{})
+	if err = translator.validateConfig(); err == nil {
+		t.Error("validateConfig() accepted an invalid timing step")
+	}
+}
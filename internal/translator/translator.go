@@ -12,7 +12,11 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/luispater/gemini-srt-translator-go/internal/decision"
+	"github.com/luispater/gemini-srt-translator-go/internal/events"
+	"github.com/luispater/gemini-srt-translator-go/internal/interrupt"
 	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/prompt"
 	"github.com/luispater/gemini-srt-translator-go/internal/providers"
 	"github.com/luispater/gemini-srt-translator-go/internal/video"
 	"github.com/luispater/gemini-srt-translator-go/pkg/config"
@@ -21,15 +25,11 @@ import (
 	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
 )
 
-// ProgressInfo stores information about translation progress
-type ProgressInfo struct {
-	Line      int    `json:"line"`
-	InputFile string `json:"input_file"`
-}
-
 // ProgressBar wrapper to implement ProgressUpdater interface
 type ProgressBarWrapper struct {
-	bar *logger.ProgressBar
+	bar        *logger.ProgressBar
+	thinking   bool
+	onThinking func() // Called when the model starts thinking
 }
 
 func (p *ProgressBarWrapper) SetLoading(loading bool) {
@@ -39,30 +39,76 @@ func (p *ProgressBarWrapper) SetLoading(loading bool) {
 }
 
 func (p *ProgressBarWrapper) SetThinking(thinking bool) {
+	if thinking && !p.thinking && p.onThinking != nil {
+		p.onThinking()
+	}
+	p.thinking = thinking
 	if p.bar != nil {
 		p.bar.SetThinking(thinking)
 	}
 }
 
+func (p *ProgressBarWrapper) SetTokenUsage(usage providers.Usage) {
+	if p.bar != nil {
+		p.bar.SetTokenUsage(usage.PromptTokens, usage.OutputTokens, usage.ThinkingTokens)
+	}
+}
+
+// errBatchTooLarge marks a batch that exceeded the token limit after the batch size was reduced
+var errBatchTooLarge = stdErrors.New("batch exceeds the token limit")
+
 // Translator handles the subtitle translation process
 type Translator struct {
-	config           *config.Config
-	provider         providers.TranslationProvider
-	batchNumber      int
-	tokenLimit       int32
-	tokenCount       int32
-	translatedBatch  []srt.SubtitleObject
-	outputFile       string
-	progressFile     string
-	logFilePath      string
-	thoughtsFilePath string
-	context          []providers.ContextMessage
-	extractedSRTFile string   // Path to SRT file extracted from MKV
-	cleanupFiles     []string // Files to clean up after translation
+	config            *config.Config
+	provider          providers.TranslationProvider
+	batchNumber       int
+	tokenLimit        int32
+	tokenCount        int32
+	translatedBatch   []srt.SubtitleObject
+	outputFile        string
+	progressFile      string
+	logFilePath       string
+	thoughtsFilePath  string
+	context           []providers.ContextMessage
+	extractedSRTFile  string        // Path to SRT file extracted from MKV
+	cleanupFiles      []string      // Files to clean up after translation
+	selection         *cueSelection // Cues selected for re-translation, nil for a full run
+	usage             providers.Usage
+	requestCount      int
+	startedAt         time.Time
+	progressFunc      func(done int, total int)  // Optional observer of translated lines
+	headless          bool                       // Never print, set for in-memory translations
+	decider           decision.Decider           // Answers resume, start line, batch size and track questions
+	sourceHash        string                     // Hash of the subtitle source, stored in the progress file
+	completed         []bool                     // Translated lines of the current run
+	resumeContext     []providers.ContextMessage // Context restored from saved progress
+	progressRejection error                      // Why saved progress was ignored, if it was
+	recordSequence    int                        // Number of the next transcript in RecordDir
+	prompt            *prompt.Engine             // Instruction template and rule packs, loaded by validatePrerequisites
+	styleGuide        string                     // Contents of the style guide file
+	sourceLanguage    string                     // Given or detected source language of the current file
+	sourceCode        string                     // Language code of sourceLanguage, empty when unknown
+	timingPre         []srt.TimingStep           // Parsed TimingPre steps
+	timingPost        []srt.TimingStep           // Parsed TimingPost steps
+	sourceLayout      *srt.Layout                // Layout of the source file, kept with PreserveFormat
 }
 
 // NewTranslator creates a new translator instance
 func NewTranslator(cfg *config.Config) *Translator {
+	// Create provider
+	factory := &providers.ProviderFactory{}
+	provider, err := factory.NewProvider(cfg)
+	if err != nil {
+		// Log error but don't fail - will be handled during translation
+		logger.Warning(fmt.Sprintf("Failed to create provider: %v", err))
+	}
+
+	return NewTranslatorWithProvider(cfg, provider)
+}
+
+// NewTranslatorWithProvider creates a translator that uses an existing provider,
+// so several translations can share one client and API key pool
+func NewTranslatorWithProvider(cfg *config.Config, provider providers.TranslationProvider) *Translator {
 	baseFile := cfg.InputFile
 
 	var baseName, dirPath string
@@ -74,25 +120,7 @@ func NewTranslator(cfg *config.Config) *Translator {
 		dirPath = ""
 	}
 
-	// Set output file path
-	outputFile := cfg.OutputFile
-	if outputFile == "" {
-		suffix := "_translated.srt"
-
-		tl := strings.ToLower(cfg.TargetLanguage)
-		if langCode, ok := languages.GetLanguageCode(tl); ok {
-			suffix = "." + langCode + ".srt"
-		}
-
-		if cfg.InputFile == "" {
-			suffix = ".srt"
-		}
-		if dirPath != "" {
-			outputFile = filepath.Join(dirPath, baseName+suffix)
-		} else {
-			outputFile = baseName + suffix
-		}
-	}
+	outputFile := OutputPath(cfg)
 
 	// Set progress and log file paths
 	var progressFile, logFilePath, thoughtsFilePath string
@@ -106,14 +134,6 @@ func NewTranslator(cfg *config.Config) *Translator {
 		thoughtsFilePath = baseName + ".thoughts.log"
 	}
 
-	// Create provider
-	factory := &providers.ProviderFactory{}
-	provider, err := factory.NewProvider(cfg)
-	if err != nil {
-		// Log error but don't fail - will be handled during translation
-		logger.Warning(fmt.Sprintf("Failed to create provider: %v", err))
-	}
-
 	return &Translator{
 		config:           cfg,
 		provider:         provider,
@@ -123,6 +143,79 @@ func NewTranslator(cfg *config.Config) *Translator {
 		logFilePath:      logFilePath,
 		thoughtsFilePath: thoughtsFilePath,
 		context:          []providers.ContextMessage{},
+		decider:          newDecider(cfg),
+	}
+}
+
+// OutputPath returns the file a translation with cfg writes: the configured
+// output file, the rendered output template or the default naming
+func OutputPath(cfg *config.Config) string {
+	switch {
+	case cfg.OutputFile != "":
+		return cfg.OutputFile
+	case cfg.InputFile == "":
+		return filepath.Join(cfg.OutputDir, "translated.srt")
+	case cfg.OutputTemplate != "":
+		return renderOutputTemplate(cfg.OutputTemplate, outputVariables(cfg))
+	}
+	return defaultOutputPath(cfg)
+}
+
+// newDecider answers questions from the configured policy, asking on the
+// terminal for anything the policy leaves open unless running non-interactively
+func newDecider(cfg *config.Config) decision.Decider {
+	if cfg.NonInteractive {
+		return decision.NonInteractive(cfg.OnExistingOutput, cfg.OnTokenLimit, cfg.SubtitleTrack)
+	}
+	return &decision.Policy{
+		OnExistingOutput: cfg.OnExistingOutput,
+		OnTokenLimit:     cfg.OnTokenLimit,
+		Track:            cfg.SubtitleTrack,
+		Fallback:         decision.Terminal{},
+	}
+}
+
+// SetDecider replaces how questions during the translation are answered
+func (t *Translator) SetDecider(decider decision.Decider) {
+	t.decider = decider
+}
+
+// OutputFile returns the path the translation is written to
+func (t *Translator) OutputFile() string {
+	return t.outputFile
+}
+
+// ProgressFile returns the path of the resume state file
+func (t *Translator) ProgressFile() string {
+	return t.progressFile
+}
+
+// Context returns the conversation context of the last translated batch
+func (t *Translator) Context() []providers.ContextMessage {
+	return t.context
+}
+
+// SetContext seeds the conversation context of the first batch, e.g. with the
+// last batch of the previous episode
+func (t *Translator) SetContext(context []providers.ContextMessage) {
+	t.context = context
+}
+
+// Usage returns the token usage accumulated by this translator
+func (t *Translator) Usage() providers.Usage {
+	return t.usage
+}
+
+// SetProgressFunc registers a callback that receives the number of finished
+// and total lines after every batch
+func (t *Translator) SetProgressFunc(fn func(done int, total int)) {
+	t.progressFunc = fn
+}
+
+// reportProgress forwards progress to the registered callback
+func (t *Translator) reportProgress(done int, total int) {
+	if t.progressFunc != nil {
+		t.progressFunc(done, total)
 	}
 }
 
@@ -135,7 +228,9 @@ func (t *Translator) GetModels(ctx context.Context) ([]string, error) {
 }
 
 // Translate performs the main translation process
-func (t *Translator) Translate(ctx context.Context) error {
+func (t *Translator) Translate(ctx context.Context) (err error) {
+	defer func() { t.emitRunEnd(err) }()
+
 	// Validate prerequisites
 	if err := t.validatePrerequisites(); err != nil {
 		return err
@@ -147,7 +242,9 @@ func (t *Translator) Translate(ctx context.Context) error {
 	}
 
 	// Check saved progress
-	t.checkSavedProgress()
+	if err := t.checkSavedProgress(); err != nil {
+		return err
+	}
 
 	// Validate model availability
 	if err := t.validateModel(ctx); err != nil {
@@ -161,11 +258,42 @@ func (t *Translator) Translate(ctx context.Context) error {
 
 	// Perform translation
 	if t.config.InputFile != "" {
-		return t.performTranslation(ctx)
+		t.startedAt = time.Now()
+		err := t.performTranslation(ctx)
+		if err != nil && (stdErrors.Is(err, interrupt.ErrStopped) || ctx.Err() != nil) {
+			t.printResumeHint()
+		}
+		if errSave := t.saveUsageSummary(err); errSave != nil {
+			logger.Warning(fmt.Sprintf("Failed to save usage summary: %v", errSave))
+		}
+		if err == nil && t.requestCount > 0 {
+			logger.Info(fmt.Sprintf("Usage: %d requests, %d prompt tokens (%d cached), %d output tokens, %d thinking tokens",
+				t.requestCount, t.usage.PromptTokens, t.usage.CachedTokens, t.usage.OutputTokens, t.usage.ThinkingTokens))
+		}
+		return err
 	}
 	return fmt.Errorf("no input file provided")
 }
 
+// printResumeHint tells the user how to continue an interrupted translation
+func (t *Translator) printResumeHint() {
+	if t.selection != nil {
+		logger.Warning(fmt.Sprintf("Re-translation interrupted. Lines translated so far were written to %s.", t.outputFile))
+		return
+	}
+
+	data, err := os.ReadFile(t.progressFile)
+	if err != nil {
+		return
+	}
+	var progress ProgressInfo
+	if err = json.Unmarshal(data, &progress); err != nil {
+		return
+	}
+	logger.Warning(fmt.Sprintf("Translation interrupted. Progress was saved to %s, the next line is %d.", t.progressFile, progress.Line))
+	logger.Info("Run the same command again with --resume to continue where it stopped.")
+}
+
 // validatePrerequisites checks if all prerequisites are met
 func (t *Translator) validatePrerequisites() error {
 	if t.provider == nil {
@@ -176,7 +304,7 @@ func (t *Translator) validatePrerequisites() error {
 		return errors.NewValidationError("please provide a target language", nil)
 	}
 
-	return nil
+	return t.loadPrompt()
 }
 
 // validateConfig validates the configuration parameters
@@ -188,6 +316,45 @@ func (t *Translator) validateConfig() error {
 		return errors.NewFileError(fmt.Sprintf("input file %s does not exist", t.config.InputFile), err).WithContext("file_path", t.config.InputFile)
 	}
 
+	if err := t.validateModelOptions(); err != nil {
+		return err
+	}
+
+	if err := ValidateOutputTemplate(t.config.OutputTemplate); err != nil {
+		return err
+	}
+	if samePath(t.outputFile, t.config.InputFile) {
+		return errors.NewValidationError("the output file would overwrite the input file, choose another --output-template or --output-file", nil).WithContext("output_file", t.outputFile)
+	}
+	if err := validateTrackFlags(t.config.TrackFlags); err != nil {
+		return err
+	}
+	if err := t.parseTiming(); err != nil {
+		return err
+	}
+	if _, err := srt.ResolveCharset(t.config.InputEncoding); err != nil {
+		return errors.NewConfigurationError("invalid input encoding", err).WithContext("input_encoding", t.config.InputEncoding)
+	}
+	if _, err := srt.EncodeText("", t.config.OutputEncoding, t.config.OutputBOM); err != nil {
+		return errors.NewConfigurationError("invalid output encoding", err).WithContext("output_encoding", t.config.OutputEncoding)
+	}
+
+	policy := decision.Policy{OnExistingOutput: t.config.OnExistingOutput, OnTokenLimit: t.config.OnTokenLimit, Track: t.config.SubtitleTrack}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	selection, err := parseCueSelection(t.config.LineSelection, t.config.TimeSelection)
+	if err != nil {
+		return errors.NewConfigurationError("invalid cue selection", err).WithContext("lines", t.config.LineSelection).WithContext("time", t.config.TimeSelection)
+	}
+	t.selection = selection
+
+	return nil
+}
+
+// validateModelOptions validates the model tuning parameters
+func (t *Translator) validateModelOptions() error {
 	switch strings.ToLower(strings.TrimSpace(t.config.ThinkingLevel)) {
 	case "minimal", "low", "medium", "high":
 	default:
@@ -206,93 +373,73 @@ func (t *Translator) validateConfig() error {
 		return errors.NewConfigurationError("top K must be a non-negative integer", nil).WithContext("top_k", *t.config.TopK)
 	}
 
-	return nil
+	return t.validateSDHAnnotations()
 }
 
-// checkSavedProgress checks for saved progress and asks user to resume
-func (t *Translator) checkSavedProgress() {
-	if t.progressFile == "" || t.config.StartLine != 0 {
-		return
-	}
-
-	data, err := os.ReadFile(t.progressFile)
+// writeOutput writes the translated subtitles to the output file
+func (t *Translator) writeOutput(translatedSubtitles []srt.Subtitle) error {
+	translatedSubtitles, err := t.applyTimingPost(translatedSubtitles)
 	if err != nil {
-		return
-	}
-
-	var progress ProgressInfo
-	if err = json.Unmarshal(data, &progress); err != nil {
-		logger.Warning(fmt.Sprintf("Error reading progress file: %v", err))
-		return
-	}
-
-	// Verify the progress file matches our current input file
-	if progress.InputFile != t.config.InputFile {
-		logger.Warning(fmt.Sprintf("Found progress file for different subtitle: %s", progress.InputFile))
-		logger.Warning("Ignoring saved progress.")
-		return
+		return err
 	}
-
-	if progress.Line > 1 {
-		var resume string
-		if t.config.Resume == nil {
-			resume = strings.ToLower(strings.TrimSpace(logger.InputPrompt("Found saved progress. Resume? (y/n): ")))
-		} else if *t.config.Resume {
-			resume = "y"
-		} else {
-			resume = "n"
-		}
-
-		if resume == "y" || resume == "yes" {
-			logger.Info(fmt.Sprintf("Resuming from line %d", progress.Line))
-			t.config.StartLine = progress.Line
-		} else {
-			logger.Info("Starting from the beginning")
-			// Remove the existing output file
-			if err = os.Remove(t.outputFile); err != nil && !os.IsNotExist(err) {
-				logger.Warning(fmt.Sprintf("Failed to remove output file: %v", err))
-			}
-			// Remove existing progress file
-			if err = os.Remove(t.progressFile); err != nil && !os.IsNotExist(err) {
-				logger.Warning(fmt.Sprintf("Failed to remove progress file: %v", err))
-			}
-			// For MKV files, also remove extracted SRT file when restarting
-			if strings.HasSuffix(strings.ToLower(t.config.InputFile), ".mkv") {
-				extractedPath := t.getExtractedSRTPath()
-				if err = os.Remove(extractedPath); err != nil && !os.IsNotExist(err) {
-					logger.Warning(fmt.Sprintf("Failed to remove extracted SRT file: %v", err))
-				}
-			}
+	content := srt.ComposeSRT(translatedSubtitles)
+	charset, bom := t.config.OutputEncoding, t.config.OutputBOM
+	if t.sourceLayout != nil {
+		content = t.sourceLayout.Compose(translatedSubtitles)
+		if charset == "" {
+			charset, bom = t.sourceLayout.Charset, bom || t.sourceLayout.BOM
 		}
 	}
+	translatedContent, err := srt.EncodeText(content, charset, bom)
+	if err != nil {
+		return errors.NewFileError("failed to encode the output", err).WithContext("output_encoding", charset)
+	}
+	return WriteFileAtomic(t.outputFile, translatedContent)
 }
 
-// saveProgress saves current progress to file
-func (t *Translator) saveProgress(line int, translatedSubtitles []srt.Subtitle) {
-	if t.progressFile == "" {
-		return
+// samePath reports whether two paths name the same file
+func samePath(left string, right string) bool {
+	leftInfo, errLeft := os.Stat(left)
+	rightInfo, errRight := os.Stat(right)
+	if errLeft == nil && errRight == nil {
+		return os.SameFile(leftInfo, rightInfo)
 	}
+	leftAbs, errLeft := filepath.Abs(left)
+	rightAbs, errRight := filepath.Abs(right)
+	return errLeft == nil && errRight == nil && leftAbs == rightAbs
+}
 
-	progress := ProgressInfo{
-		Line:      line,
-		InputFile: t.config.InputFile,
+// WriteFileAtomic writes data to a temporary file next to path and renames it
+// into place, so an interrupted write never leaves a truncated file behind. A
+// file that is replaced keeps its permissions.
+func WriteFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, errStat := os.Stat(path); errStat == nil {
+		mode = info.Mode().Perm()
 	}
-
-	data, err := json.Marshal(progress)
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
 	if err != nil {
-		logger.Warning(fmt.Sprintf("Failed to marshal progress: %v", err))
-		return
+		return err
 	}
+	tmpFile := file.Name()
 
-	// Write translated subtitles to the file
-	translatedContent := srt.ComposeSRT(translatedSubtitles)
-	if err = os.WriteFile(t.outputFile, []byte(translatedContent), 0644); err != nil {
-		logger.Warning(fmt.Sprintf("failed to write output file: %v", err))
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
 	}
-
-	if err = os.WriteFile(t.progressFile, data, 0644); err != nil {
-		logger.Warning(fmt.Sprintf("Failed to save progress: %v", err))
+	if errClose := file.Close(); err == nil {
+		err = errClose
 	}
+	if err == nil {
+		err = os.Chmod(tmpFile, mode)
+	}
+	if err == nil {
+		err = os.Rename(tmpFile, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile)
+	}
+	return err
 }
 
 // validateModel checks if the specified model is available
@@ -329,61 +476,95 @@ func (t *Translator) performTranslation(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if err = os.MkdirAll(filepath.Dir(t.outputFile), 0755); err != nil {
+		return errors.NewFileError("failed to create output directory", err).WithContext("dir_path", filepath.Dir(t.outputFile))
+	}
 
 	// Read original subtitle file
 	originalData, err := os.ReadFile(srtFile)
 	if err != nil {
 		return errors.NewFileError("failed to read input file", err).WithContext("file_path", srtFile)
 	}
+	t.sourceHash = hashBytes(originalData)
 
-	originalSubtitles, err := srt.ParseSRT(string(originalData))
+	originalSubtitles, err := t.parseSource(originalData, srtFile)
 	if err != nil {
-		return errors.NewFileError("failed to parse SRT file", err).WithContext("file_path", srtFile)
+		return err
 	}
+	if err = srt.ApplyTiming(originalSubtitles, t.timingPre); err != nil {
+		return errors.NewConfigurationError("failed to apply timing steps", err).WithContext("file_path", srtFile)
+	}
+	t.resolveSourceLanguage(originalSubtitles)
 
 	// Load or create translated subtitles
 	var translatedSubtitles []srt.Subtitle
 	if _, err = os.Stat(t.outputFile); err == nil {
 		translatedData, errRead := os.ReadFile(t.outputFile)
 		if errRead == nil {
-			translatedSubtitles, errRead = srt.ParseSRT(string(translatedData))
+			translatedSubtitles, _, errRead = srt.ParseCharset(translatedData, t.config.OutputEncoding)
 			if errRead == nil {
 				logger.Info(fmt.Sprintf("Translated file %s already exists. Loading existing translation...\n", t.outputFile))
 
-				// Prompt for start line if not set
-				if t.config.StartLine == 0 {
-					for {
-						input := logger.InputPrompt(fmt.Sprintf("Enter the line number to start from (1 to %d): ", len(originalSubtitles)))
-						startLine, errParse := strconv.Atoi(strings.TrimSpace(input))
-						if errParse != nil || startLine < 1 || startLine > len(originalSubtitles) {
-							logger.Warning(fmt.Sprintf("Line number must be between 1 and %d. Please try again.", len(originalSubtitles)))
-							continue
+				// Ask for the start line if not set
+				if t.config.StartLine == 0 && t.selection == nil {
+					startLine, errDecide := t.decider.StartLine(len(originalSubtitles))
+					if errDecide != nil {
+						if t.progressRejection != nil {
+							return errors.NewValidationError(errDecide.Error(), t.progressRejection)
 						}
-						t.config.StartLine = startLine
-						break
+						return errDecide
+					}
+					if startLine == 1 {
+						// Starting over, translate into a fresh copy of the original
+						translatedSubtitles = nil
 					}
+					t.config.StartLine = startLine
 				}
 			}
 		}
 	}
 
+	if t.selection != nil {
+		if len(translatedSubtitles) == 0 {
+			return errors.NewValidationError("selective re-translation requires an existing translated output file", nil).WithContext("output_file", t.outputFile)
+		}
+		if len(originalSubtitles) != len(translatedSubtitles) {
+			return errors.NewValidationError("number of lines of existing translated file does not match the number of lines in the original file", nil).WithContext("original_count", len(originalSubtitles)).WithContext("translated_count", len(translatedSubtitles))
+		}
+		t.restoreSourceTimings(originalSubtitles, translatedSubtitles)
+		return t.performSelectiveTranslation(ctx, originalSubtitles, translatedSubtitles)
+	}
+
 	if len(translatedSubtitles) == 0 {
 		// Copy original subtitles as template
 		translatedSubtitles = make([]srt.Subtitle, len(originalSubtitles))
 		copy(translatedSubtitles, originalSubtitles)
 		t.config.StartLine = 1
+		t.completed = nil
 	}
 
 	// Validate subtitle count consistency
 	if len(originalSubtitles) != len(translatedSubtitles) {
 		return errors.NewValidationError("number of lines of existing translated file does not match the number of lines in the original file", nil).WithContext("original_count", len(originalSubtitles)).WithContext("translated_count", len(translatedSubtitles))
 	}
+	t.restoreSourceTimings(originalSubtitles, translatedSubtitles)
 
 	// Validate start line
 	if t.config.StartLine > len(originalSubtitles) || t.config.StartLine < 1 {
 		return errors.NewValidationError(fmt.Sprintf("start line must be between 1 and %d", len(originalSubtitles)), nil).WithContext("start_line", t.config.StartLine).WithContext("max_lines", len(originalSubtitles))
 	}
 
+	// Lines before the start line count as translated unless saved progress says otherwise
+	if len(t.completed) != len(originalSubtitles) {
+		t.completed = make([]bool, len(originalSubtitles))
+		for j := 0; j < t.config.StartLine-1; j++ {
+			t.completed[j] = true
+		}
+	}
+
+	// Cues already in the target language keep their text and are not sent
+	keep := t.targetLanguageCues(originalSubtitles)
+
 	// Adjust batch size if needed
 	if len(originalSubtitles) < t.config.BatchSize {
 		t.config.BatchSize = len(originalSubtitles)
@@ -414,46 +595,26 @@ func (t *Translator) performTranslation(ctx context.Context) error {
 	var batch []srt.SubtitleObject
 
 	// Build context from previous translations if resuming
-	if t.config.StartLine > 1 {
+	if len(t.resumeContext) > 0 {
+		t.context = t.resumeContext
+	} else if t.config.StartLine > 1 {
 		startIdx := max(0, t.config.StartLine-2-t.config.BatchSize)
-		var userBatch []srt.SubtitleObject
-		var modelBatch []srt.SubtitleObject
-
-		for j := startIdx; j < t.config.StartLine-1; j++ {
-			objUser := srt.SubtitleObject{
-				Index:   j,
-				Content: normalizeSubtitleContentForModel(originalSubtitles[j].Content),
-				Guard:   t.lineGuard(j),
-			}
-
-			objModel := srt.SubtitleObject{
-				Index:   j,
-				Content: normalizeSubtitleContentForModel(translatedSubtitles[j].Content),
-				Guard:   t.lineGuard(j),
-			}
-
-			userBatch = append(userBatch, objUser)
-			modelBatch = append(modelBatch, objModel)
-		}
-
-		userData, _ := json.Marshal(userBatch)
-		modelData, _ := json.Marshal(modelBatch)
-
-		t.context = []providers.ContextMessage{
-			{Role: "user", Content: string(userData)},
-			{Role: "model", Content: string(modelData)},
-		}
+		t.context = t.buildContextMessages(originalSubtitles, translatedSubtitles, startIdx, t.config.StartLine-1)
 	}
 
 	progressBar.Update(i)
+	t.reportProgress(i, total)
+	t.emitRunStarted(i, total)
 
 	// Add first subtitle to batch
-	obj := srt.SubtitleObject{
-		Index:   i,
-		Content: originalSubtitles[i].Content,
+	if i = t.skipKeptCues(keep, i, originalSubtitles, translatedSubtitles); i < total {
+		obj := srt.SubtitleObject{
+			Index:   i,
+			Content: originalSubtitles[i].Content,
+		}
+		batch = append(batch, obj)
+		i++
 	}
-	batch = append(batch, obj)
-	i++
 
 	// Save initial progress
 	t.saveProgress(i, translatedSubtitles)
@@ -462,6 +623,9 @@ func (t *Translator) performTranslation(ctx context.Context) error {
 	for i < total || len(batch) > 0 {
 		// Build batch
 		for i < total && len(batch) < t.config.BatchSize {
+			if i = t.skipKeptCues(keep, i, originalSubtitles, translatedSubtitles); i == total {
+				break
+			}
 			subtitleObj := srt.SubtitleObject{
 				Index:   i,
 				Content: originalSubtitles[i].Content,
@@ -470,10 +634,24 @@ func (t *Translator) performTranslation(ctx context.Context) error {
 			i++
 		}
 
+		if len(batch) == 0 {
+			// The remaining cues are all kept as they are
+			if err = t.writeOutput(translatedSubtitles); err != nil {
+				return errors.NewFileError("failed to write output file", err).WithContext("file_path", t.outputFile)
+			}
+			break
+		}
+
 		// Validate token size
 		guardedBatch := t.withLineGuards(batch)
 		if err = t.validateTokenSize(ctx, guardedBatch); err != nil {
-			return err
+			if !stdErrors.Is(err, errBatchTooLarge) || len(batch) <= t.config.BatchSize {
+				return err
+			}
+			// Hand the lines beyond the reduced batch size back and retry
+			i = batch[t.config.BatchSize].Index
+			batch = batch[:t.config.BatchSize]
+			continue
 		}
 
 		if i == total && len(batch) < t.config.BatchSize {
@@ -482,6 +660,7 @@ func (t *Translator) performTranslation(ctx context.Context) error {
 
 		// Process batch
 		startTime := time.Now()
+		usageBefore := t.usage
 		newContext, errProcessBatch := t.processBatch(ctx, guardedBatch, translatedSubtitles, progressBar)
 		if errProcessBatch != nil {
 			return errProcessBatch
@@ -489,16 +668,26 @@ func (t *Translator) performTranslation(ctx context.Context) error {
 		endTime := time.Now()
 
 		t.context = newContext
+		t.markCompleted(batch)
 
 		// Update progress
 		progressBar.Update(i)
+		t.reportProgress(i, total)
+		t.emitBatchCompleted(batch, i, total, usageBefore)
 		t.saveProgress(i+1, translatedSubtitles)
 
+		// Stop between batches when interrupted; output and progress are in sync here
+		if i < total && interrupt.Requested(ctx) {
+			return errors.NewTranslationError("translation interrupted", interrupt.ErrStopped).WithContext("next_line", i+1)
+		}
+
 		// Apply delay if needed
 		if delay {
 			elapsed := endTime.Sub(startTime)
 			if elapsed < delayTime && i < total {
-				time.Sleep(delayTime - elapsed)
+				if err = waitOrStop(ctx, delayTime-elapsed); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -532,6 +721,124 @@ func (t *Translator) performTranslation(ctx context.Context) error {
 	return nil
 }
 
+// performSelectiveTranslation re-translates only the selected cues and splices them
+// into the existing translation, leaving every other line untouched
+func (t *Translator) performSelectiveTranslation(ctx context.Context, originalSubtitles []srt.Subtitle, translatedSubtitles []srt.Subtitle) error {
+	indices := t.selection.indices(originalSubtitles)
+	if len(indices) == 0 {
+		return errors.NewValidationError("no subtitle lines match the selection", nil).WithContext("lines", t.config.LineSelection).WithContext("time", t.config.TimeSelection)
+	}
+
+	logger.Highlight(fmt.Sprintf("Re-translating %d selected lines using %s...\n", len(indices), t.provider.GetName()))
+
+	progressBar := logger.NewProgressBar(len(indices), "Translating:")
+	defer progressBar.Stop()
+
+	progressBar.SetSuffix(t.config.ModelName)
+	progressBar.SetSending(true)
+
+	t.emitRunStarted(0, len(indices))
+
+	done := 0
+	for remaining := indices; len(remaining) > 0; {
+		// Grouped again each time, the batch size may have been reduced
+		group := groupSelectedIndices(remaining, t.config.BatchSize)[0]
+		first := group[0]
+		last := group[len(group)-1]
+
+		// Surround the batch with the neighbouring source and existing translation
+		var contextMessages []providers.ContextMessage
+		contextMessages = append(contextMessages, t.buildContextMessages(originalSubtitles, translatedSubtitles, max(0, first-t.config.BatchSize), first)...)
+		contextMessages = append(contextMessages, t.buildContextMessages(originalSubtitles, translatedSubtitles, last+1, min(len(originalSubtitles), last+1+t.config.BatchSize))...)
+		t.context = contextMessages
+
+		batch := make([]srt.SubtitleObject, 0, len(group))
+		for _, index := range group {
+			batch = append(batch, srt.SubtitleObject{
+				Index:   index,
+				Content: originalSubtitles[index].Content,
+			})
+		}
+
+		guardedBatch := t.withLineGuards(batch)
+		if err := t.validateTokenSize(ctx, guardedBatch); err != nil {
+			if !stdErrors.Is(err, errBatchTooLarge) || len(group) <= t.config.BatchSize {
+				return err
+			}
+			// Retry the lines with the reduced batch size
+			continue
+		}
+
+		usageBefore := t.usage
+		if _, err := t.processBatch(ctx, guardedBatch, translatedSubtitles, progressBar); err != nil {
+			return err
+		}
+
+		remaining = remaining[len(group):]
+		done += len(group)
+		progressBar.Update(done)
+		t.reportProgress(done, len(indices))
+		t.emitBatchCompleted(batch, done, len(indices), usageBefore)
+
+		if err := t.writeOutput(translatedSubtitles); err != nil {
+			return errors.NewFileError("failed to write output file", err).WithContext("file_path", t.outputFile)
+		}
+
+		if done < len(indices) && interrupt.Requested(ctx) {
+			return errors.NewTranslationError("re-translation interrupted", interrupt.ErrStopped).WithContext("translated_lines", done)
+		}
+	}
+
+	progressBar.Stop()
+
+	logger.Success(fmt.Sprintf("Re-translated %d lines successfully!", len(indices)))
+	if t.config.ProgressLog {
+		if err := logger.SaveLogsToFile(t.logFilePath); err != nil {
+			logger.Warning(fmt.Sprintf("Failed to save logs: %v", err))
+		}
+	}
+
+	t.cleanup()
+
+	return nil
+}
+
+// buildContextMessages builds a user/model message pair from the source and
+// translated lines in [from, to) so the model sees how they were translated
+func (t *Translator) buildContextMessages(originalSubtitles []srt.Subtitle, translatedSubtitles []srt.Subtitle, from int, to int) []providers.ContextMessage {
+	if from >= to {
+		return nil
+	}
+
+	var userBatch []srt.SubtitleObject
+	var modelBatch []srt.SubtitleObject
+
+	for j := from; j < to; j++ {
+		objUser := srt.SubtitleObject{
+			Index:   j,
+			Content: normalizeSubtitleContentForModel(originalSubtitles[j].Content),
+			Guard:   t.lineGuard(j),
+		}
+
+		objModel := srt.SubtitleObject{
+			Index:   j,
+			Content: normalizeSubtitleContentForModel(translatedSubtitles[j].Content),
+			Guard:   t.lineGuard(j),
+		}
+
+		userBatch = append(userBatch, objUser)
+		modelBatch = append(modelBatch, objModel)
+	}
+
+	userData, _ := json.Marshal(userBatch)
+	modelData, _ := json.Marshal(modelBatch)
+
+	return []providers.ContextMessage{
+		{Role: "user", Content: string(userData)},
+		{Role: "model", Content: string(modelData)},
+	}
+}
+
 // validateTokenSize validates that the batch doesn't exceed token limits
 func (t *Translator) validateTokenSize(ctx context.Context, batch []srt.SubtitleObject) error {
 	batchData, err := json.Marshal(batch)
@@ -548,25 +855,22 @@ func (t *Translator) validateTokenSize(ctx context.Context, batch []srt.Subtitle
 
 	// Check if token count exceeds 90% of limit
 	if t.tokenLimit != 0 && float64(tokenCount) > float64(t.tokenLimit)*0.9 {
-		// This is a critical error that requires user input, so we break the progress bar display
-		fmt.Printf("\n\n") // Add some spacing
-		logger.Error(fmt.Sprintf("Token size (%d) exceeds limit (%d) for %s", int(float64(tokenCount)/0.9), t.tokenLimit, t.config.ModelName))
-
-		// Ask user for new batch size
-		for {
-			input := logger.InputPrompt(fmt.Sprintf("Please enter a new batch size (current: %d): ", t.config.BatchSize))
-			newBatchSize, errAtoi := strconv.Atoi(strings.TrimSpace(input))
-			if errAtoi != nil || newBatchSize <= 0 {
-				logger.Warning("Invalid input. Batch size must be a positive integer.")
-				continue
-			}
+		if !t.headless {
+			// This is a critical error that may require user input, so we break the progress bar display
+			fmt.Printf("\n\n") // Add some spacing
+			logger.Error(fmt.Sprintf("Token size (%d) exceeds limit (%d) for %s", int(float64(tokenCount)/0.9), t.tokenLimit, t.config.ModelName))
+		}
 
-			t.config.BatchSize = newBatchSize
+		newBatchSize, errDecide := t.decider.BatchSize(t.config.BatchSize, tokenCount, t.tokenLimit)
+		if errDecide != nil {
+			return errDecide
+		}
+		t.config.BatchSize = newBatchSize
+		if !t.headless {
 			logger.Info(fmt.Sprintf("Batch size updated to %d.", t.config.BatchSize))
-			break
 		}
 
-		return errors.NewValidationError("batch size too large, please retry with smaller batch", nil).WithContext("current_batch_size", t.config.BatchSize).WithContext("token_count", tokenCount).WithContext("token_limit", t.tokenLimit)
+		return errors.NewValidationError("batch size too large, please retry with smaller batch", errBatchTooLarge).WithContext("current_batch_size", t.config.BatchSize).WithContext("token_count", tokenCount).WithContext("token_limit", t.tokenLimit)
 	}
 
 	return nil
@@ -576,6 +880,7 @@ func (t *Translator) validateTokenSize(ctx context.Context, batch []srt.Subtitle
 func (t *Translator) withLineGuards(batch []srt.SubtitleObject) []srt.SubtitleObject {
 	guardedBatch := make([]srt.SubtitleObject, len(batch))
 	for i, item := range batch {
+		item = t.withSegments(item)
 		item.Content = normalizeSubtitleContentForModel(item.Content)
 		item.Guard = t.lineGuard(item.Index)
 		guardedBatch[i] = item
@@ -602,40 +907,106 @@ func (t *Translator) lineGuard(index int) string {
 func (t *Translator) processBatch(ctx context.Context, batch []srt.SubtitleObject, translatedSubtitles []srt.Subtitle, progressBar *logger.ProgressBar) ([]providers.ContextMessage, error) {
 	var lastErr error
 	retryInstruction := ""
-	progressWrapper := &ProgressBarWrapper{bar: progressBar}
+	progressWrapper := &ProgressBarWrapper{bar: progressBar, onThinking: func() {
+		t.emit(events.Event{Type: events.Thinking, Batch: t.batchNumber})
+	}}
+
+	// Headless translations run without a progress bar
+	printAbove := func(message string, color string, args ...any) {
+		if progressBar != nil {
+			progressBar.PrintErrorAbove(message, color, args...)
+		}
+	}
+	debug := func(message string, args ...any) {
+		if !t.headless {
+			logger.Debug(message, args...)
+		}
+	}
 
 	for attempt := 0; attempt <= t.config.RetryCount; attempt++ {
 		if attempt > 0 {
-			progressBar.PrintErrorAbove(fmt.Sprintf("Retry attempt %d/%d", attempt, t.config.RetryCount), logger.Yellow)
-			progressBar.AddRetry()
-			retryInstruction = t.buildRetryInstruction(lastErr)
+			printAbove(fmt.Sprintf("Retry attempt %d/%d", attempt, t.config.RetryCount), logger.Yellow, t.batchAttrs(batch, attempt)...)
+			if progressBar != nil {
+				progressBar.AddRetry()
+			}
+			retryInstruction = t.buildRetryInstruction(lastErr, batch)
+			t.emit(events.Event{Type: events.Retry, Batch: t.batchNumber, Attempt: attempt, Reason: lastErr.Error()})
 
 			// Try to switch API key if provider supports it
-			if keySwitcher, ok := t.provider.(providers.KeySwitcher); ok {
+			if keySwitcher, ok := providers.AsKeySwitcher(t.provider); ok {
 				if keySwitcher.SwitchAPIKey() {
-					progressBar.PrintErrorAbove(fmt.Sprintf("Switching to API Key %d", keySwitcher.GetCurrentAPIKeyIndex()+1), logger.Yellow)
+					printAbove(fmt.Sprintf("Switching to API Key %d", keySwitcher.GetCurrentAPIKeyIndex()+1), logger.Yellow, t.batchAttrs(batch, attempt)...)
+					t.emit(events.Event{Type: events.KeySwitched, Batch: t.batchNumber, Key: keySwitcher.GetCurrentAPIKeyIndex() + 1})
 				}
 			}
 
 			// Add small delay between retries
-			time.Sleep(time.Duration(attempt) * 2 * time.Second)
+			if err := waitOrStop(ctx, time.Duration(attempt)*2*time.Second); err != nil {
+				return nil, err
+			}
 		}
 
-		result, errProcess := t.processBatchAttempt(ctx, batch, translatedSubtitles, progressWrapper, retryInstruction)
+		first, last := batchLines(batch)
+		t.emit(events.Event{Type: events.BatchSent, Batch: t.batchNumber, FirstLine: first, LastLine: last, Attempt: attempt + 1})
+		attrs := t.batchAttrs(batch, attempt)
+		debug("Sending batch", attrs...)
+
+		usageBefore := t.usage
+		result, errProcess := t.processBatchAttempt(ctx, batch, translatedSubtitles, progressWrapper, retryInstruction, attempt)
 		if errProcess == nil {
 			// No need to clear messages anymore - errors stay in terminal history
+			debug("Batch translated", append(attrs,
+				"prompt_tokens", t.usage.PromptTokens-usageBefore.PromptTokens,
+				"output_tokens", t.usage.OutputTokens-usageBefore.OutputTokens,
+				"latency", t.usage.Latency-usageBefore.Latency)...)
 			return result, nil
 		}
 
+		if ctx.Err() != nil {
+			// Aborted, retrying would only fail again
+			return nil, errProcess
+		}
+
 		lastErr = errProcess
-		progressBar.PrintErrorAbove(fmt.Sprintf("Batch processing failed (attempt %d/%d): %v", attempt+1, t.config.RetryCount+1, errProcess), logger.Red)
+		printAbove(fmt.Sprintf("Batch processing failed (attempt %d/%d): %v", attempt+1, t.config.RetryCount+1, errProcess), logger.Red, attrs...)
 	}
 
 	return nil, fmt.Errorf("batch processing failed after %d retries: %w", t.config.RetryCount, lastErr)
 }
 
+// batchAttrs returns the log attributes that identify a batch request
+func (t *Translator) batchAttrs(batch []srt.SubtitleObject, attempt int) []any {
+	first, last := batchLines(batch)
+	attrs := []any{"batch", t.batchNumber, "first_line", first, "last_line", last, "attempt", attempt + 1}
+	if !t.headless {
+		attrs = append(attrs, "file", t.config.InputFile)
+	}
+	if t.provider != nil {
+		attrs = append(attrs, "provider", t.provider.GetName())
+		if keySwitcher, ok := providers.AsKeySwitcher(t.provider); ok {
+			attrs = append(attrs, "key_index", keySwitcher.GetCurrentAPIKeyIndex()+1)
+		}
+	}
+	return attrs
+}
+
+// waitOrStop sleeps for d, returning early with an error when ctx is cancelled
+// or a graceful stop is requested
+func waitOrStop(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-interrupt.Stopped(ctx):
+		return errors.NewTranslationError("translation interrupted", interrupt.ErrStopped)
+	}
+}
+
 // buildRetryInstruction creates correction instructions for the next retry.
-func (t *Translator) buildRetryInstruction(err error) string {
+func (t *Translator) buildRetryInstruction(err error, batch []srt.SubtitleObject) string {
 	if err == nil {
 		return ""
 	}
@@ -648,6 +1019,9 @@ func (t *Translator) buildRetryInstruction(err error) string {
 	builder.WriteString("No content string may contain unescaped literal line breaks, carriage returns, or control characters; replace any line break, carriage return, or source subtitle \\n, \\r, or \\r\\n with four spaces.\n")
 	builder.WriteString("Treat each input object as one complete subtitle unit. Do not split one content value into multiple output objects because it contains escaped line separators, visual line breaks, or multiple short phrases.\n")
 	builder.WriteString("The output object count, order, index values, and guard values must exactly match the current input array.\n")
+	if hasSegments(batch) {
+		builder.WriteString("Objects with a segments array must return the same number of segments, in the same order and with the same kind values, each with its text translated.\n")
+	}
 	builder.WriteString("Copy each guard value unchanged. Do not translate, remove, rename, or move guard values between objects.\n")
 
 	var translatorErr *errors.TranslatorError
@@ -688,30 +1062,33 @@ func (t *Translator) responseErrorExcerpt(err error, responseText string) string
 }
 
 // processBatchAttempt performs a single attempt to process a batch
-func (t *Translator) processBatchAttempt(ctx context.Context, batch []srt.SubtitleObject, translatedSubtitles []srt.Subtitle, progressWrapper *ProgressBarWrapper, retryInstruction string) ([]providers.ContextMessage, error) {
+func (t *Translator) processBatchAttempt(ctx context.Context, batch []srt.SubtitleObject, translatedSubtitles []srt.Subtitle, progressWrapper *ProgressBarWrapper, retryInstruction string, attempt int) ([]providers.ContextMessage, error) {
 	// Create translation config
-	translationConfig := &providers.TranslationConfig{
-		ModelName:        t.config.ModelName,
-		TargetLanguage:   t.config.TargetLanguage,
-		Description:      t.config.Description,
-		RetryInstruction: retryInstruction,
-		Temperature:      t.config.Temperature,
-		TopP:             t.config.TopP,
-		TopK:             t.config.TopK,
-		Streaming:        t.config.Streaming,
-		Thinking:         t.config.Thinking,
-		ThinkingLevel:    t.config.ThinkingLevel,
-		ProgressUpdater:  progressWrapper,
+	translationConfig := t.translationConfig(batch, len(translatedSubtitles), retryInstruction)
+	translationConfig.ProgressUpdater = progressWrapper
+	if t.config.RecordDir != "" {
+		translationConfig.Exchange = &providers.Exchange{}
 	}
 
 	// Call provider to translate batch
 	response, err := t.provider.TranslateBatch(ctx, batch, t.context, translationConfig)
+
+	// Account for the tokens of every answered request, valid or not
+	if response != nil {
+		t.requestCount++
+		t.usage.Add(response.Usage)
+		progressWrapper.SetTokenUsage(t.usage)
+	}
 	if err != nil {
+		t.recordExchange(batch, attempt, translationConfig.Exchange, err, nil)
 		return nil, err
 	}
+	t.saveThoughts(batch, attempt, response)
 
 	// Validate response content
-	if errValidate := t.validateTranslatedResponse(response.TranslatedBatch, batch); errValidate != nil {
+	errValidate := t.validateTranslatedResponse(response.TranslatedBatch, batch)
+	t.recordExchange(batch, attempt, translationConfig.Exchange, nil, errValidate)
+	if errValidate != nil {
 		return nil, errValidate
 	}
 
@@ -740,13 +1117,26 @@ func (t *Translator) processTranslatedLines(translatedLines []srt.SubtitleObject
 	for _, line := range translatedLines {
 		index := line.Index
 
-		// Apply RTL detection and formatting
-		if t.isDominantRTL(line.Content) {
-			translatedSubtitles[index].Content = "\u202b" + line.Content + "\u202c"
-		} else if len(line.Content) == 0 {
+		// Segmented lines get back the dash and label layout of their source
+		content := line.Content
+		if position, ok := indexMap[index]; ok && batch[position].Segments != nil {
+			content = srt.JoinSegments(batch[position].Segments, line.Segments)
+		}
+
+		// Embed right-to-left lines unless the target language is known to be written left to right
+		rtl := t.isDominantRTL(content)
+		if language, ok := languages.Lookup(t.config.TargetLanguage); ok && !language.RTL {
+			rtl = false
+		}
+		switch {
+		case rtl:
+			translatedSubtitles[index].Content = "\u202b" + content + "\u202c"
+		case len(content) == 0 && t.config.PreserveFormat:
+			// The cue keeps its text so its lines are written as they were
+		case len(content) == 0:
 			translatedSubtitles[index].Content = " "
-		} else {
-			translatedSubtitles[index].Content = line.Content
+		default:
+			translatedSubtitles[index].Content = content
 		}
 	}
 
@@ -770,6 +1160,11 @@ func (t *Translator) validateTranslatedResponse(translatedBatch []srt.SubtitleOb
 		if translated.Content == "" && original.Content != "" && !t.isOnlyPunctuation(original.Content) {
 			return errors.NewTranslationError(fmt.Sprintf("provider returned an empty translation for line %d", translated.Index), nil).WithContext("line_index", translated.Index)
 		}
+		if original.Segments != nil {
+			if err := validateSegments(translated, original); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -837,7 +1232,7 @@ func (t *Translator) prepareSRTFile() (string, error) {
 
 		logger.Info("MKV file detected. Extracting subtitles...")
 
-		newExtractedPath, err := video.ExtractSubtitlesFromMKV(inputFile)
+		newExtractedPath, err := video.ExtractSubtitlesFromMKV(inputFile, t.decider, t.config.SourceLanguage, t.config.TargetLanguage, t.config.InputEncoding)
 		if err != nil {
 			return "", errors.NewFileError("failed to extract subtitles from MKV file", err).WithContext("mkv_path", inputFile)
 		}
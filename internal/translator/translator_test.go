@@ -3,10 +3,15 @@ package translator
 import (
 	"context"
 	"encoding/json"
+	stdErrors "errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/luispater/gemini-srt-translator-go/internal/interrupt"
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
 	"github.com/luispater/gemini-srt-translator-go/internal/providers"
 	"github.com/luispater/gemini-srt-translator-go/pkg/config"
 	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
@@ -308,3 +313,126 @@ func (m *mockProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleO
 func (m *mockProvider) GetName() string {
 	return "mock"
 }
+
+func TestTranslator_nonInteractive(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	tempDir := t.TempDir()
+	inputPath := filepath.Join(tempDir, "episode.srt")
+	var subtitles []srt.Subtitle
+	for i := 0; i < 40; i++ {
+		subtitles = append(subtitles, srt.Subtitle{
+			Index:   i + 1,
+			Start:   time.Duration(i) * time.Second,
+			End:     time.Duration(i)*time.Second + 500*time.Millisecond,
+			Content: strings.Repeat("word ", 5),
+		})
+	}
+	if err := os.WriteFile(inputPath, []byte(srt.ComposeSRT(subtitles)), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	newTranslator := func(onExistingOutput string) *Translator {
+		return NewTranslatorWithProvider(&config.Config{
+			InputFile:        inputPath,
+			TargetLanguage:   "French",
+			ModelName:        "mock-model",
+			BatchSize:        40,
+			ThinkingLevel:    "high",
+			NonInteractive:   true,
+			OnExistingOutput: onExistingOutput,
+		}, &mockProvider{})
+	}
+
+	// 40 lines exceed the mock token limit, so the batch size is halved until a batch fits
+	translator := newTranslator("")
+	if err := translator.Translate(context.Background()); err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+	if translator.config.BatchSize >= 40 {
+		t.Errorf("Expected batch size to shrink, got %d", translator.config.BatchSize)
+	}
+
+	// The output now exists without saved progress
+	if err := newTranslator("").Translate(context.Background()); err == nil {
+		t.Error("Expected existing output to fail by default")
+	}
+	if err := newTranslator("overwrite").Translate(context.Background()); err != nil {
+		t.Errorf("Translate() with overwrite failed: %v", err)
+	}
+}
+
+// stoppingProvider requests a graceful stop while the first batch is in flight
+type stoppingProvider struct {
+	mockProvider
+	stop    chan struct{}
+	batches int
+}
+
+func (s *stoppingProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []providers.ContextMessage, config *providers.TranslationConfig) (*providers.TranslationResponse, error) {
+	s.batches++
+	if s.batches == 1 {
+		close(s.stop)
+	}
+	return s.mockProvider.TranslateBatch(ctx, batch, previousContext, config)
+}
+
+func TestTranslator_gracefulStop(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	tempDir := t.TempDir()
+	inputPath := filepath.Join(tempDir, "episode.srt")
+	var subtitles []srt.Subtitle
+	for i := 0; i < 10; i++ {
+		subtitles = append(subtitles, srt.Subtitle{
+			Index:   i + 1,
+			Start:   time.Duration(i) * time.Second,
+			End:     time.Duration(i)*time.Second + 500*time.Millisecond,
+			Content: "line",
+		})
+	}
+	if err := os.WriteFile(inputPath, []byte(srt.ComposeSRT(subtitles)), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	provider := &stoppingProvider{stop: make(chan struct{})}
+	translator := NewTranslatorWithProvider(&config.Config{
+		InputFile:      inputPath,
+		TargetLanguage: "French",
+		ModelName:      "mock-model",
+		BatchSize:      4,
+		ThinkingLevel:  "high",
+		NonInteractive: true,
+	}, provider)
+
+	ctx := interrupt.WithStop(context.Background(), provider.stop)
+	err := translator.Translate(ctx)
+	if !stdErrors.Is(err, interrupt.ErrStopped) {
+		t.Fatalf("Translate() error = %v, want ErrStopped", err)
+	}
+	if provider.batches != 1 {
+		t.Errorf("Expected the in-flight batch to finish and no further batch, got %d batches", provider.batches)
+	}
+
+	data, err := os.ReadFile(translator.progressFile)
+	if err != nil {
+		t.Fatalf("Expected progress file after stop: %v", err)
+	}
+	var progress ProgressInfo
+	if err = json.Unmarshal(data, &progress); err != nil {
+		t.Fatalf("Failed to parse progress: %v", err)
+	}
+	if progress.Line != 5 {
+		t.Errorf("Saved line = %d, want 5", progress.Line)
+	}
+	if _, err = os.Stat(translator.outputFile); err != nil {
+		t.Errorf("Expected output file after stop: %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(tempDir, ".*.tmp"))
+	if len(matches) > 0 {
+		t.Errorf("Temporary files left behind: %v", matches)
+	}
+}
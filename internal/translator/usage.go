@@ -0,0 +1,85 @@
+package translator
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/pricing"
+)
+
+// UsageSummary is the per-run usage report written next to the output file
+type UsageSummary struct {
+	Provider       string    `json:"provider"`
+	Model          string    `json:"model"`
+	InputFile      string    `json:"input_file"`
+	OutputFile     string    `json:"output_file"`
+	Status         string    `json:"status"`
+	StartedAt      time.Time `json:"started_at"`
+	FinishedAt     time.Time `json:"finished_at"`
+	Requests       int       `json:"requests"`
+	PromptTokens   int64     `json:"prompt_tokens"`
+	OutputTokens   int64     `json:"output_tokens"`
+	ThinkingTokens int64     `json:"thinking_tokens"`
+	CachedTokens   int64     `json:"cached_tokens"`
+	LatencyMillis  int64     `json:"latency_ms"`
+	EstimatedCost  *float64  `json:"estimated_cost_usd,omitempty"`
+	PricedAs       string    `json:"priced_as,omitempty"` // Price table entry of the model family used when the model has none
+}
+
+// usageSummaryPath returns "<output without extension>.usage.json"
+func (t *Translator) usageSummaryPath() string {
+	return strings.TrimSuffix(t.outputFile, filepath.Ext(t.outputFile)) + ".usage.json"
+}
+
+// buildUsageSummary collects the accumulated usage of this run
+func (t *Translator) buildUsageSummary(runErr error) *UsageSummary {
+	summary := &UsageSummary{
+		Model:          t.config.ModelName,
+		InputFile:      t.config.InputFile,
+		OutputFile:     t.outputFile,
+		Status:         "completed",
+		StartedAt:      t.startedAt,
+		FinishedAt:     time.Now(),
+		Requests:       t.requestCount,
+		PromptTokens:   t.usage.PromptTokens,
+		OutputTokens:   t.usage.OutputTokens,
+		ThinkingTokens: t.usage.ThinkingTokens,
+		CachedTokens:   t.usage.CachedTokens,
+		LatencyMillis:  t.usage.Latency.Milliseconds(),
+	}
+	if t.provider != nil {
+		summary.Provider = t.provider.GetName()
+	}
+	if runErr != nil {
+		summary.Status = "failed"
+	}
+
+	// Thinking tokens are billed as output tokens
+	if priceTable, err := pricing.LoadTable(t.config.PriceTableFile); err == nil {
+		price, ok := priceTable.Lookup(t.config.ModelName)
+		if !ok {
+			price, summary.PricedAs, ok = priceTable.LookupFamily(t.config.ModelName)
+		}
+		if ok {
+			cost := price.Cost(t.usage.PromptTokens, t.usage.OutputTokens+t.usage.ThinkingTokens)
+			summary.EstimatedCost = &cost
+		}
+	}
+
+	return summary
+}
+
+// saveUsageSummary writes the usage summary if any request was made
+func (t *Translator) saveUsageSummary(runErr error) error {
+	if t.requestCount == 0 || t.outputFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(t.buildUsageSummary(runErr), "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(t.usageSummaryPath(), data)
+}
@@ -0,0 +1,118 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+func TestTranslator_saveUsageSummary(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "episode.fr.srt")
+	translator := &Translator{
+		config:       &config.Config{ModelName: "gpt-4o", InputFile: "episode.srt"},
+		provider:     &mockProvider{},
+		outputFile:   outputPath,
+		requestCount: 2,
+		startedAt:    time.Now(),
+	}
+	translator.usage.Add(providers.Usage{PromptTokens: 1000, OutputTokens: 400, ThinkingTokens: 100, Latency: time.Second})
+	translator.usage.Add(providers.Usage{PromptTokens: 1000, OutputTokens: 400, CachedTokens: 200, Latency: time.Second})
+
+	if err := translator.saveUsageSummary(errors.New("boom")); err != nil {
+		t.Fatalf("saveUsageSummary() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(outputPath), "episode.fr.usage.json"))
+	if err != nil {
+		t.Fatalf("Failed to read usage summary: %v", err)
+	}
+
+	var summary UsageSummary
+	if err = json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("Failed to unmarshal usage summary: %v", err)
+	}
+
+	if summary.Status != "failed" || summary.Requests != 2 {
+		t.Errorf("Unexpected status/requests: %q/%d", summary.Status, summary.Requests)
+	}
+	if summary.PromptTokens != 2000 || summary.OutputTokens != 800 || summary.ThinkingTokens != 100 || summary.CachedTokens != 200 {
+		t.Errorf("Unexpected token totals: %+v", summary)
+	}
+	if summary.LatencyMillis != 2000 {
+		t.Errorf("LatencyMillis = %d, want 2000", summary.LatencyMillis)
+	}
+	if summary.EstimatedCost == nil || *summary.EstimatedCost <= 0 {
+		t.Error("Expected an estimated cost for a priced model")
+	}
+}
+
+func TestTranslator_saveUsageSummarySkipsWithoutRequests(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "episode.fr.srt")
+	translator := &Translator{config: &config.Config{}, outputFile: outputPath}
+
+	if err := translator.saveUsageSummary(nil); err != nil {
+		t.Fatalf("saveUsageSummary() failed: %v", err)
+	}
+	if _, err := os.Stat(translator.usageSummaryPath()); !os.IsNotExist(err) {
+		t.Error("Expected no usage summary without requests")
+	}
+}
+
+// malformedProvider answers the first request with JSON that cannot be parsed,
+// billing its tokens like a real API, and echoes afterwards
+type malformedProvider struct {
+	mockProvider
+	requests int
+}
+
+func (p *malformedProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []providers.ContextMessage, config *providers.TranslationConfig) (*providers.TranslationResponse, error) {
+	p.requests++
+	usage := providers.Usage{PromptTokens: 100, OutputTokens: 10}
+	if p.requests == 1 {
+		return &providers.TranslationResponse{Usage: usage}, errors.New("failed to parse response")
+	}
+	return &providers.TranslationResponse{TranslatedBatch: batch, Context: previousContext, Usage: usage}, nil
+}
+
+func TestTranslator_usageOfMalformedResponses(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	inputPath := filepath.Join(t.TempDir(), "episode.srt")
+	if err := os.WriteFile(inputPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	provider := &malformedProvider{}
+	translator := NewTranslatorWithProvider(&config.Config{
+		InputFile:      inputPath,
+		TargetLanguage: "French",
+		ModelName:      "mock-model",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+		RetryCount:     1,
+		NonInteractive: true,
+	}, provider)
+	if err := translator.Translate(context.Background()); err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+
+	// Both answered requests are billed, the malformed one included
+	if provider.requests != 2 {
+		t.Fatalf("Expected one retry, got %d requests", provider.requests)
+	}
+	if usage := translator.Usage(); usage.PromptTokens != 200 || usage.OutputTokens != 20 {
+		t.Errorf("Usage() = %+v, want 200 prompt and 20 output tokens", usage)
+	}
+	if translator.requestCount != 2 {
+		t.Errorf("requestCount = %d, want 2", translator.requestCount)
+	}
+}
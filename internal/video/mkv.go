@@ -6,12 +6,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strconv"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/luispater/matroska-go"
 
+	"github.com/luispater/gemini-srt-translator-go/internal/decision"
 	"github.com/luispater/gemini-srt-translator-go/internal/logger"
 	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
 	"github.com/luispater/gemini-srt-translator-go/pkg/languages"
@@ -40,6 +41,7 @@ type MKVParser struct {
 	filename      string
 	tracks        []SubtitleTrack
 	timecodescale uint64
+	charset       string // Charset of the text packets, empty to detect it per track
 }
 
 // NewMKVParser creates a new MKV parser
@@ -51,6 +53,12 @@ func NewMKVParser(filename string) *MKVParser {
 	}
 }
 
+// SetCharset sets the charset of the text packets; empty or "auto" detects
+// it for each track, for files muxed from legacy SRTs without conversion
+func (p *MKVParser) SetCharset(charset string) {
+	p.charset = charset
+}
+
 // Parse parses the MKV file and extracts subtitle tracks
 func (p *MKVParser) Parse() error {
 	file, err := os.Open(p.filename)
@@ -143,7 +151,7 @@ func (p *MKVParser) extractSubtitlePackets(demuxer *matroska.Demuxer) error {
 			continue // Not a subtitle track we're interested in
 		}
 
-		// Convert packet data to text (assuming UTF-8)
+		// The packet bytes are converted by decodeTrackText once the track is complete
 		text := strings.TrimSpace(string(packet.Data))
 		if text == "" {
 			continue // Skip empty packets
@@ -166,6 +174,39 @@ func (p *MKVParser) extractSubtitlePackets(demuxer *matroska.Demuxer) error {
 		track.Entries = append(track.Entries, entry)
 	}
 
+	for i := range p.tracks {
+		if err := p.decodeTrackText(&p.tracks[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeTrackText converts the packet texts of a track to UTF-8, detecting
+// their charset from the whole track unless one is set
+func (p *MKVParser) decodeTrackText(track *SubtitleTrack) error {
+	charset, err := srt.ResolveCharset(p.charset)
+	if err != nil {
+		return err
+	}
+	if charset == "" {
+		var sample []byte
+		for _, entry := range track.Entries {
+			sample = append(append(sample, entry.Text...), '\n')
+		}
+		charset = srt.DetectCharset(sample)
+	}
+	if strings.EqualFold(charset, "UTF-8") {
+		return nil
+	}
+
+	for i := range track.Entries {
+		text, _, errDecode := srt.DecodeText([]byte(track.Entries[i].Text), charset)
+		if errDecode != nil {
+			return errDecode
+		}
+		track.Entries[i].Text = strings.TrimSpace(text)
+	}
 	return nil
 }
 
@@ -207,6 +248,62 @@ func (p *MKVParser) SelectBestEnglishTrack() (*SubtitleTrack, error) {
 	return &englishTracks[0], nil
 }
 
+// SelectTrack selects the track to translate. With a source language it
+// prefers tracks in that language, otherwise tracks not already in the target
+// language, English first. Tracks without a language tag are identified from
+// their text. Non-SDH tracks are preferred.
+func (p *MKVParser) SelectTrack(sourceLanguage string, targetLanguage string) (*SubtitleTrack, error) {
+	var candidates []SubtitleTrack
+	for _, track := range p.tracks {
+		code, _ := TrackLanguage(track)
+		switch {
+		case sourceLanguage != "":
+			if languages.Matches(code, sourceLanguage) {
+				candidates = append(candidates, track)
+			}
+		case targetLanguage == "" || !languages.Matches(code, targetLanguage):
+			candidates = append(candidates, track)
+		}
+	}
+	if len(candidates) == 0 {
+		return p.SelectBestEnglishTrack()
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return trackRank(candidates[i]) < trackRank(candidates[j])
+	})
+	return &candidates[0], nil
+}
+
+// trackRank orders candidate tracks: English before other languages, non-SDH
+// before SDH
+func trackRank(track SubtitleTrack) int {
+	rank := 0
+	if code, _ := TrackLanguage(track); !languages.Matches(code, "en") {
+		rank += 2
+	}
+	if isSDHTrack(track.Name) {
+		rank++
+	}
+	return rank
+}
+
+// TrackLanguage returns the language code of a track from its tag, or detected
+// from its text when the tag is missing or undetermined
+func TrackLanguage(track SubtitleTrack) (code string, detected bool) {
+	if language, ok := languages.Lookup(track.Language); ok {
+		return language.Tag, false
+	}
+	texts := make([]string, 0, len(track.Entries))
+	for _, entry := range track.Entries {
+		texts = append(texts, entry.Text)
+	}
+	if detection := languages.DetectAll(texts); detection.Reliable() {
+		return detection.Code, true
+	}
+	return "", false
+}
+
 // ExtractToSRT extracts a subtitle track to SRT format
 func (p *MKVParser) ExtractToSRT(track *SubtitleTrack, outputPath string) error {
 	if len(track.Entries) == 0 {
@@ -251,8 +348,11 @@ func (p *MKVParser) ExtractToSRT(track *SubtitleTrack, outputPath string) error
 	return nil
 }
 
-// ExtractSubtitlesFromMKV extracts subtitles from MKV file and returns the path to extracted SRT
-func ExtractSubtitlesFromMKV(mkvPath string) (string, error) {
+// ExtractSubtitlesFromMKV extracts subtitles from MKV file and returns the path to extracted SRT.
+// The decider picks the track when the file has several; the suggested track
+// is chosen by SelectTrack from the source and target languages. Text in
+// another charset than UTF-8 is converted, see SetCharset.
+func ExtractSubtitlesFromMKV(mkvPath string, decider decision.Decider, sourceLanguage string, targetLanguage string, charset string) (string, error) {
 	// Validate input file
 	if !strings.HasSuffix(strings.ToLower(mkvPath), ".mkv") {
 		return "", errors.NewValidationError("file is not an MKV file", nil).WithContext("file_path", mkvPath)
@@ -264,6 +364,7 @@ func ExtractSubtitlesFromMKV(mkvPath string) (string, error) {
 
 	// Create parser and parse the file
 	parser := NewMKVParser(mkvPath)
+	parser.SetCharset(charset)
 	if err := parser.Parse(); err != nil {
 		return "", err
 	}
@@ -279,42 +380,35 @@ func ExtractSubtitlesFromMKV(mkvPath string) (string, error) {
 		logger.Info("Only one subtitle track found; selecting it automatically.")
 		selectedIdx = 0
 	} else {
-		logger.Info("Available subtitle tracks:")
-		for i, tr := range tracks {
-			lines := len(tr.Entries)
-			name := strings.TrimSpace(tr.Name)
-			lang := tr.Language
-			bcp := languages.BCP47FromMKV(lang)
-			if bcp != "" {
-				if name != "" {
-					lang = fmt.Sprintf("%s (%s)", bcp, name)
-				} else {
-					lang = fmt.Sprintf("%s", bcp)
+		best := -1
+		if track, errSel := parser.SelectTrack(sourceLanguage, targetLanguage); errSel == nil && track != nil {
+			for i := range tracks {
+				if tracks[i].Number == track.Number {
+					best = i
+					break
 				}
 			}
-			logger.Info(fmt.Sprintf("[%d] Language: %s, Lines:%d", i+1, lang, lines))
 		}
 
-		for {
-			input := logger.InputPrompt("Select track number to extract: ")
-			input = strings.TrimSpace(input)
-			if input == "" {
-				best, errSel := parser.SelectBestEnglishTrack()
-				if errSel == nil && best != nil {
-					for i := range tracks {
-						if tracks[i].Number == best.Number {
-							selectedIdx = i
-							break
-						}
-					}
-					break
+		choices := make([]decision.Track, 0, len(tracks))
+		for _, tr := range tracks {
+			lang := "Undetermined"
+			if code, detected := TrackLanguage(tr); code != "" {
+				lang = languages.Name(code)
+				if detected {
+					lang += " (detected)"
 				}
 			}
-			if n, errAtoi := strconv.Atoi(input); errAtoi == nil && n >= 1 && n <= len(tracks) {
-				selectedIdx = n - 1
-				break
-			}
-			logger.Warning("Invalid selection. Enter a valid number.")
+			choices = append(choices, decision.Track{
+				Language: lang,
+				Name:     strings.TrimSpace(tr.Name),
+				Lines:    len(tr.Entries),
+			})
+		}
+
+		var err error
+		if selectedIdx, err = decider.SubtitleTrack(choices, best); err != nil {
+			return "", err
 		}
 	}
 
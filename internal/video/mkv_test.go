@@ -154,3 +154,68 @@ func TestExtractToSRT(t *testing.T) {
 		t.Errorf("Output content mismatch\nExpected:\n%q\nGot:\n%q", expectedContent, string(content))
 	}
 }
+
+func TestSelectTrack(t *testing.T) {
+	french := []SubtitleEntry{
+		{Text: "Je ne sais pas ce que tu veux dire."},
+		{Text: "Il faut partir maintenant, avant qu'ils arrivent."},
+	}
+	parser := &MKVParser{
+		tracks: []SubtitleTrack{
+			{Number: 1, Language: "eng", Name: "English SDH"},
+			{Number: 2, Language: "und", Name: "Track 2", Entries: french},
+			{Number: 3, Language: "spa", Name: "Spanish"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		target string
+		want   int
+	}{
+		{"English first", "", "German", 1},
+		{"source language", "Spanish", "German", 3},
+		{"detected source language", "French", "German", 2},
+		{"skip target language", "", "English", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			track, err := parser.SelectTrack(tt.source, tt.target)
+			if err != nil {
+				t.Fatalf("SelectTrack() failed: %v", err)
+			}
+			if track.Number != tt.want {
+				t.Errorf("SelectTrack(%q, %q) = track %d, want %d", tt.source, tt.target, track.Number, tt.want)
+			}
+		})
+	}
+
+	if code, detected := TrackLanguage(parser.tracks[1]); code != "fr" || !detected {
+		t.Errorf("TrackLanguage() = %q, %v, want detected fr", code, detected)
+	}
+}
+
+func TestDecodeTrackText(t *testing.T) {
+	// Windows-1251 packets of a track muxed without conversion
+	track := SubtitleTrack{Entries: []SubtitleEntry{
+		{Text: "\xc3\xe4\xe5 \xf2\xfb \xe1\xfb\xeb \xe2\xf7\xe5\xf0\xe0?"},
+		{Text: "\xcf\xf0\xee\xf1\xf2\xe8, \xed\xe0 \xf0\xe0\xe1\xee\xf2\xe5 \xe1\xfb\xeb\xee \xec\xed\xee\xe3\xee \xe4\xe5\xeb."},
+	}}
+	parser := NewMKVParser("movie.mkv")
+	if err := parser.decodeTrackText(&track); err != nil {
+		t.Fatalf("decodeTrackText() failed: %v", err)
+	}
+	if track.Entries[0].Text != "Где ты был вчера?" || track.Entries[1].Text != "Прости, на работе было много дел." {
+		t.Errorf("Unexpected text: %q", track.Entries)
+	}
+
+	utf8Track := SubtitleTrack{Entries: []SubtitleEntry{{Text: "Где ты был вчера?"}}}
+	parser.SetCharset("windows-1251")
+	if err := parser.decodeTrackText(&utf8Track); err != nil {
+		t.Fatalf("decodeTrackText() failed: %v", err)
+	}
+	if utf8Track.Entries[0].Text == "Где ты был вчера?" {
+		t.Error("decodeTrackText() ignored the configured charset")
+	}
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+package watch
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyNotifier wakes the watcher as soon as a file is written or moved
+// into a watched directory
+type inotifyNotifier struct {
+	file    *os.File
+	fd      int
+	watched map[string]bool
+	events  chan struct{}
+}
+
+// newNotifier returns an inotify based notifier, or a polling-only notifier
+// when inotify is unavailable
+func newNotifier() notifier {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return pollNotifier{}
+	}
+
+	n := &inotifyNotifier{
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		fd:      fd,
+		watched: make(map[string]bool),
+		events:  make(chan struct{}, 1),
+	}
+	go n.readEvents()
+	return n
+}
+
+// Add starts watching a directory
+func (n *inotifyNotifier) Add(dir string) error {
+	if n.watched[dir] {
+		return nil
+	}
+	if _, err := unix.InotifyAddWatch(n.fd, dir, unix.IN_CLOSE_WRITE|unix.IN_MOVED_TO|unix.IN_CREATE); err != nil {
+		return err
+	}
+	n.watched[dir] = true
+	return nil
+}
+
+// Events signals that something changed in a watched directory
+func (n *inotifyNotifier) Events() <-chan struct{} {
+	return n.events
+}
+
+// Close stops watching
+func (n *inotifyNotifier) Close() error {
+	return n.file.Close()
+}
+
+// readEvents coalesces raw inotify events into wake-up signals
+func (n *inotifyNotifier) readEvents() {
+	buf := make([]byte, 4096)
+	for {
+		if _, err := n.file.Read(buf); err != nil {
+			return
+		}
+		select {
+		case n.events <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package watch
+
+// newNotifier returns a polling-only notifier on platforms without inotify
+func newNotifier() notifier {
+	return pollNotifier{}
+}
@@ -0,0 +1,360 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/batch"
+	"github.com/luispater/gemini-srt-translator-go/internal/decision"
+	"github.com/luispater/gemini-srt-translator-go/internal/interrupt"
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/translator"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+)
+
+// DefaultStateFile is the queue/state file name created in the watched directory
+const DefaultStateFile = ".gst-watch.json"
+
+// JobStatus describes where a queued file is in its lifecycle
+type JobStatus string
+
+const (
+	JobPending JobStatus = "pending"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed"
+)
+
+// Job is one queued input file
+type Job struct {
+	InputFile        string    `json:"input_file"`
+	Status           JobStatus `json:"status"`
+	Size             int64     `json:"size"`
+	ModTime          time.Time `json:"mod_time"`
+	CompletedTargets []string  `json:"completed_targets,omitempty"`
+	Error            string    `json:"error,omitempty"`
+	QueuedAt         time.Time `json:"queued_at"`
+	FinishedAt       time.Time `json:"finished_at,omitempty"`
+}
+
+// State is the persisted queue
+type State struct {
+	Jobs map[string]*Job `json:"jobs"`
+}
+
+// Options controls the watcher
+type Options struct {
+	Interval  time.Duration // How often the directory is rescanned
+	StableFor time.Duration // How long size and modification time must stay unchanged
+	StateFile string        // Queue/state file, defaults to DefaultStateFile in the directory
+	Targets   []string      // Target languages, defaults to the configured target language
+	Batch     batch.Options // Include/exclude patterns for discovered files
+}
+
+// notifier wakes the watcher early when the file system reports changes
+type notifier interface {
+	Add(dir string) error
+	Events() <-chan struct{}
+	Close() error
+}
+
+// pollNotifier never fires, leaving change detection to the polling interval
+type pollNotifier struct{}
+
+func (pollNotifier) Add(string) error        { return nil }
+func (pollNotifier) Events() <-chan struct{} { return nil }
+func (pollNotifier) Close() error            { return nil }
+
+// observation tracks a file until its size settles
+type observation struct {
+	size    int64
+	modTime time.Time
+	since   time.Time
+}
+
+// Watcher monitors a directory and translates new subtitle files
+type Watcher struct {
+	dir      string
+	config   *config.Config
+	runner   *batch.Runner
+	options  Options
+	state    *State
+	observed map[string]observation
+	notifier notifier
+}
+
+// NewWatcher creates a watcher for dir. Translations resume automatically
+// through the .progress files and never prompt, so the configuration is
+// switched to non-interactive resume mode.
+func NewWatcher(dir string, cfg *config.Config, runner *batch.Runner, opts Options) (*Watcher, error) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, errors.NewValidationError(fmt.Sprintf("watch directory does not exist: %s", dir), err).WithContext("dir_path", dir)
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.StableFor < 0 {
+		opts.StableFor = 0
+	}
+	if opts.StateFile == "" {
+		opts.StateFile = filepath.Join(dir, DefaultStateFile)
+	}
+	if len(opts.Targets) == 0 {
+		opts.Targets = []string{cfg.TargetLanguage}
+	}
+	// The outputs written for every target are not new inputs
+	if opts.Batch.Config == nil {
+		opts.Batch.Config = cfg
+	}
+	if len(opts.Batch.Roots) == 0 {
+		opts.Batch.Roots = []string{dir}
+	}
+	opts.Batch.Targets = opts.Targets
+
+	resume := true
+	cfg.Resume = &resume
+	cfg.NonInteractive = true
+	if cfg.OnExistingOutput == "" {
+		cfg.OnExistingOutput = decision.OutputResume
+	}
+
+	return &Watcher{
+		dir:      dir,
+		config:   cfg,
+		runner:   runner,
+		options:  opts,
+		state:    &State{Jobs: make(map[string]*Job)},
+		observed: make(map[string]observation),
+		notifier: pollNotifier{},
+	}, nil
+}
+
+// Run watches until the context is cancelled
+func (w *Watcher) Run(ctx context.Context) error {
+	if err := w.loadState(); err != nil {
+		return err
+	}
+
+	w.notifier = newNotifier()
+	defer func() {
+		_ = w.notifier.Close()
+	}()
+
+	logger.Info(fmt.Sprintf("Watching %s for new subtitle files (state: %s)", w.dir, w.options.StateFile))
+
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Poll(ctx); err != nil {
+			logger.Warning(fmt.Sprintf("Watch scan failed: %v", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-interrupt.Stopped(ctx):
+			return nil
+		case <-ticker.C:
+		case <-w.notifier.Events():
+		}
+	}
+}
+
+// Poll scans the directory once, queues files that became stable and
+// processes every pending job
+func (w *Watcher) Poll(ctx context.Context) error {
+	if err := w.scan(); err != nil {
+		return err
+	}
+	return w.processPending(ctx)
+}
+
+// scan looks for new or changed files and queues the stable ones
+func (w *Watcher) scan() error {
+	errWalk := filepath.WalkDir(w.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.IsDir() {
+			_ = w.notifier.Add(path)
+		}
+		return err
+	})
+	if errWalk != nil {
+		return errWalk
+	}
+
+	inputs, err := batch.CollectInputs([]string{w.dir}, w.options.Batch)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, path := range inputs {
+		info, errStat := os.Stat(path)
+		if errStat != nil {
+			continue
+		}
+
+		// Skip files already queued in their current version
+		if job, ok := w.state.Jobs[path]; ok && job.Size == info.Size() && job.ModTime.Equal(info.ModTime()) {
+			continue
+		}
+
+		obs, ok := w.observed[path]
+		if !ok || obs.size != info.Size() || !obs.modTime.Equal(info.ModTime()) {
+			w.observed[path] = observation{size: info.Size(), modTime: info.ModTime(), since: now}
+			if w.options.StableFor > 0 {
+				continue
+			}
+			obs = w.observed[path]
+		}
+		if now.Sub(obs.since) < w.options.StableFor {
+			continue
+		}
+
+		delete(w.observed, path)
+		w.state.Jobs[path] = &Job{
+			InputFile: path,
+			Status:    JobPending,
+			Size:      info.Size(),
+			ModTime:   info.ModTime(),
+			QueuedAt:  now,
+		}
+		logger.Info(fmt.Sprintf("Queued %s", path))
+		if errSave := w.saveState(); errSave != nil {
+			return errSave
+		}
+	}
+
+	return nil
+}
+
+// processPending translates queued jobs in the order they were queued
+func (w *Watcher) processPending(ctx context.Context) error {
+	var pending []*Job
+	for _, job := range w.state.Jobs {
+		if job.Status == JobPending {
+			pending = append(pending, job)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].QueuedAt.Equal(pending[j].QueuedAt) {
+			return pending[i].InputFile < pending[j].InputFile
+		}
+		return pending[i].QueuedAt.Before(pending[j].QueuedAt)
+	})
+
+	for _, job := range pending {
+		if interrupt.Requested(ctx) {
+			return nil
+		}
+		w.processJob(ctx, job)
+		if err := w.saveState(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processJob translates one file into every target that is not done yet
+func (w *Watcher) processJob(ctx context.Context, job *Job) {
+	completed := make(map[string]bool)
+	for _, target := range job.CompletedTargets {
+		completed[target] = true
+	}
+
+	for _, target := range w.options.Targets {
+		if completed[target] {
+			continue
+		}
+
+		// An output without a .progress file is a finished translation
+		if w.isFinished(job.InputFile, target) {
+			logger.Info(fmt.Sprintf("Output for %s (%s) already exists, skipping", job.InputFile, target))
+		} else {
+			logger.Highlight(fmt.Sprintf("Translating %s to %s", job.InputFile, target))
+			result := w.runner.TranslateFile(ctx, job.InputFile, target)
+			if result.Status == batch.StatusFailed {
+				if interrupt.Requested(ctx) {
+					// Interrupted, keep the job pending so it resumes on restart
+					return
+				}
+				job.Status = JobFailed
+				job.Error = result.Err.Error()
+				job.FinishedAt = time.Now()
+				return
+			}
+		}
+
+		job.CompletedTargets = append(job.CompletedTargets, target)
+		if err := w.saveState(); err != nil {
+			logger.Warning(fmt.Sprintf("Failed to save watch state: %v", err))
+		}
+	}
+
+	job.Status = JobDone
+	job.Error = ""
+	job.FinishedAt = time.Now()
+}
+
+// isFinished reports whether the output for a target exists without a pending .progress file
+func (w *Watcher) isFinished(inputFile string, target string) bool {
+	t := translator.NewTranslatorWithProvider(w.runner.FileConfig(inputFile, target), nil)
+
+	if _, err := os.Stat(t.OutputFile()); err != nil {
+		return false
+	}
+	_, err := os.Stat(t.ProgressFile())
+	return os.IsNotExist(err)
+}
+
+// loadState reads the persisted queue if there is one
+func (w *Watcher) loadState() error {
+	data, err := os.ReadFile(w.options.StateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.NewFileError("failed to read watch state", err).WithContext("file_path", w.options.StateFile)
+	}
+
+	var state State
+	if err = json.Unmarshal(data, &state); err != nil {
+		return errors.NewFileError("failed to parse watch state", err).WithContext("file_path", w.options.StateFile)
+	}
+	if state.Jobs == nil {
+		state.Jobs = make(map[string]*Job)
+	}
+	w.state = &state
+
+	pending := 0
+	for _, job := range state.Jobs {
+		if job.Status == JobPending {
+			pending++
+		}
+	}
+	if pending > 0 {
+		logger.Info(fmt.Sprintf("Resuming %d pending jobs from %s", pending, w.options.StateFile))
+	}
+	return nil
+}
+
+// saveState persists the queue atomically
+func (w *Watcher) saveState() error {
+	data, err := json.MarshalIndent(w.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpFile := w.options.StateFile + ".tmp"
+	if err = os.WriteFile(tmpFile, data, 0644); err != nil {
+		return errors.NewFileError("failed to write watch state", err).WithContext("file_path", tmpFile)
+	}
+	return os.Rename(tmpFile, w.options.StateFile)
+}
@@ -0,0 +1,260 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/batch"
+	"github.com/luispater/gemini-srt-translator-go/internal/logger"
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+const testSRT = "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n"
+
+func newTestWatcher(t *testing.T, dir string, opts Options) *Watcher {
+	t.Helper()
+	cfg := &config.Config{
+		TargetLanguage: "Klingon",
+		ModelName:      "mock-model",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+	}
+	runner := batch.NewRunnerWithProvider(cfg, &echoProvider{}, batch.Options{})
+	watcher, err := NewWatcher(dir, cfg, runner, opts)
+	if err != nil {
+		t.Fatalf("NewWatcher() failed: %v", err)
+	}
+	return watcher
+}
+
+func readState(t *testing.T, path string) State {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read state: %v", err)
+	}
+	var state State
+	if err = json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("Failed to parse state: %v", err)
+	}
+	return state
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "E01.srt")
+	if err := os.WriteFile(inputPath, []byte(testSRT), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	watcher := newTestWatcher(t, dir, Options{StableFor: time.Hour})
+
+	// A file that has not been stable long enough is only observed
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() failed: %v", err)
+	}
+	if len(watcher.state.Jobs) != 0 {
+		t.Fatalf("Expected no queued jobs yet, got %d", len(watcher.state.Jobs))
+	}
+
+	watcher.options.StableFor = 0
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() failed: %v", err)
+	}
+
+	state := readState(t, filepath.Join(dir, DefaultStateFile))
+	job, ok := state.Jobs[inputPath]
+	if !ok {
+		t.Fatalf("Expected job for %s, got %+v", inputPath, state.Jobs)
+	}
+	if job.Status != JobDone {
+		t.Errorf("Job status = %s, want %s (%s)", job.Status, JobDone, job.Error)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "E01_translated.srt")); err != nil {
+		t.Errorf("Expected output next to the input: %v", err)
+	}
+
+	// The output must not be queued as a new input
+	if err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() failed: %v", err)
+	}
+	if len(watcher.state.Jobs) != 1 {
+		t.Errorf("Expected 1 job, got %d", len(watcher.state.Jobs))
+	}
+}
+
+func TestWatcher_ResumesPendingJobs(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "E02.srt")
+	if err := os.WriteFile(inputPath, []byte(testSRT), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	info, err := os.Stat(inputPath)
+	if err != nil {
+		t.Fatalf("Failed to stat input: %v", err)
+	}
+
+	statePath := filepath.Join(dir, "queue.json")
+	state := State{Jobs: map[string]*Job{
+		inputPath: {InputFile: inputPath, Status: JobPending, Size: info.Size(), ModTime: info.ModTime()},
+	}}
+	data, _ := json.Marshal(state)
+	if err = os.WriteFile(statePath, data, 0644); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	watcher := newTestWatcher(t, dir, Options{StateFile: statePath, StableFor: time.Hour})
+	if err = watcher.loadState(); err != nil {
+		t.Fatalf("loadState() failed: %v", err)
+	}
+	if err = watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() failed: %v", err)
+	}
+
+	if got := readState(t, statePath).Jobs[inputPath].Status; got != JobDone {
+		t.Errorf("Job status = %s, want %s", got, JobDone)
+	}
+}
+
+func TestNewWatcher_MissingDirectory(t *testing.T) {
+	_, err := NewWatcher(filepath.Join(t.TempDir(), "missing"), &config.Config{}, nil, Options{})
+	if err == nil {
+		t.Error("Expected error for missing directory")
+	}
+}
+
+// echoProvider returns every batch unchanged
+type echoProvider struct{}
+
+func (p *echoProvider) GetModels(ctx context.Context) ([]string, error) {
+	return []string{"mock-model"}, nil
+}
+
+func (p *echoProvider) GetTokenLimit(ctx context.Context, modelName string) (int32, error) {
+	return 100000, nil
+}
+
+func (p *echoProvider) CountTokens(ctx context.Context, modelName string, content string) (int32, error) {
+	return int32(len(content)), nil
+}
+
+func (p *echoProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []providers.ContextMessage, config *providers.TranslationConfig) (*providers.TranslationResponse, error) {
+	return &providers.TranslationResponse{TranslatedBatch: batch}, nil
+}
+
+func (p *echoProvider) GetName() string {
+	return "mock"
+}
+
+// killingProvider cancels the run during its first batch, then echoes
+type killingProvider struct {
+	echoProvider
+	cancel  context.CancelFunc
+	batches int
+}
+
+func (p *killingProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []providers.ContextMessage, config *providers.TranslationConfig) (*providers.TranslationResponse, error) {
+	p.batches++
+	if p.batches == 1 {
+		p.cancel()
+		return nil, ctx.Err()
+	}
+	return p.echoProvider.TranslateBatch(ctx, batch, previousContext, config)
+}
+
+func TestWatcher_RetriesJobKilledInFirstBatch(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "E03.srt")
+	if err := os.WriteFile(inputPath, []byte(testSRT), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	cfg := &config.Config{
+		TargetLanguage: "Klingon",
+		ModelName:      "mock-model",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	provider := &killingProvider{cancel: cancel}
+	watcher, err := NewWatcher(dir, cfg, batch.NewRunnerWithProvider(cfg, provider, batch.Options{}), Options{})
+	if err != nil {
+		t.Fatalf("NewWatcher() failed: %v", err)
+	}
+
+	// The killed job stays pending with its output and progress left behind
+	if err = watcher.Poll(ctx); err != nil {
+		t.Fatalf("Poll() failed: %v", err)
+	}
+	if job := watcher.state.Jobs[inputPath]; job == nil || job.Status != JobPending {
+		t.Fatalf("Job after the kill = %+v, want pending", job)
+	}
+
+	// The retry resumes the output of the killed run instead of refusing it
+	if err = watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() failed: %v", err)
+	}
+	if job := watcher.state.Jobs[inputPath]; job.Status != JobDone {
+		t.Errorf("Job status = %s, want %s (%s)", job.Status, JobDone, job.Error)
+	}
+	if provider.batches != 2 {
+		t.Errorf("Expected the retry to translate the batch again, got %d batches", provider.batches)
+	}
+}
+
+func TestWatcher_SkipsOutputsOfEveryTarget(t *testing.T) {
+	logger.SetQuietMode(true)
+	defer logger.SetQuietMode(false)
+
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "E01.srt")
+	if err := os.WriteFile(inputPath, []byte(testSRT), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	cfg := &config.Config{
+		TargetLanguage: "Klingon",
+		ModelName:      "mock-model",
+		BatchSize:      10,
+		ThinkingLevel:  "high",
+		OutputTemplate: "{dir}/{stem}.{lang}.{ext}",
+	}
+	runner := batch.NewRunnerWithProvider(cfg, &echoProvider{}, batch.Options{Roots: []string{dir}})
+	watcher, err := NewWatcher(dir, cfg, runner, Options{Targets: []string{"French", "German"}})
+	if err != nil {
+		t.Fatalf("NewWatcher() failed: %v", err)
+	}
+
+	for range 2 {
+		if err = watcher.Poll(context.Background()); err != nil {
+			t.Fatalf("Poll() failed: %v", err)
+		}
+	}
+	for _, name := range []string{"E01.fr.srt", "E01.de.srt"} {
+		if _, err = os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected output %s: %v", name, err)
+		}
+	}
+	// The outputs are not queued, so nothing like E01.fr.fr.srt is written
+	if len(watcher.state.Jobs) != 1 {
+		t.Errorf("Expected 1 job, got %+v", watcher.state.Jobs)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "E01.*.*.srt")); len(matches) > 0 {
+		t.Errorf("Outputs were translated again: %v", matches)
+	}
+}
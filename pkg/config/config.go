@@ -16,14 +16,41 @@ type Config struct {
 	TargetLanguage string
 
 	// File paths
-	InputFile  string
-	OutputFile string
+	InputFile      string
+	OutputFile     string
+	OutputTemplate string   // Output path template, e.g. "{dir}/{name}.{lang_bcp47}{.forced}.{ext}"
+	OutputDir      string   // Directory outputs are written to instead of next to the input
+	TrackFlags     []string // Flags of the translated track (forced, sdh, default) for the output template
+	InputEncoding  string   // Charset of the input subtitles, empty to detect it
+	OutputEncoding string   // Charset of the output file, empty for UTF-8 or, with PreserveFormat, the input charset
+	OutputBOM      bool     // Start the output file with a byte order mark
+	PreserveFormat bool     // Write the output in the layout of the input: cue numbers, timestamps, whitespace and line endings
 
 	// Processing options
-	StartLine   int
-	Description string
-	BatchSize   int
-	RetryCount  int
+	StartLine     int
+	LineSelection string // Line numbers/ranges to re-translate, e.g. "120-180,455"
+	TimeSelection string // Time ranges to re-translate, e.g. "00:12:00-00:15:30"
+	Description   string
+	Glossary      []string // "term = translation" entries added to the instruction
+	BatchSize     int
+	RetryCount    int
+	TimingPre     []string // Timing steps applied to the source before translating, e.g. "shift=-1.5s"
+	TimingPost    []string // Timing steps applied to the translated output, e.g. "min-gap=84ms"
+	StrictParsing bool     // Fail on any defect in the source subtitles instead of repairing it
+	StripSDH      bool     // Remove sound descriptions, speaker labels and music lines before translating
+
+	// Prompt options
+	SourceLanguage   string // Language of the source subtitles, empty to detect it
+	SkipTargetCues   bool   // Keep cues already in the target language instead of translating them
+	DialogueSegments bool   // Send dialogue lines, speaker labels and SDH annotations as separate segments
+	SDHAnnotations   string // keep or drop the SDH annotations ([door slams], ♪ lyrics ♪) of segmented cues
+	PromptTemplate   string // Template file or built-in template name for the instruction
+	RulesDir         string // Directory of per-target-language rule packs
+	StyleGuide       string // File with a style guide added to the instruction
+
+	// Dry-run options
+	DryRun         bool   // Estimate requests, tokens and cost without translating
+	PriceTableFile string // JSON file overriding the built-in per-model price table
 
 	// Model configuration
 	ModelName     string
@@ -35,11 +62,28 @@ type Config struct {
 	TopK          *float32
 
 	// User options
-	FreeQuota   bool
-	UseColors   bool
-	ProgressLog bool
-	QuietMode   bool
-	Resume      *bool
+	FreeQuota    bool
+	UseColors    bool
+	ProgressLog  bool
+	SaveThoughts bool // Append the model thoughts of every batch to the thoughts log
+	QuietMode    bool
+	Resume       *bool
+
+	// Transcripts
+	RecordDir    string // Directory every request/response exchange is written to
+	ReplayDir    string // Directory with recorded exchanges served by the replay provider
+	ReplayStrict bool   // Fail a batch whose payload matches no transcript instead of serving the next one
+
+	// Logging
+	LogLevel  string // debug, info, warn or error
+	LogFile   string // File every log record is appended to while running
+	LogFormat string // text or json, for the log file
+
+	// Non-interactive decisions
+	NonInteractive   bool   // Never prompt, answer every question from the options below
+	OnExistingOutput string // resume, overwrite or fail when an output or saved progress exists
+	OnTokenLimit     string // shrink or fail when a batch exceeds the token limit
+	SubtitleTrack    int    // 1-based MKV subtitle track, 0 picks the best track for the source language
 }
 
 // parseAPIKeys parses comma-separated API keys from environment variable
@@ -63,42 +107,41 @@ func parseAPIKeys(envKey string) []string {
 // NewConfig creates a new configuration with default values
 func NewConfig() *Config {
 	return &Config{
-		Provider:      "gemini",                            // Default to Gemini for backward compatibility
-		APIKeys:       parseAPIKeys("GEMINI_API_KEY"),      // Default to Gemini env var
-		BaseURL:       os.Getenv("GOOGLE_GEMINI_BASE_URL"), // Default to Gemini base URL
-		ModelName:     "gemini-3.5-flash",
-		BatchSize:     300,
-		RetryCount:    3,
-		Streaming:     true,
-		Thinking:      true,
-		ThinkingLevel: "high",
-		FreeQuota:     true,
-		UseColors:     true,
-		ProgressLog:   false,
-		QuietMode:     false,
+		Provider:         "gemini",                            // Default to Gemini for backward compatibility
+		APIKeys:          parseAPIKeys("GEMINI_API_KEY"),      // Default to Gemini env var
+		BaseURL:          os.Getenv("GOOGLE_GEMINI_BASE_URL"), // Default to Gemini base URL
+		ModelName:        "gemini-3.5-flash",
+		BatchSize:        300,
+		RetryCount:       3,
+		Streaming:        true,
+		Thinking:         true,
+		DialogueSegments: true,
+		ThinkingLevel:    "high",
+		FreeQuota:        true,
+		UseColors:        true,
+		ProgressLog:      false,
+		QuietMode:        false,
 	}
 }
 
-// LoadEnvironmentForProvider loads environment variables based on the provider
-func (c *Config) LoadEnvironmentForProvider() {
-	switch c.Provider {
+// ProviderEnvVars returns the environment variables holding the API keys and
+// the base URL of a provider
+func ProviderEnvVars(provider string) (apiKeys string, baseURL string) {
+	switch provider {
 	case "openai":
-		// Load OpenAI environment variables
-		if len(c.APIKeys) == 0 {
-			c.APIKeys = parseAPIKeys("OPENAI_API_KEY")
-		}
-		if c.BaseURL == "" {
-			c.BaseURL = os.Getenv("OPENAI_BASE_URL")
-		}
-	case "gemini":
-		fallthrough
+		return "OPENAI_API_KEY", "OPENAI_BASE_URL"
 	default:
-		// Load Gemini environment variables
-		if len(c.APIKeys) == 0 {
-			c.APIKeys = parseAPIKeys("GEMINI_API_KEY")
-		}
-		if c.BaseURL == "" {
-			c.BaseURL = os.Getenv("GOOGLE_GEMINI_BASE_URL")
-		}
+		return "GEMINI_API_KEY", "GOOGLE_GEMINI_BASE_URL"
+	}
+}
+
+// LoadEnvironmentForProvider loads environment variables based on the provider
+func (c *Config) LoadEnvironmentForProvider() {
+	apiKeysVar, baseURLVar := ProviderEnvVars(c.Provider)
+	if len(c.APIKeys) == 0 {
+		c.APIKeys = parseAPIKeys(apiKeysVar)
+	}
+	if c.BaseURL == "" {
+		c.BaseURL = os.Getenv(baseURLVar)
 	}
 }
@@ -0,0 +1,442 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+)
+
+// FileName is the name of the configuration file inside the user config directory
+const FileName = "config.toml"
+
+// File is a parsed configuration file. It is a TOML subset: top-level keys,
+// [profiles.<name>] tables, strings, numbers, booleans and arrays of strings.
+type File struct {
+	Path     string
+	Profile  string              // Profile used when --profile is not given
+	Base     Profile             // Settings outside any profile, used by every run
+	Profiles map[string]*Profile // Named profiles
+}
+
+// Profile is a set of settings from the configuration file. Nil fields are not set.
+type Profile struct {
+	Provider       *string
+	Model          *string
+	BaseURL        *string
+	APIKeyEnv      *string // Environment variable holding comma-separated API keys
+	APIKeyFile     *string // File holding API keys, comma or newline separated
+	SourceLanguage *string
+	TargetLanguage *string
+	Description    *string
+	Glossary       []string // "term = translation" entries
+	OutputTemplate *string
+	OutputDir      *string
+	PromptTemplate *string
+	RulesDir       *string
+	StyleGuide     *string
+	BatchSize      *int
+	ThinkingLevel  *string
+	Temperature    *float32
+	TopP           *float32
+	TopK           *float32
+}
+
+// DefaultFilePath returns the configuration file in the user config directory,
+// $XDG_CONFIG_HOME/gst/config.toml on Linux
+func DefaultFilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gst", FileName)
+}
+
+// LoadFile reads and parses a configuration file
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewFileError("failed to read config file", err).WithContext("file_path", path)
+	}
+	file, err := ParseFile(string(data))
+	if err != nil {
+		return nil, errors.NewConfigurationError("invalid config file", err).WithContext("file_path", path)
+	}
+	file.Path = path
+	return file, nil
+}
+
+// ParseFile parses the contents of a configuration file
+func ParseFile(content string) (*File, error) {
+	file := &File{Profiles: map[string]*Profile{}}
+	current := &file.Base
+	inProfile := false
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(stripComment(lines[i]))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			name, err := parseTableName(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			if _, exists := file.Profiles[name]; exists {
+				return nil, fmt.Errorf("line %d: profile %q is defined twice", lineNumber, name)
+			}
+			current = &Profile{}
+			file.Profiles[name] = current
+			inProfile = true
+			continue
+		}
+
+		key, rawValue, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		key = unquoteKey(strings.TrimSpace(key))
+		rawValue = strings.TrimSpace(rawValue)
+
+		// Arrays may continue over several lines
+		for strings.HasPrefix(rawValue, "[") && !arrayClosed(rawValue) && i+1 < len(lines) {
+			i++
+			rawValue += " " + strings.TrimSpace(stripComment(lines[i]))
+		}
+
+		value, err := parseValue(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", lineNumber, key, err)
+		}
+
+		if key == "profile" && !inProfile {
+			name, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("line %d: profile must be a string", lineNumber)
+			}
+			file.Profile = name
+			continue
+		}
+		if err = current.set(key, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+	return file, nil
+}
+
+// Lookup returns a named profile, or an error listing the defined profiles
+func (f *File) Lookup(name string) (*Profile, error) {
+	if profile, ok := f.Profiles[name]; ok {
+		return profile, nil
+	}
+	return nil, errors.NewConfigurationError(fmt.Sprintf("profile %q is not defined", name), nil).
+		WithContext("file_path", f.Path).
+		WithContext("profiles", strings.Join(f.ProfileNames(), ", "))
+}
+
+// ProfileNames returns the names of the defined profiles in sorted order
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// APIKeys resolves the API key reference of the profile, or returns nil when it has none
+func (p *Profile) APIKeys() ([]string, error) {
+	var value string
+	switch {
+	case p.APIKeyEnv != nil:
+		value = os.Getenv(*p.APIKeyEnv)
+		if value == "" {
+			return nil, errors.NewConfigurationError("api_key_env refers to an empty environment variable", nil).WithContext("variable", *p.APIKeyEnv)
+		}
+	case p.APIKeyFile != nil:
+		data, err := os.ReadFile(expandHome(*p.APIKeyFile))
+		if err != nil {
+			return nil, errors.NewFileError("failed to read api_key_file", err).WithContext("file_path", *p.APIKeyFile)
+		}
+		value = strings.ReplaceAll(string(data), "\n", ",")
+	default:
+		return nil, nil
+	}
+
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(key); trimmed != "" {
+			keys = append(keys, trimmed)
+		}
+	}
+	return keys, nil
+}
+
+// set assigns one key of the configuration file
+func (p *Profile) set(key string, value any) error {
+	var err error
+	switch key {
+	case "provider":
+		p.Provider, err = stringValue(key, value)
+	case "model":
+		p.Model, err = stringValue(key, value)
+	case "base_url":
+		p.BaseURL, err = stringValue(key, value)
+	case "api_key_env":
+		p.APIKeyEnv, err = stringValue(key, value)
+	case "api_key_file":
+		p.APIKeyFile, err = stringValue(key, value)
+	case "source_language":
+		p.SourceLanguage, err = stringValue(key, value)
+	case "target_language":
+		p.TargetLanguage, err = stringValue(key, value)
+	case "output_template":
+		p.OutputTemplate, err = stringValue(key, value)
+	case "output_dir":
+		p.OutputDir, err = pathValue(key, value)
+	case "description":
+		p.Description, err = stringValue(key, value)
+	case "thinking_level":
+		p.ThinkingLevel, err = stringValue(key, value)
+	case "glossary":
+		p.Glossary, err = stringsValue(key, value)
+	case "prompt_template":
+		p.PromptTemplate, err = pathValue(key, value)
+	case "rules_dir":
+		p.RulesDir, err = pathValue(key, value)
+	case "style_guide":
+		p.StyleGuide, err = pathValue(key, value)
+	case "batch_size":
+		number, ok := value.(int64)
+		if !ok || number <= 0 {
+			return fmt.Errorf("batch_size must be a positive integer")
+		}
+		size := int(number)
+		p.BatchSize = &size
+	case "temperature":
+		p.Temperature, err = floatValue(key, value)
+	case "top_p":
+		p.TopP, err = floatValue(key, value)
+	case "top_k":
+		p.TopK, err = floatValue(key, value)
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	return err
+}
+
+func stringValue(key string, value any) (*string, error) {
+	text, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a string", key)
+	}
+	return &text, nil
+}
+
+// pathValue is a string value with a leading ~ expanded to the home directory
+func pathValue(key string, value any) (*string, error) {
+	path, err := stringValue(key, value)
+	if err != nil {
+		return nil, err
+	}
+	expanded := expandHome(*path)
+	return &expanded, nil
+}
+
+func stringsValue(key string, value any) ([]string, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of strings", key)
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		text, isString := item.(string)
+		if !isString {
+			return nil, fmt.Errorf("%s must be an array of strings", key)
+		}
+		result = append(result, text)
+	}
+	return result, nil
+}
+
+func floatValue(key string, value any) (*float32, error) {
+	var number float32
+	switch v := value.(type) {
+	case int64:
+		number = float32(v)
+	case float64:
+		number = float32(v)
+	default:
+		return nil, fmt.Errorf("%s must be a number", key)
+	}
+	return &number, nil
+}
+
+// parseTableName parses a [profiles.<name>] header
+func parseTableName(line string) (string, error) {
+	if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+		return "", fmt.Errorf("invalid table header %s", line)
+	}
+	header := strings.TrimSpace(line[1 : len(line)-1])
+	name, found := strings.CutPrefix(header, "profiles.")
+	if !found {
+		return "", fmt.Errorf("unknown table [%s], use [profiles.<name>]", header)
+	}
+	name = unquoteKey(strings.TrimSpace(name))
+	if name == "" {
+		return "", fmt.Errorf("profile name is empty")
+	}
+	return name, nil
+}
+
+// unquoteKey removes the quotes of a quoted key
+func unquoteKey(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+// parseValue parses a string, number, boolean or array value
+func parseValue(raw string) (any, error) {
+	value, rest, err := parseValuePrefix(raw)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return nil, fmt.Errorf("unexpected %q after value", strings.TrimSpace(rest))
+	}
+	return value, nil
+}
+
+// parseValuePrefix parses the value at the start of raw and returns the remaining text
+func parseValuePrefix(raw string) (any, string, error) {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case raw == "":
+		return nil, "", fmt.Errorf("missing value")
+	case raw[0] == '"':
+		end := 1
+		for ; end < len(raw); end++ {
+			if raw[end] == '\\' {
+				end++
+			} else if raw[end] == '"' {
+				break
+			}
+		}
+		if end >= len(raw) {
+			return nil, "", fmt.Errorf("unterminated string")
+		}
+		text, err := strconv.Unquote(raw[:end+1])
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid string %s", raw[:end+1])
+		}
+		return text, raw[end+1:], nil
+	case raw[0] == '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated string")
+		}
+		return raw[1 : end+1], raw[end+2:], nil
+	case raw[0] == '[':
+		var items []any
+		rest := strings.TrimSpace(raw[1:])
+		for !strings.HasPrefix(rest, "]") {
+			item, after, err := parseValuePrefix(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			items = append(items, item)
+			rest = strings.TrimSpace(after)
+			if strings.HasPrefix(rest, ",") {
+				rest = strings.TrimSpace(rest[1:])
+			} else if !strings.HasPrefix(rest, "]") {
+				return nil, "", fmt.Errorf("expected , or ] in array")
+			}
+		}
+		return items, rest[1:], nil
+	}
+
+	token := raw
+	if end := strings.IndexAny(raw, ",]"); end >= 0 {
+		token = raw[:end]
+	}
+	rest := raw[len(token):]
+	token = strings.TrimSpace(token)
+	switch token {
+	case "true":
+		return true, rest, nil
+	case "false":
+		return false, rest, nil
+	}
+	number := strings.ReplaceAll(token, "_", "")
+	if integer, err := strconv.ParseInt(number, 10, 64); err == nil {
+		return integer, rest, nil
+	}
+	if float, err := strconv.ParseFloat(number, 64); err == nil {
+		return float, rest, nil
+	}
+	return nil, "", fmt.Errorf("invalid value %q (strings must be quoted)", token)
+}
+
+// arrayClosed reports whether the brackets of an array value are balanced
+func arrayClosed(raw string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// stripComment removes a # comment that is not inside a string
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if rest, found := strings.CutPrefix(path, "~/"); found {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
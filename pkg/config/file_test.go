@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFile(t *testing.T) {
+	file, err := ParseFile(`# Used by every run
+target_language = "French"
+batch_size = 200
+profile = "anime-zh"
+
+[profiles.anime-zh]
+provider = "openai"
+model = 'deepseek-chat'
+api_key_env = "DEEPSEEK_KEYS"
+description = "Keep honorifics # and the jokes"
+glossary = [
+  "Nakama = 伙伴", # crew
+  "Senpai",
+]
+temperature = 0.7
+top_k = 40
+
+[profiles."plain"]
+thinking_level = "low"
+`)
+	if err != nil {
+		t.Fatalf("ParseFile() failed: %v", err)
+	}
+
+	if file.Profile != "anime-zh" {
+		t.Errorf("Profile = %q, want anime-zh", file.Profile)
+	}
+	if *file.Base.TargetLanguage != "French" || *file.Base.BatchSize != 200 {
+		t.Errorf("Unexpected base settings: %+v", file.Base)
+	}
+	if names := file.ProfileNames(); strings.Join(names, ",") != "anime-zh,plain" {
+		t.Errorf("ProfileNames() = %v", names)
+	}
+
+	profile, err := file.Lookup("anime-zh")
+	if err != nil {
+		t.Fatalf("Lookup() failed: %v", err)
+	}
+	if *profile.Provider != "openai" || *profile.Model != "deepseek-chat" || *profile.APIKeyEnv != "DEEPSEEK_KEYS" {
+		t.Errorf("Unexpected profile: %+v", profile)
+	}
+	if *profile.Description != "Keep honorifics # and the jokes" {
+		t.Errorf("Description = %q", *profile.Description)
+	}
+	if len(profile.Glossary) != 2 || profile.Glossary[0] != "Nakama = 伙伴" || profile.Glossary[1] != "Senpai" {
+		t.Errorf("Glossary = %q", profile.Glossary)
+	}
+	if *profile.Temperature != 0.7 || *profile.TopK != 40 || profile.TopP != nil {
+		t.Errorf("Unexpected sampling parameters: %+v", profile)
+	}
+
+	if _, err = file.Lookup("missing"); err == nil {
+		t.Error("Expected error for an undefined profile")
+	}
+}
+
+func TestParseFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown setting", "colour = \"red\"", `line 1: unknown setting "colour"`},
+		{"unquoted string", "\nmodel = gpt-4o", "line 2: model: invalid value"},
+		{"wrong type", "batch_size = \"big\"", "line 1: batch_size must be a positive integer"},
+		{"unknown table", "[server]", "line 1: unknown table [server]"},
+		{"duplicate profile", "[profiles.a]\n[profiles.a]", `line 2: profile "a" is defined twice`},
+		{"unterminated string", "model = \"gpt", "line 1: model: unterminated string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFile(tt.content)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseFile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileAPIKeys(t *testing.T) {
+	t.Setenv("GST_TEST_KEYS", "key-one, key-two")
+	variable := "GST_TEST_KEYS"
+	keys, err := (&Profile{APIKeyEnv: &variable}).APIKeys()
+	if err != nil || strings.Join(keys, ",") != "key-one,key-two" {
+		t.Errorf("APIKeys() from env = %v, %v", keys, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "keys")
+	if err = os.WriteFile(path, []byte("key-three\nkey-four\n"), 0600); err != nil {
+		t.Fatalf("Failed to write keys: %v", err)
+	}
+	keys, err = (&Profile{APIKeyFile: &path}).APIKeys()
+	if err != nil || strings.Join(keys, ",") != "key-three,key-four" {
+		t.Errorf("APIKeys() from file = %v, %v", keys, err)
+	}
+
+	empty := "GST_TEST_UNSET"
+	if _, err = (&Profile{APIKeyEnv: &empty}).APIKeys(); err == nil {
+		t.Error("Expected error for an empty environment variable")
+	}
+}
@@ -0,0 +1,345 @@
+package languages
+
+import (
+	"embed"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed profiles/*.txt
+var profileFiles embed.FS
+
+// Detection is the result of identifying the language of a text
+type Detection struct {
+	Code       string  // ISO 639-1 code, zh-Hans or zh-Hant for Chinese; empty when unknown
+	Name       string  // English name of the language
+	Confidence float64 // Between 0 and 1
+}
+
+// Known reports whether a language was identified
+func (d Detection) Known() bool {
+	return d.Code != ""
+}
+
+// Reliable reports whether the detection is confident enough to act on
+func (d Detection) Reliable() bool {
+	return d.Known() && d.Confidence >= 0.5
+}
+
+const (
+	minScriptLetters  = 4  // Letters needed to trust a script that identifies the language
+	minTrigramLetters = 15 // Letters needed to tell languages of the same script apart
+	fullTrigramScore  = 60 // Letters from which the trigram confidence is not reduced
+	maxSampleLetters  = 20000
+	profileSize       = 400 // Most frequent trigrams kept per language profile
+
+	// A single cue is only taken to be in another language than its file with
+	// enough letters, a confident detection and trigrams that are clearly
+	// closer to that language than to the language of the file
+	minCueLetters    = 25
+	minCueConfidence = 0.6
+	minCueSimilarity = 0.25
+	minCueMargin     = 2.5
+)
+
+// Scripts that identify a single language
+var scriptLanguages = map[string]string{
+	"Greek":      "el",
+	"Hebrew":     "he",
+	"Arabic":     "ar",
+	"Thai":       "th",
+	"Devanagari": "hi",
+	"Georgian":   "ka",
+	"Armenian":   "hy",
+}
+
+// Characters that only exist in simplified or only in traditional Chinese
+const (
+	simplifiedOnly  = "这说们个来时为会对过还没国发后问从经见开关长门间让谁现话吗车书东听买卖钱号电样欢谢爱觉亲边头实岁万两医学习认识"
+	traditionalOnly = "這說們個來時為會對過還沒國發後問從經見開關長門間讓誰現話嗎車書東聽買賣錢號電樣歡謝愛覺親邊頭實歲萬兩醫學習認識"
+)
+
+// profile holds the normalized trigram frequencies of a language
+type profile struct {
+	code     string
+	script   string
+	trigrams map[string]float64
+}
+
+var loadProfiles = sync.OnceValue(func() []profile {
+	entries, _ := profileFiles.ReadDir("profiles")
+	profiles := make([]profile, 0, len(entries))
+	for _, entry := range entries {
+		data, _ := profileFiles.ReadFile("profiles/" + entry.Name())
+		text := string(data)
+		script, _ := dominantScript(text)
+		profiles = append(profiles, profile{
+			code:     strings.TrimSuffix(entry.Name(), ".txt"),
+			script:   script,
+			trigrams: trigramVector(text, profileSize),
+		})
+	}
+	return profiles
+})
+
+// Detect identifies the language of a text, first by its script and then, for
+// scripts shared by several languages, by comparing character trigrams with
+// the built-in language profiles
+func Detect(text string) Detection {
+	script, counts := dominantScript(text)
+	letters := 0
+	for _, count := range counts {
+		letters += count
+	}
+	if letters == 0 {
+		return Detection{}
+	}
+
+	han, kana, hangul := counts["Han"], counts["Kana"], counts["Hangul"]
+	if cjk := han + kana + hangul; cjk*2 >= letters {
+		if cjk < minScriptLetters {
+			return Detection{}
+		}
+		share := float64(cjk) / float64(letters)
+		switch {
+		case hangul*2 >= cjk:
+			return newDetection("ko", share)
+		case kana*10 >= cjk:
+			return newDetection("ja", share)
+		default:
+			return newDetection(chineseVariant(text), share)
+		}
+	}
+
+	share := float64(counts[script]) / float64(letters)
+	if code, ok := scriptLanguages[script]; ok {
+		if counts[script] < minScriptLetters {
+			return Detection{}
+		}
+		return newDetection(code, share)
+	}
+	if counts[script] < minTrigramLetters {
+		return Detection{}
+	}
+
+	vector := trigramVector(text, 0)
+	best, second := "", 0.0
+	bestScore := 0.0
+	for _, candidate := range loadProfiles() {
+		if candidate.script != script {
+			continue
+		}
+		score := cosine(vector, candidate.trigrams)
+		if score > bestScore {
+			best, second, bestScore = candidate.code, bestScore, score
+		} else if score > second {
+			second = score
+		}
+	}
+	if best == "" {
+		return Detection{}
+	}
+
+	// A clear margin over the runner-up and enough text make a confident result
+	margin := math.Min(1, (bestScore-second)/bestScore*4)
+	length := math.Min(1, float64(counts[script])/fullTrigramScore)
+	return Detection{Code: best, Name: Name(best), Confidence: share * margin * math.Sqrt(length)}
+}
+
+// CueIn reports whether the text of a single cue of a file in language source
+// is in language instead. Short cues are easily mistaken for another language
+// by names and loan words, so it demands more than Detect: enough letters, a
+// high confidence and, for scripts shared by several languages, trigrams much
+// closer to language than to source.
+func CueIn(text string, language string, source string) bool {
+	detection := Detect(text)
+	if detection.Confidence < minCueConfidence || !Matches(detection.Code, language) || Matches(detection.Code, source) {
+		return false
+	}
+	script, counts := dominantScript(text)
+	if _, ok := scriptLanguages[script]; ok || script == "Han" || script == "Kana" || script == "Hangul" {
+		// The script alone identifies the language
+		return true
+	}
+	if counts[script] < minCueLetters {
+		return false
+	}
+
+	vector := trigramVector(text, 0)
+	similarity, sourceSimilarity := 0.0, 0.0
+	for _, candidate := range loadProfiles() {
+		switch {
+		case candidate.code == detection.Code:
+			similarity = cosine(vector, candidate.trigrams)
+		case candidate.script == script && Matches(candidate.code, source):
+			sourceSimilarity = cosine(vector, candidate.trigrams)
+		}
+	}
+	return similarity >= minCueSimilarity && similarity >= sourceSimilarity*minCueMargin
+}
+
+// DetectAll identifies the language of a whole subtitle file from its cues
+func DetectAll(texts []string) Detection {
+	var sample strings.Builder
+	for _, text := range texts {
+		if sample.Len() > maxSampleLetters {
+			break
+		}
+		sample.WriteString(text)
+		sample.WriteString("\n")
+	}
+	return Detect(sample.String())
+}
+
+// Matches reports whether a detected language code is the given language,
+// which may be anything Lookup accepts ("Brazilian Portuguese", "pt-BR").
+// Regional variants match their language; Chinese without a script matches
+// both simplified and traditional Chinese.
+func Matches(code string, language string) bool {
+	left, okLeft := Lookup(code)
+	right, okRight := Lookup(language)
+	if !okLeft || !okRight || left.Base() != right.Base() {
+		return false
+	}
+	if left.Base() == "zh" {
+		return left.Tag == right.Tag || left.Tag == "zh" || right.Tag == "zh"
+	}
+	return true
+}
+
+// Name returns the English name of a language code, or the code itself when
+// it is not in the registry
+func Name(code string) string {
+	if language, ok := Lookup(code); ok {
+		return language.Name
+	}
+	return code
+}
+
+// newDetection builds a detection for a language identified by its script
+func newDetection(code string, confidence float64) Detection {
+	return Detection{Code: code, Name: Name(code), Confidence: confidence}
+}
+
+// chineseVariant tells simplified from traditional Chinese by the characters
+// that only one of them uses
+func chineseVariant(text string) string {
+	simplified, traditional := 0, 0
+	for _, r := range text {
+		if strings.ContainsRune(simplifiedOnly, r) {
+			simplified++
+		} else if strings.ContainsRune(traditionalOnly, r) {
+			traditional++
+		}
+	}
+	switch {
+	case simplified > traditional:
+		return "zh-Hans"
+	case traditional > simplified:
+		return "zh-Hant"
+	}
+	return "zh"
+}
+
+// dominantScript counts the letters of each script and returns the most used
+func dominantScript(text string) (string, map[string]int) {
+	counts := map[string]int{}
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			counts[scriptOf(r)]++
+		}
+	}
+	best := ""
+	for script, count := range counts {
+		if count > counts[best] || (count == counts[best] && script < best) {
+			best = script
+		}
+	}
+	return best, counts
+}
+
+// scriptOf names the script of a letter
+func scriptOf(r rune) string {
+	switch {
+	case r < 0x80 || unicode.Is(unicode.Latin, r):
+		return "Latin"
+	case unicode.Is(unicode.Han, r):
+		return "Han"
+	case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+		return "Kana"
+	case unicode.Is(unicode.Hangul, r):
+		return "Hangul"
+	case unicode.Is(unicode.Cyrillic, r):
+		return "Cyrillic"
+	case unicode.Is(unicode.Greek, r):
+		return "Greek"
+	case unicode.Is(unicode.Hebrew, r):
+		return "Hebrew"
+	case unicode.Is(unicode.Arabic, r):
+		return "Arabic"
+	case unicode.Is(unicode.Thai, r):
+		return "Thai"
+	case unicode.Is(unicode.Devanagari, r):
+		return "Devanagari"
+	case unicode.Is(unicode.Georgian, r):
+		return "Georgian"
+	case unicode.Is(unicode.Armenian, r):
+		return "Armenian"
+	}
+	return "Other"
+}
+
+// trigramVector counts the letter trigrams of every word, padded with spaces,
+// keeps the limit most frequent (all when limit is 0) and normalizes the
+// counts to unit length
+func trigramVector(text string, limit int) map[string]float64 {
+	counts := map[string]float64{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			counts[string(runes[i:i+3])]++
+		}
+	}
+
+	if limit > 0 && len(counts) > limit {
+		keys := make([]string, 0, len(counts))
+		for key := range counts {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if counts[keys[i]] != counts[keys[j]] {
+				return counts[keys[i]] > counts[keys[j]]
+			}
+			return keys[i] < keys[j]
+		})
+		for _, key := range keys[limit:] {
+			delete(counts, key)
+		}
+	}
+
+	norm := 0.0
+	for _, count := range counts {
+		norm += count * count
+	}
+	norm = math.Sqrt(norm)
+	for key := range counts {
+		counts[key] /= norm
+	}
+	return counts
+}
+
+// cosine returns the cosine similarity of two normalized vectors
+func cosine(left, right map[string]float64) float64 {
+	if len(left) > len(right) {
+		left, right = right, left
+	}
+	score := 0.0
+	for key, value := range left {
+		score += value * right[key]
+	}
+	return score
+}
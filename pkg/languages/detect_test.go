@@ -0,0 +1,112 @@
+package languages
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"I can't believe you actually came back after all these years.", "en"},
+		{"No puedo creer que hayas vuelto después de tantos años.", "es"},
+		{"Je n'arrive pas à croire que tu sois revenu après toutes ces années.", "fr"},
+		{"Ich kann nicht glauben, dass du nach all den Jahren zurückgekommen bist.", "de"},
+		{"Non posso credere che tu sia tornato dopo tutti questi anni.", "it"},
+		{"Não acredito que você voltou depois de todos esses anos.", "pt"},
+		{"Ik kan niet geloven dat je na al die jaren terug bent gekomen.", "nl"},
+		{"Jag kan inte tro att du faktiskt kom tillbaka efter alla dessa år.", "sv"},
+		{"Nie mogę uwierzyć, że wróciłeś po tylu latach.", "pl"},
+		{"Bunca yıldan sonra geri döndüğüne inanamıyorum.", "tr"},
+		{"Aku tidak percaya kamu benar-benar kembali setelah bertahun-tahun.", "id"},
+		{"Nu-mi vine să cred că te-ai întors după atâția ani.", "ro"},
+		{"Nemůžu uvěřit, že ses po všech těch letech vrátil.", "cs"},
+		{"En voi uskoa, että tulit takaisin kaikkien näiden vuosien jälkeen.", "fi"},
+		{"Nem hiszem el, hogy ennyi év után visszajöttél.", "hu"},
+		{"Tôi không thể tin là anh đã quay lại sau ngần ấy năm.", "vi"},
+		{"Не могу поверить, что ты вернулся спустя столько лет.", "ru"},
+		{"Не можу повірити, що ти повернувся через стільки років.", "uk"},
+		{"Δεν μπορώ να πιστέψω ότι γύρισες μετά από τόσα χρόνια.", "el"},
+		{"何年も経ってから本当に戻ってきたなんて信じられない。", "ja"},
+		{"그렇게 오랜 세월이 지나고 돌아왔다니 믿을 수가 없어.", "ko"},
+		{"我真不敢相信这么多年后你会回来。", "zh-Hans"},
+		{"我真不敢相信這麼多年後你會回來。", "zh-Hant"},
+		{"OK.", ""},
+		{"♪ ♪", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want+" "+tt.text, func(t *testing.T) {
+			got := Detect(tt.text)
+			if got.Code != tt.want {
+				t.Errorf("Detect(%q) = %+v, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCueIn(t *testing.T) {
+	tests := []struct {
+		text     string
+		language string
+		want     bool
+	}{
+		{"Je ne sais pas ce que tu veux dire par là, mon ami.", "French", true},
+		{"Ich weiß nicht, was du meinst, mein Freund.", "German", true},
+		{"Δεν μπορώ να πιστέψω ότι γύρισες.", "Greek", true},
+		// English cues with foreign names and words
+		{"Senator Alvarez has arrived.", "Spanish", false},
+		{"Radio station, central area.", "Italian", false},
+		{"Taxi! Hotel Central, please.", "Romanian", false},
+		{"We ate pasta carbonara at Luigi's trattoria.", "Italian", false},
+		{"We ate pasta carbonara at Luigi's trattoria.", "Swedish", false},
+		{"Welcome to the Hotel Bellagio, Señor Garcia.", "Spanish", false},
+		{"Bonjour, Madame Dubois.", "French", false},
+		// Too short to tell
+		{"Merci beaucoup.", "French", false},
+	}
+
+	for _, tt := range tests {
+		if got := CueIn(tt.text, tt.language, "en"); got != tt.want {
+			t.Errorf("CueIn(%q, %q, en) = %v, want %v (detected %+v)", tt.text, tt.language, got, tt.want, Detect(tt.text))
+		}
+	}
+}
+
+func TestDetectAll(t *testing.T) {
+	cues := []string{
+		"Where have you been?",
+		"I waited for hours.",
+		"- Sorry.\n- Don't be sorry, just tell me the truth.",
+		"We need to leave before they find us.",
+	}
+	got := DetectAll(cues)
+	if got.Code != "en" || !got.Reliable() {
+		t.Errorf("DetectAll() = %+v, want a reliable en", got)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		code     string
+		language string
+		want     bool
+	}{
+		{"en", "English", true},
+		{"en", "British English", true},
+		{"pt", "Brazilian Portuguese", true},
+		{"zh-Hans", "Simplified Chinese", true},
+		{"zh-Hant", "Simplified Chinese", false},
+		{"zh", "Traditional Chinese", true},
+		{"zh-Hant", "cht", true},
+		{"ja", "Chinese", false},
+		{"es", "eng", false},
+		{"en", "Klingon", false},
+		{"", "English", false},
+	}
+
+	for _, tt := range tests {
+		if got := Matches(tt.code, tt.language); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.code, tt.language, got, tt.want)
+		}
+	}
+}
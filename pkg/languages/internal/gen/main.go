@@ -0,0 +1,94 @@
+// Command gen turns registry.tsv into the Go table of the language registry.
+//
+//	go run ./internal/gen registry.tsv registry_table.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: gen <registry.tsv> <output.go>")
+		os.Exit(2)
+	}
+	if err := generate(os.Args[1], os.Args[2]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func generate(inputPath string, outputPath string) error {
+	input, err := os.Open(inputPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = input.Close() }()
+
+	var buffer bytes.Buffer
+	buffer.WriteString("// Code generated by internal/gen from registry.tsv; DO NOT EDIT.\n\n")
+	buffer.WriteString("package languages\n\n")
+	buffer.WriteString("var registry = []Language{\n")
+
+	tags := map[string]bool{}
+	scanner := bufio.NewScanner(input)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 9 {
+			return fmt.Errorf("%s:%d: expected 9 columns, got %d", inputPath, lineNumber, len(fields))
+		}
+		tag, name := fields[0], fields[5]
+		if tag == "" || name == "" {
+			return fmt.Errorf("%s:%d: tag and English name are required", inputPath, lineNumber)
+		}
+		if tags[strings.ToLower(tag)] {
+			return fmt.Errorf("%s:%d: duplicate tag %s", inputPath, lineNumber, tag)
+		}
+		tags[strings.ToLower(tag)] = true
+
+		var rtl, cjk bool
+		for _, flag := range strings.Split(fields[7], ",") {
+			switch strings.TrimSpace(flag) {
+			case "":
+			case "rtl":
+				rtl = true
+			case "cjk":
+				cjk = true
+			default:
+				return fmt.Errorf("%s:%d: unknown flag %q", inputPath, lineNumber, flag)
+			}
+		}
+		var aliases []string
+		for _, alias := range strings.Split(fields[8], ";") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		fmt.Fprintf(&buffer, "\t{Tag: %q, ISO6391: %q, ISO6392B: %q, ISO6392T: %q, ISO6393: %q, Name: %q, Native: %q, RTL: %t, CJK: %t",
+			tag, fields[1], fields[2], fields[3], fields[4], name, fields[6], rtl, cjk)
+		if len(aliases) > 0 {
+			fmt.Fprintf(&buffer, ", Aliases: %#v", aliases)
+		}
+		buffer.WriteString("},\n")
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	buffer.WriteString("}\n")
+
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputPath, source, 0644)
+}
@@ -0,0 +1,158 @@
+// Package languages is the registry of language tags, codes and names, and an
+// offline identifier for the language of subtitle text.
+package languages
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:generate go run ./internal/gen registry.tsv registry_table.go
+
+// Language is an entry of the language registry
+type Language struct {
+	Tag      string   // BCP-47 tag, e.g. "pt-BR" or "zh-Hans"
+	ISO6391  string   // Two-letter code, empty when the language has none
+	ISO6392B string   // Bibliographic three-letter code, as used in Matroska tags
+	ISO6392T string   // Terminology three-letter code
+	ISO6393  string   // ISO 639-3 code
+	Name     string   // English name
+	Native   string   // Name in the language itself
+	RTL      bool     // Written right to left
+	CJK      bool     // Chinese, Japanese or Korean
+	Aliases  []string // Other names and legacy codes, e.g. "chs"
+}
+
+// Base returns the primary language subtag, e.g. "pt" for pt-BR
+func (l Language) Base() string {
+	base, _, _ := strings.Cut(strings.ToLower(l.Tag), "-")
+	return base
+}
+
+// MatroskaCode returns the ISO 639-2 code used in Matroska language tags, or
+// "und" when the language has none
+func (l Language) MatroskaCode() string {
+	switch {
+	case l.ISO6392B != "":
+		return l.ISO6392B
+	case l.ISO6393 != "":
+		return l.ISO6393
+	}
+	return "und"
+}
+
+// String returns the English name
+func (l Language) String() string {
+	return l.Name
+}
+
+// index maps every tag, code, name and alias to its registry entry. Base
+// languages come before their regional variants, so a shared code like "por"
+// finds the base language.
+var index = sync.OnceValue(func() map[string]int {
+	keys := map[string]int{}
+	for i, language := range registry {
+		for _, key := range append([]string{language.Tag, language.ISO6391, language.ISO6392B, language.ISO6392T,
+			language.ISO6393, language.Name, language.Native}, language.Aliases...) {
+			if key = normalizeKey(key); key != "" {
+				if _, exists := keys[key]; !exists {
+					keys[key] = i
+				}
+			}
+		}
+	}
+	return keys
+})
+
+// Lookup finds a language by BCP-47 tag, ISO 639 code, English or native name
+// or alias, case-insensitively. Tags with an unknown region or script fall back
+// to the language ("fr-BE" is French), and misspelled names of at least four
+// letters find the closest name ("portugese").
+func Lookup(query string) (Language, bool) {
+	key := normalizeKey(query)
+	if key == "" {
+		return Language{}, false
+	}
+	for candidate := key; ; {
+		if i, ok := index()[candidate]; ok {
+			return registry[i], true
+		}
+		cut := strings.LastIndex(candidate, "-")
+		if cut <= 0 {
+			break
+		}
+		candidate = candidate[:cut]
+	}
+	return fuzzyLookup(key)
+}
+
+// All returns every language of the registry
+func All() []Language {
+	return slices.Clone(registry)
+}
+
+// fuzzyLookup returns the language whose name or alias is closest to key, if
+// exactly one is within the allowed edit distance
+func fuzzyLookup(key string) (Language, bool) {
+	if len([]rune(key)) < 4 {
+		return Language{}, false
+	}
+	limit := 1
+	switch length := len([]rune(key)); {
+	case length > 8:
+		limit = 3
+	case length > 4:
+		limit = 2
+	}
+
+	best, bestDistance, ambiguous := -1, limit+1, false
+	for i, language := range registry {
+		distance := limit + 1
+		for _, name := range append([]string{language.Name, language.Native}, language.Aliases...) {
+			if name = normalizeKey(name); len([]rune(name)) >= 4 && !strings.Contains(name, "-") {
+				distance = min(distance, editDistance(key, name))
+			}
+		}
+		switch {
+		case distance < bestDistance:
+			best, bestDistance, ambiguous = i, distance, false
+		case distance == bestDistance && distance <= limit:
+			ambiguous = true
+		}
+	}
+	if best < 0 || ambiguous {
+		return Language{}, false
+	}
+	return registry[best], true
+}
+
+// normalizeKey lowercases a query, turns underscores into hyphens and
+// collapses whitespace
+func normalizeKey(query string) string {
+	query = strings.ReplaceAll(strings.ToLower(query), "_", "-")
+	return strings.Join(strings.FieldsFunc(query, unicode.IsSpace), " ")
+}
+
+// editDistance returns the Levenshtein distance of two strings
+func editDistance(left string, right string) int {
+	a, b := []rune(left), []rune(right)
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,128 @@
+// Code generated by internal/gen from registry.tsv; DO NOT EDIT.
+
+package languages
+
+var registry = []Language{
+	{Tag: "af", ISO6391: "af", ISO6392B: "afr", ISO6392T: "afr", ISO6393: "afr", Name: "Afrikaans", Native: "Afrikaans", RTL: false, CJK: false},
+	{Tag: "sq", ISO6391: "sq", ISO6392B: "alb", ISO6392T: "sqi", ISO6393: "sqi", Name: "Albanian", Native: "Shqip", RTL: false, CJK: false},
+	{Tag: "am", ISO6391: "am", ISO6392B: "amh", ISO6392T: "amh", ISO6393: "amh", Name: "Amharic", Native: "አማርኛ", RTL: false, CJK: false},
+	{Tag: "ar", ISO6391: "ar", ISO6392B: "ara", ISO6392T: "ara", ISO6393: "ara", Name: "Arabic", Native: "العربية", RTL: true, CJK: false},
+	{Tag: "hy", ISO6391: "hy", ISO6392B: "arm", ISO6392T: "hye", ISO6393: "hye", Name: "Armenian", Native: "Հայերեն", RTL: false, CJK: false},
+	{Tag: "az", ISO6391: "az", ISO6392B: "aze", ISO6392T: "aze", ISO6393: "aze", Name: "Azerbaijani", Native: "Azərbaycan dili", RTL: false, CJK: false},
+	{Tag: "eu", ISO6391: "eu", ISO6392B: "baq", ISO6392T: "eus", ISO6393: "eus", Name: "Basque", Native: "Euskara", RTL: false, CJK: false},
+	{Tag: "be", ISO6391: "be", ISO6392B: "bel", ISO6392T: "bel", ISO6393: "bel", Name: "Belarusian", Native: "Беларуская", RTL: false, CJK: false},
+	{Tag: "bn", ISO6391: "bn", ISO6392B: "ben", ISO6392T: "ben", ISO6393: "ben", Name: "Bengali", Native: "বাংলা", RTL: false, CJK: false, Aliases: []string{"Bangla"}},
+	{Tag: "bs", ISO6391: "bs", ISO6392B: "bos", ISO6392T: "bos", ISO6393: "bos", Name: "Bosnian", Native: "Bosanski", RTL: false, CJK: false},
+	{Tag: "bg", ISO6391: "bg", ISO6392B: "bul", ISO6392T: "bul", ISO6393: "bul", Name: "Bulgarian", Native: "Български", RTL: false, CJK: false},
+	{Tag: "my", ISO6391: "my", ISO6392B: "bur", ISO6392T: "mya", ISO6393: "mya", Name: "Burmese", Native: "မြန်မာ", RTL: false, CJK: false, Aliases: []string{"Myanmar"}},
+	{Tag: "ca", ISO6391: "ca", ISO6392B: "cat", ISO6392T: "cat", ISO6393: "cat", Name: "Catalan", Native: "Català", RTL: false, CJK: false, Aliases: []string{"Catalan language"}},
+	{Tag: "zh", ISO6391: "zh", ISO6392B: "chi", ISO6392T: "zho", ISO6393: "zho", Name: "Chinese", Native: "中文", RTL: false, CJK: true, Aliases: []string{"cmn", "Chinese language"}},
+	{Tag: "zh-Hans", ISO6391: "zh", ISO6392B: "chi", ISO6392T: "zho", ISO6393: "zho", Name: "Simplified Chinese", Native: "简体中文", RTL: false, CJK: true, Aliases: []string{"chs", "zh-CN", "zh-SG", "Mandarin", "Chinese Simplified"}},
+	{Tag: "zh-Hant", ISO6391: "zh", ISO6392B: "chi", ISO6392T: "zho", ISO6393: "zho", Name: "Traditional Chinese", Native: "繁體中文", RTL: false, CJK: true, Aliases: []string{"cht", "zh-TW", "zh-MO", "Chinese Traditional"}},
+	{Tag: "yue", ISO6391: "", ISO6392B: "", ISO6392T: "", ISO6393: "", Name: "Cantonese", Native: "粵語", RTL: false, CJK: true, Aliases: []string{"zh-HK", "yue-Hant", "Cantonese Chinese"}},
+	{Tag: "hr", ISO6391: "hr", ISO6392B: "hrv", ISO6392T: "hrv", ISO6393: "hrv", Name: "Croatian", Native: "Hrvatski", RTL: false, CJK: false},
+	{Tag: "cs", ISO6391: "cs", ISO6392B: "cze", ISO6392T: "ces", ISO6393: "ces", Name: "Czech", Native: "Čeština", RTL: false, CJK: false, Aliases: []string{"Czech language", "Cestina"}},
+	{Tag: "da", ISO6391: "da", ISO6392B: "dan", ISO6392T: "dan", ISO6393: "dan", Name: "Danish", Native: "Dansk", RTL: false, CJK: false},
+	{Tag: "dv", ISO6391: "dv", ISO6392B: "div", ISO6392T: "div", ISO6393: "div", Name: "Divehi", Native: "ދިވެހި", RTL: true, CJK: false, Aliases: []string{"Dhivehi", "Maldivian"}},
+	{Tag: "nl", ISO6391: "nl", ISO6392B: "dut", ISO6392T: "nld", ISO6393: "nld", Name: "Dutch", Native: "Nederlands", RTL: false, CJK: false, Aliases: []string{"Flemish"}},
+	{Tag: "en", ISO6391: "en", ISO6392B: "eng", ISO6392T: "eng", ISO6393: "eng", Name: "English", Native: "English", RTL: false, CJK: false},
+	{Tag: "en-US", ISO6391: "en", ISO6392B: "eng", ISO6392T: "eng", ISO6393: "eng", Name: "American English", Native: "American English", RTL: false, CJK: false, Aliases: []string{"US English"}},
+	{Tag: "en-GB", ISO6391: "en", ISO6392B: "eng", ISO6392T: "eng", ISO6393: "eng", Name: "British English", Native: "British English", RTL: false, CJK: false, Aliases: []string{"UK English"}},
+	{Tag: "en-AU", ISO6391: "en", ISO6392B: "eng", ISO6392T: "eng", ISO6393: "eng", Name: "Australian English", Native: "Australian English", RTL: false, CJK: false},
+	{Tag: "en-CA", ISO6391: "en", ISO6392B: "eng", ISO6392T: "eng", ISO6393: "eng", Name: "Canadian English", Native: "Canadian English", RTL: false, CJK: false},
+	{Tag: "eo", ISO6391: "eo", ISO6392B: "epo", ISO6392T: "epo", ISO6393: "epo", Name: "Esperanto", Native: "Esperanto", RTL: false, CJK: false},
+	{Tag: "et", ISO6391: "et", ISO6392B: "est", ISO6392T: "est", ISO6393: "est", Name: "Estonian", Native: "Eesti", RTL: false, CJK: false},
+	{Tag: "fil", ISO6391: "", ISO6392B: "fil", ISO6392T: "fil", ISO6393: "fil", Name: "Filipino", Native: "Filipino", RTL: false, CJK: false},
+	{Tag: "fj", ISO6391: "fj", ISO6392B: "fij", ISO6392T: "fij", ISO6393: "fij", Name: "Fijian", Native: "Na Vosa Vakaviti", RTL: false, CJK: false},
+	{Tag: "fi", ISO6391: "fi", ISO6392B: "fin", ISO6392T: "fin", ISO6393: "fin", Name: "Finnish", Native: "Suomi", RTL: false, CJK: false},
+	{Tag: "fr", ISO6391: "fr", ISO6392B: "fre", ISO6392T: "fra", ISO6393: "fra", Name: "French", Native: "Français", RTL: false, CJK: false, Aliases: []string{"Francais"}},
+	{Tag: "fr-CA", ISO6391: "fr", ISO6392B: "fre", ISO6392T: "fra", ISO6393: "fra", Name: "Canadian French", Native: "Français canadien", RTL: false, CJK: false, Aliases: []string{"Quebec French"}},
+	{Tag: "gl", ISO6391: "gl", ISO6392B: "glg", ISO6392T: "glg", ISO6393: "glg", Name: "Galician", Native: "Galego", RTL: false, CJK: false},
+	{Tag: "ka", ISO6391: "ka", ISO6392B: "geo", ISO6392T: "kat", ISO6393: "kat", Name: "Georgian", Native: "ქართული", RTL: false, CJK: false},
+	{Tag: "de", ISO6391: "de", ISO6392B: "ger", ISO6392T: "deu", ISO6393: "deu", Name: "German", Native: "Deutsch", RTL: false, CJK: false},
+	{Tag: "de-AT", ISO6391: "de", ISO6392B: "ger", ISO6392T: "deu", ISO6393: "deu", Name: "Austrian German", Native: "Österreichisches Deutsch", RTL: false, CJK: false},
+	{Tag: "de-CH", ISO6391: "de", ISO6392B: "ger", ISO6392T: "deu", ISO6393: "deu", Name: "Swiss German", Native: "Schweizerdeutsch", RTL: false, CJK: false},
+	{Tag: "el", ISO6391: "el", ISO6392B: "gre", ISO6392T: "ell", ISO6393: "ell", Name: "Greek", Native: "Ελληνικά", RTL: false, CJK: false},
+	{Tag: "gu", ISO6391: "gu", ISO6392B: "guj", ISO6392T: "guj", ISO6393: "guj", Name: "Gujarati", Native: "ગુજરાતી", RTL: false, CJK: false},
+	{Tag: "ht", ISO6391: "ht", ISO6392B: "hat", ISO6392T: "hat", ISO6393: "hat", Name: "Haitian Creole", Native: "Kreyòl ayisyen", RTL: false, CJK: false, Aliases: []string{"Haitian"}},
+	{Tag: "ha", ISO6391: "ha", ISO6392B: "hau", ISO6392T: "hau", ISO6393: "hau", Name: "Hausa", Native: "Hausa", RTL: false, CJK: false},
+	{Tag: "haw", ISO6391: "", ISO6392B: "haw", ISO6392T: "haw", ISO6393: "haw", Name: "Hawaiian", Native: "ʻŌlelo Hawaiʻi", RTL: false, CJK: false},
+	{Tag: "he", ISO6391: "he", ISO6392B: "heb", ISO6392T: "heb", ISO6393: "heb", Name: "Hebrew", Native: "עברית", RTL: true, CJK: false, Aliases: []string{"iw"}},
+	{Tag: "hi", ISO6391: "hi", ISO6392B: "hin", ISO6392T: "hin", ISO6393: "hin", Name: "Hindi", Native: "हिन्दी", RTL: false, CJK: false},
+	{Tag: "hu", ISO6391: "hu", ISO6392B: "hun", ISO6392T: "hun", ISO6393: "hun", Name: "Hungarian", Native: "Magyar", RTL: false, CJK: false},
+	{Tag: "is", ISO6391: "is", ISO6392B: "ice", ISO6392T: "isl", ISO6393: "isl", Name: "Icelandic", Native: "Íslenska", RTL: false, CJK: false},
+	{Tag: "ig", ISO6391: "ig", ISO6392B: "ibo", ISO6392T: "ibo", ISO6393: "ibo", Name: "Igbo", Native: "Igbo", RTL: false, CJK: false},
+	{Tag: "id", ISO6391: "id", ISO6392B: "ind", ISO6392T: "ind", ISO6393: "ind", Name: "Indonesian", Native: "Bahasa Indonesia", RTL: false, CJK: false, Aliases: []string{"in", "Bahasa"}},
+	{Tag: "ga", ISO6391: "ga", ISO6392B: "gle", ISO6392T: "gle", ISO6393: "gle", Name: "Irish", Native: "Gaeilge", RTL: false, CJK: false, Aliases: []string{"Irish Gaelic"}},
+	{Tag: "it", ISO6391: "it", ISO6392B: "ita", ISO6392T: "ita", ISO6393: "ita", Name: "Italian", Native: "Italiano", RTL: false, CJK: false},
+	{Tag: "ja", ISO6391: "ja", ISO6392B: "jpn", ISO6392T: "jpn", ISO6393: "jpn", Name: "Japanese", Native: "日本語", RTL: false, CJK: true},
+	{Tag: "jv", ISO6391: "jv", ISO6392B: "jav", ISO6392T: "jav", ISO6393: "jav", Name: "Javanese", Native: "Basa Jawa", RTL: false, CJK: false},
+	{Tag: "kn", ISO6391: "kn", ISO6392B: "kan", ISO6392T: "kan", ISO6393: "kan", Name: "Kannada", Native: "ಕನ್ನಡ", RTL: false, CJK: false},
+	{Tag: "kk", ISO6391: "kk", ISO6392B: "kaz", ISO6392T: "kaz", ISO6393: "kaz", Name: "Kazakh", Native: "Қазақ тілі", RTL: false, CJK: false},
+	{Tag: "km", ISO6391: "km", ISO6392B: "khm", ISO6392T: "khm", ISO6393: "khm", Name: "Khmer", Native: "ខ្មែរ", RTL: false, CJK: false, Aliases: []string{"Cambodian"}},
+	{Tag: "ko", ISO6391: "ko", ISO6392B: "kor", ISO6392T: "kor", ISO6393: "kor", Name: "Korean", Native: "한국어", RTL: false, CJK: true},
+	{Tag: "ku", ISO6391: "ku", ISO6392B: "kur", ISO6392T: "kur", ISO6393: "kur", Name: "Kurdish", Native: "Kurdî", RTL: false, CJK: false},
+	{Tag: "ky", ISO6391: "ky", ISO6392B: "kir", ISO6392T: "kir", ISO6393: "kir", Name: "Kyrgyz", Native: "Кыргызча", RTL: false, CJK: false, Aliases: []string{"Kirghiz"}},
+	{Tag: "lo", ISO6391: "lo", ISO6392B: "lao", ISO6392T: "lao", ISO6393: "lao", Name: "Lao", Native: "ລາວ", RTL: false, CJK: false, Aliases: []string{"Laotian"}},
+	{Tag: "la", ISO6391: "la", ISO6392B: "lat", ISO6392T: "lat", ISO6393: "lat", Name: "Latin", Native: "Latina", RTL: false, CJK: false},
+	{Tag: "lv", ISO6391: "lv", ISO6392B: "lav", ISO6392T: "lav", ISO6393: "lav", Name: "Latvian", Native: "Latviešu", RTL: false, CJK: false},
+	{Tag: "lt", ISO6391: "lt", ISO6392B: "lit", ISO6392T: "lit", ISO6393: "lit", Name: "Lithuanian", Native: "Lietuvių", RTL: false, CJK: false},
+	{Tag: "lb", ISO6391: "lb", ISO6392B: "ltz", ISO6392T: "ltz", ISO6393: "ltz", Name: "Luxembourgish", Native: "Lëtzebuergesch", RTL: false, CJK: false},
+	{Tag: "mk", ISO6391: "mk", ISO6392B: "mac", ISO6392T: "mkd", ISO6393: "mkd", Name: "Macedonian", Native: "Македонски", RTL: false, CJK: false},
+	{Tag: "ms", ISO6391: "ms", ISO6392B: "may", ISO6392T: "msa", ISO6393: "msa", Name: "Malay", Native: "Bahasa Melayu", RTL: false, CJK: false},
+	{Tag: "ml", ISO6391: "ml", ISO6392B: "mal", ISO6392T: "mal", ISO6393: "mal", Name: "Malayalam", Native: "മലയാളം", RTL: false, CJK: false},
+	{Tag: "mt", ISO6391: "mt", ISO6392B: "mlt", ISO6392T: "mlt", ISO6393: "mlt", Name: "Maltese", Native: "Malti", RTL: false, CJK: false},
+	{Tag: "mi", ISO6391: "mi", ISO6392B: "mao", ISO6392T: "mri", ISO6393: "mri", Name: "Maori", Native: "Māori", RTL: false, CJK: false},
+	{Tag: "mr", ISO6391: "mr", ISO6392B: "mar", ISO6392T: "mar", ISO6393: "mar", Name: "Marathi", Native: "मराठी", RTL: false, CJK: false},
+	{Tag: "mn", ISO6391: "mn", ISO6392B: "mon", ISO6392T: "mon", ISO6393: "mon", Name: "Mongolian", Native: "Монгол", RTL: false, CJK: false},
+	{Tag: "cnr", ISO6391: "", ISO6392B: "", ISO6392T: "", ISO6393: "cnr", Name: "Montenegrin", Native: "Crnogorski", RTL: false, CJK: false},
+	{Tag: "ne", ISO6391: "ne", ISO6392B: "nep", ISO6392T: "nep", ISO6393: "nep", Name: "Nepali", Native: "नेपाली", RTL: false, CJK: false},
+	{Tag: "no", ISO6391: "no", ISO6392B: "nor", ISO6392T: "nor", ISO6393: "nor", Name: "Norwegian", Native: "Norsk", RTL: false, CJK: false},
+	{Tag: "nb", ISO6391: "nb", ISO6392B: "nob", ISO6392T: "nob", ISO6393: "nob", Name: "Norwegian Bokmål", Native: "Norsk bokmål", RTL: false, CJK: false, Aliases: []string{"Bokmal"}},
+	{Tag: "nn", ISO6391: "nn", ISO6392B: "nno", ISO6392T: "nno", ISO6393: "nno", Name: "Norwegian Nynorsk", Native: "Norsk nynorsk", RTL: false, CJK: false, Aliases: []string{"Nynorsk"}},
+	{Tag: "or", ISO6391: "or", ISO6392B: "ori", ISO6392T: "ori", ISO6393: "ori", Name: "Odia", Native: "ଓଡ଼ିଆ", RTL: false, CJK: false, Aliases: []string{"Oriya"}},
+	{Tag: "ps", ISO6391: "ps", ISO6392B: "pus", ISO6392T: "pus", ISO6393: "pus", Name: "Pashto", Native: "پښتو", RTL: true, CJK: false, Aliases: []string{"Pushto"}},
+	{Tag: "fa", ISO6391: "fa", ISO6392B: "per", ISO6392T: "fas", ISO6393: "fas", Name: "Persian", Native: "فارسی", RTL: true, CJK: false, Aliases: []string{"Farsi"}},
+	{Tag: "pl", ISO6391: "pl", ISO6392B: "pol", ISO6392T: "pol", ISO6393: "pol", Name: "Polish", Native: "Polski", RTL: false, CJK: false},
+	{Tag: "pt", ISO6391: "pt", ISO6392B: "por", ISO6392T: "por", ISO6393: "por", Name: "Portuguese", Native: "Português", RTL: false, CJK: false, Aliases: []string{"Portugues"}},
+	{Tag: "pt-BR", ISO6391: "pt", ISO6392B: "por", ISO6392T: "por", ISO6393: "por", Name: "Brazilian Portuguese", Native: "Português do Brasil", RTL: false, CJK: false},
+	{Tag: "pt-PT", ISO6391: "pt", ISO6392B: "por", ISO6392T: "por", ISO6393: "por", Name: "European Portuguese", Native: "Português europeu", RTL: false, CJK: false},
+	{Tag: "pa", ISO6391: "pa", ISO6392B: "pan", ISO6392T: "pan", ISO6393: "pan", Name: "Punjabi", Native: "ਪੰਜਾਬੀ", RTL: false, CJK: false, Aliases: []string{"Panjabi"}},
+	{Tag: "qu", ISO6391: "qu", ISO6392B: "que", ISO6392T: "que", ISO6393: "que", Name: "Quechua", Native: "Runa Simi", RTL: false, CJK: false},
+	{Tag: "ro", ISO6391: "ro", ISO6392B: "rum", ISO6392T: "ron", ISO6393: "ron", Name: "Romanian", Native: "Română", RTL: false, CJK: false, Aliases: []string{"Moldavian", "mo"}},
+	{Tag: "ru", ISO6391: "ru", ISO6392B: "rus", ISO6392T: "rus", ISO6393: "rus", Name: "Russian", Native: "Русский", RTL: false, CJK: false},
+	{Tag: "sm", ISO6391: "sm", ISO6392B: "smo", ISO6392T: "smo", ISO6393: "smo", Name: "Samoan", Native: "Gagana Samoa", RTL: false, CJK: false},
+	{Tag: "sr", ISO6391: "sr", ISO6392B: "srp", ISO6392T: "srp", ISO6393: "srp", Name: "Serbian", Native: "Српски", RTL: false, CJK: false},
+	{Tag: "sd", ISO6391: "sd", ISO6392B: "snd", ISO6392T: "snd", ISO6393: "snd", Name: "Sindhi", Native: "سنڌي", RTL: true, CJK: false},
+	{Tag: "si", ISO6391: "si", ISO6392B: "sin", ISO6392T: "sin", ISO6393: "sin", Name: "Sinhala", Native: "සිංහල", RTL: false, CJK: false, Aliases: []string{"Sinhalese"}},
+	{Tag: "sk", ISO6391: "sk", ISO6392B: "slo", ISO6392T: "slk", ISO6393: "slk", Name: "Slovak", Native: "Slovenčina", RTL: false, CJK: false},
+	{Tag: "sl", ISO6391: "sl", ISO6392B: "slv", ISO6392T: "slv", ISO6393: "slv", Name: "Slovenian", Native: "Slovenščina", RTL: false, CJK: false, Aliases: []string{"Slovene"}},
+	{Tag: "so", ISO6391: "so", ISO6392B: "som", ISO6392T: "som", ISO6393: "som", Name: "Somali", Native: "Soomaali", RTL: false, CJK: false},
+	{Tag: "es", ISO6391: "es", ISO6392B: "spa", ISO6392T: "spa", ISO6393: "spa", Name: "Spanish", Native: "Español", RTL: false, CJK: false, Aliases: []string{"Espanol"}},
+	{Tag: "es-ES", ISO6391: "es", ISO6392B: "spa", ISO6392T: "spa", ISO6393: "spa", Name: "Castilian Spanish", Native: "Español de España", RTL: false, CJK: false, Aliases: []string{"European Spanish", "Spain Spanish"}},
+	{Tag: "es-419", ISO6391: "es", ISO6392B: "spa", ISO6392T: "spa", ISO6393: "spa", Name: "Latin American Spanish", Native: "Español latinoamericano", RTL: false, CJK: false, Aliases: []string{"Latin Spanish"}},
+	{Tag: "es-MX", ISO6391: "es", ISO6392B: "spa", ISO6392T: "spa", ISO6393: "spa", Name: "Mexican Spanish", Native: "Español de México", RTL: false, CJK: false},
+	{Tag: "es-AR", ISO6391: "es", ISO6392B: "spa", ISO6392T: "spa", ISO6393: "spa", Name: "Argentinian Spanish", Native: "Español rioplatense", RTL: false, CJK: false, Aliases: []string{"Argentine Spanish"}},
+	{Tag: "su", ISO6391: "su", ISO6392B: "sun", ISO6392T: "sun", ISO6393: "sun", Name: "Sundanese", Native: "Basa Sunda", RTL: false, CJK: false},
+	{Tag: "sw", ISO6391: "sw", ISO6392B: "swa", ISO6392T: "swa", ISO6393: "swa", Name: "Swahili", Native: "Kiswahili", RTL: false, CJK: false},
+	{Tag: "sv", ISO6391: "sv", ISO6392B: "swe", ISO6392T: "swe", ISO6393: "swe", Name: "Swedish", Native: "Svenska", RTL: false, CJK: false},
+	{Tag: "tl", ISO6391: "tl", ISO6392B: "tgl", ISO6392T: "tgl", ISO6393: "tgl", Name: "Tagalog", Native: "Tagalog", RTL: false, CJK: false},
+	{Tag: "tg", ISO6391: "tg", ISO6392B: "tgk", ISO6392T: "tgk", ISO6393: "tgk", Name: "Tajik", Native: "Тоҷикӣ", RTL: false, CJK: false},
+	{Tag: "ta", ISO6391: "ta", ISO6392B: "tam", ISO6392T: "tam", ISO6393: "tam", Name: "Tamil", Native: "தமிழ்", RTL: false, CJK: false},
+	{Tag: "te", ISO6391: "te", ISO6392B: "tel", ISO6392T: "tel", ISO6393: "tel", Name: "Telugu", Native: "తెలుగు", RTL: false, CJK: false},
+	{Tag: "th", ISO6391: "th", ISO6392B: "tha", ISO6392T: "tha", ISO6393: "tha", Name: "Thai", Native: "ไทย", RTL: false, CJK: false},
+	{Tag: "bo", ISO6391: "bo", ISO6392B: "tib", ISO6392T: "bod", ISO6393: "bod", Name: "Tibetan", Native: "བོད་ཡིག", RTL: false, CJK: false},
+	{Tag: "ti", ISO6391: "ti", ISO6392B: "tir", ISO6392T: "tir", ISO6393: "tir", Name: "Tigrinya", Native: "ትግርኛ", RTL: false, CJK: false},
+	{Tag: "to", ISO6391: "to", ISO6392B: "ton", ISO6392T: "ton", ISO6393: "ton", Name: "Tongan", Native: "Lea faka-Tonga", RTL: false, CJK: false},
+	{Tag: "tr", ISO6391: "tr", ISO6392B: "tur", ISO6392T: "tur", ISO6393: "tur", Name: "Turkish", Native: "Türkçe", RTL: false, CJK: false},
+	{Tag: "uk", ISO6391: "uk", ISO6392B: "ukr", ISO6392T: "ukr", ISO6393: "ukr", Name: "Ukrainian", Native: "Українська", RTL: false, CJK: false},
+	{Tag: "ur", ISO6391: "ur", ISO6392B: "urd", ISO6392T: "urd", ISO6393: "urd", Name: "Urdu", Native: "اردو", RTL: true, CJK: false},
+	{Tag: "ug", ISO6391: "ug", ISO6392B: "uig", ISO6392T: "uig", ISO6393: "uig", Name: "Uyghur", Native: "ئۇيغۇرچە", RTL: true, CJK: false, Aliases: []string{"Uighur"}},
+	{Tag: "uz", ISO6391: "uz", ISO6392B: "uzb", ISO6392T: "uzb", ISO6393: "uzb", Name: "Uzbek", Native: "Oʻzbek", RTL: false, CJK: false},
+	{Tag: "vi", ISO6391: "vi", ISO6392B: "vie", ISO6392T: "vie", ISO6393: "vie", Name: "Vietnamese", Native: "Tiếng Việt", RTL: false, CJK: false},
+	{Tag: "cy", ISO6391: "cy", ISO6392B: "wel", ISO6392T: "cym", ISO6393: "cym", Name: "Welsh", Native: "Cymraeg", RTL: false, CJK: false},
+	{Tag: "xh", ISO6391: "xh", ISO6392B: "xho", ISO6392T: "xho", ISO6393: "xho", Name: "Xhosa", Native: "isiXhosa", RTL: false, CJK: false},
+	{Tag: "yi", ISO6391: "yi", ISO6392B: "yid", ISO6392T: "yid", ISO6393: "yid", Name: "Yiddish", Native: "ייִדיש", RTL: true, CJK: false},
+	{Tag: "yo", ISO6391: "yo", ISO6392B: "yor", ISO6392T: "yor", ISO6393: "yor", Name: "Yoruba", Native: "Yorùbá", RTL: false, CJK: false},
+	{Tag: "zu", ISO6391: "zu", ISO6392B: "zul", ISO6392T: "zul", ISO6393: "zul", Name: "Zulu", Native: "isiZulu", RTL: false, CJK: false},
+}
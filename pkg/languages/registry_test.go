@@ -0,0 +1,82 @@
+package languages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"English", "en"},
+		{"eng", "en"},
+		{"EN", "en"},
+		{"fre", "fr"},
+		{"fra", "fr"},
+		{"Français", "fr"},
+		{"fr-BE", "fr"},
+		{"pt_br", "pt-BR"},
+		{"Brazilian Portuguese", "pt-BR"},
+		{"por", "pt"},
+		{"es-419", "es-419"},
+		{"Simplified Chinese", "zh-Hans"},
+		{"chs", "zh-Hans"},
+		{"zh-CN", "zh-Hans"},
+		{"zh-Hant-TW", "zh-Hant"},
+		{"chi", "zh"},
+		{"日本語", "ja"},
+		{"  simplified   chinese ", "zh-Hans"},
+		{"portugese", "pt"},
+		{"check", "cs"},
+		{"Hungarain", "hu"},
+	}
+
+	for _, tt := range tests {
+		got, ok := Lookup(tt.query)
+		if !ok || got.Tag != tt.want {
+			t.Errorf("Lookup(%q) = %q, %v, want %q", tt.query, got.Tag, ok, tt.want)
+		}
+	}
+
+	for _, query := range []string{"", "und", "xyz", "Klingon"} {
+		if got, ok := Lookup(query); ok {
+			t.Errorf("Lookup(%q) = %q, want no match", query, got.Tag)
+		}
+	}
+}
+
+func TestLanguageProperties(t *testing.T) {
+	arabic, _ := Lookup("Arabic")
+	if !arabic.RTL || arabic.CJK || arabic.MatroskaCode() != "ara" {
+		t.Errorf("Unexpected Arabic entry: %+v", arabic)
+	}
+	traditional, _ := Lookup("Traditional Chinese")
+	if !traditional.CJK || traditional.RTL || traditional.Base() != "zh" || traditional.MatroskaCode() != "chi" {
+		t.Errorf("Unexpected Traditional Chinese entry: %+v", traditional)
+	}
+	montenegrin, _ := Lookup("Montenegrin")
+	if montenegrin.MatroskaCode() != "cnr" {
+		t.Errorf("MatroskaCode() = %q, want cnr", montenegrin.MatroskaCode())
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	tags := map[string]bool{}
+	for _, language := range All() {
+		if tags[strings.ToLower(language.Tag)] {
+			t.Errorf("Duplicate tag %s", language.Tag)
+		}
+		tags[strings.ToLower(language.Tag)] = true
+		if language.Name == "" || language.Native == "" {
+			t.Errorf("Missing name for %s", language.Tag)
+		}
+		if got, ok := Lookup(language.Tag); !ok || got.Tag != language.Tag {
+			t.Errorf("Lookup(%q) = %q, want the same entry", language.Tag, got.Tag)
+		}
+		if got, ok := Lookup(language.Name); !ok || got.Tag != language.Tag {
+			t.Errorf("Lookup(%q) = %q, want %s", language.Name, got.Tag, language.Tag)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package srt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	textunicode "golang.org/x/text/encoding/unicode"
+)
+
+// Byte order marks of the Unicode encodings
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// legacyCharset is a non-Unicode encoding that DetectCharset can recognize
+// by the characters its decoding produces
+type legacyCharset struct {
+	name     string
+	encoding encoding.Encoding
+	common   string // The most frequent non-ASCII characters of its languages
+	latin    bool   // Its languages mix a few accented letters into ASCII words
+}
+
+// Candidates in order of preference when they score the same. The letter lists
+// are short on purpose: the single-byte charsets map the same bytes to
+// letters of different alphabets, and only the frequent ones tell them apart.
+var legacyCharsets = []legacyCharset{
+	{"Windows-1252", charmap.Windows1252, "éèàçêâôîûëïüöäßñáíóúãõœ", true},
+	{"Windows-1250", charmap.Windows1250, "ąćęłńóśźżčěřšžťďňůáéíýúőűăâîșțşţľĺŕô", true},
+	{"Windows-1251", charmap.Windows1251, "оеаинтсрвлкмдпяіїє", false},
+	{"Windows-1253", charmap.Windows1253, "αοιετσνηυρπκμλάέίόύήώς", false},
+	{"Windows-1255", charmap.Windows1255, "יוהאלתמבשרנעק", false},
+	{"Windows-1256", charmap.Windows1256, "اليمونهرتبعدةأ", false},
+	{"GB18030", simplifiedchinese.GB18030, commonSimplified, false},
+	{"Big5", traditionalchinese.Big5, commonTraditional, false},
+	{"Shift_JIS", japanese.ShiftJIS, commonJapanese, false},
+	{"EUC-KR", korean.EUCKR, commonKorean, false},
+}
+
+// Frequent characters of Chinese, Japanese and Korean dialogue and punctuation
+const (
+	commonSimplified  = "的一是不了人我在有他这个们中来上大为和国地到以说时要就出会可也你对生能而子那得于着下自之年过发后作里用道行所然家种事成方多经么去法学如都同现当没动面起看定天分还进好小部其些主样理心她本前开但因只从想实吗呢吧啊哪儿等很情做，。！？、：“”…"
+	commonTraditional = "的一是不了人我在有他這個們中來上大為和國地到以說時要就出會可也你對生能而子那得於著下自之年過發後作裡用道行所然家種事成方多經麼去法學如都同現當沒動面起看定天分還進好小部其些主樣理心她本前開但因只從想實嗎呢吧啊哪兒等很情做，。！？、：「」…"
+	commonJapanese    = "のにはをたがでてとしれさいかなるもうっすこらあよだまんりくおけえせきそつねめわやろゆみひちほへふむーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワヲンッャュョ人日本私何今見行来言思、。！？「」…"
+	commonKorean      = "이다는의에가하고를을지서한로도기사은리나아수어그대자있게요해으내니것주시거보제없우라네마여면일어만들정했"
+)
+
+// ResolveCharset checks a charset name given by the user, such as "gbk",
+// "big5", "shift_jis", "cp1251", "windows-1256" or "utf-16le"; empty and
+// "auto" mean detection
+func ResolveCharset(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "auto") {
+		return "", nil
+	}
+	if _, err := lookupCharset(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// lookupCharset finds the encoding of a charset name
+func lookupCharset(name string) (encoding.Encoding, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	switch normalized {
+	case "utf-8", "utf8":
+		return textunicode.UTF8, nil
+	case "utf-16le", "utf16le":
+		return textunicode.UTF16(textunicode.LittleEndian, textunicode.IgnoreBOM), nil
+	case "utf-16be", "utf16be":
+		return textunicode.UTF16(textunicode.BigEndian, textunicode.IgnoreBOM), nil
+	case "utf-16", "utf16":
+		return textunicode.UTF16(textunicode.LittleEndian, textunicode.UseBOM), nil
+	}
+	if strings.HasPrefix(normalized, "cp") && len(normalized) == 6 {
+		normalized = "windows-" + normalized[2:]
+	}
+	if enc, err := htmlindex.Get(normalized); err == nil {
+		return enc, nil
+	}
+	if enc, err := ianaindex.IANA.Encoding(normalized); err == nil && enc != nil {
+		return enc, nil
+	}
+	return nil, fmt.Errorf("unknown character encoding %q", name)
+}
+
+// DetectCharset guesses the encoding of subtitle file contents: a byte order
+// mark, UTF-16 without one, valid UTF-8, and otherwise the legacy charset
+// whose decoding yields the most common characters of its languages
+func DetectCharset(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return "UTF-8"
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return "UTF-16LE"
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return "UTF-16BE"
+	}
+	if name := sniffUTF16(data); name != "" {
+		return name
+	}
+	if utf8.Valid(data) {
+		return "UTF-8"
+	}
+
+	sample := data[:min(len(data), 64<<10)]
+	best, bestScore := legacyCharsets[0].name, -1.0
+	for _, candidate := range legacyCharsets {
+		decoded, err := candidate.encoding.NewDecoder().Bytes(sample)
+		if err != nil {
+			continue
+		}
+		if score := charsetScore(string(decoded), candidate); score > bestScore {
+			best, bestScore = candidate.name, score
+		}
+	}
+	return best
+}
+
+// charsetScore rates a decoding by the share of its non-ASCII characters that
+// are common in the charset's languages; replacement and control characters
+// count against it. Latin text with more than a third of its letters outside
+// ASCII is another alphabet decoded with the wrong charset, and text of
+// another alphabet with fewer is Latin text.
+func charsetScore(text string, charset legacyCharset) float64 {
+	good, total := 0.0, 0
+	letters, foreign := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+		if r < utf8.RuneSelf {
+			continue
+		}
+		total++
+		if unicode.IsLetter(r) {
+			foreign++
+		}
+		switch {
+		case r == utf8.RuneError || unicode.IsControl(r) || r >= 0xE000 && r <= 0xF8FF:
+			good -= 4
+		case strings.ContainsRune(charset.common, unicode.ToLower(r)):
+			good++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	score := good / float64(total)
+	if charset.latin != (foreign*3 <= letters) {
+		score -= 1
+	}
+	return score
+}
+
+// sniffUTF16 recognizes UTF-16 without a byte order mark by the zero high
+// bytes of ASCII characters, which SRT timestamps are full of, and returns
+// UTF-16LE, UTF-16BE or nothing
+func sniffUTF16(data []byte) string {
+	sample := data[:min(len(data), 512)&^1]
+	if len(sample) < 8 {
+		return ""
+	}
+	even, odd := 0, 0
+	for i := 0; i < len(sample); i += 2 {
+		if sample[i] == 0 {
+			even++
+		}
+		if sample[i+1] == 0 {
+			odd++
+		}
+	}
+	pairs := len(sample) / 2
+	switch {
+	case odd*10 >= pairs*4 && even*10 < pairs:
+		return "UTF-16LE"
+	case even*10 >= pairs*4 && odd*10 < pairs:
+		return "UTF-16BE"
+	}
+	return ""
+}
+
+// HasBOM reports whether data starts with a UTF-8 or UTF-16 byte order mark
+func HasBOM(data []byte) bool {
+	return bytes.HasPrefix(data, bomUTF8) || bytes.HasPrefix(data, bomUTF16LE) || bytes.HasPrefix(data, bomUTF16BE)
+}
+
+// DecodeText converts file contents in the given charset, or in the detected
+// one when charset is empty or "auto", to a string without a byte order mark.
+// It returns the charset that was used.
+func DecodeText(data []byte, charset string) (string, string, error) {
+	charset, err := ResolveCharset(charset)
+	if err != nil {
+		return "", "", err
+	}
+	if charset == "" {
+		charset = DetectCharset(data)
+	}
+	enc, err := lookupCharset(charset)
+	if err != nil {
+		return "", "", err
+	}
+
+	// A byte order mark overrides the charset's own handling of it
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		data = data[len(bomUTF8):]
+	case bytes.HasPrefix(data, bomUTF16LE) && strings.HasPrefix(strings.ToUpper(charset), "UTF-16"):
+		data, enc = data[2:], textunicode.UTF16(textunicode.LittleEndian, textunicode.IgnoreBOM)
+	case bytes.HasPrefix(data, bomUTF16BE) && strings.HasPrefix(strings.ToUpper(charset), "UTF-16"):
+		data, enc = data[2:], textunicode.UTF16(textunicode.BigEndian, textunicode.IgnoreBOM)
+	}
+	if enc == textunicode.UTF8 {
+		return strings.ToValidUTF8(string(data), "\ufffd"), charset, nil
+	}
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode %s text: %w", charset, err)
+	}
+	return strings.TrimPrefix(string(decoded), "\ufeff"), charset, nil
+}
+
+// EncodeText converts text to the given charset (UTF-8 when empty), with a
+// byte order mark when bom is set. It fails when the charset cannot represent
+// a character of the text, or has no byte order mark.
+func EncodeText(text string, charset string, bom bool) ([]byte, error) {
+	if strings.TrimSpace(charset) == "" || strings.EqualFold(strings.TrimSpace(charset), "auto") {
+		charset = "UTF-8"
+	}
+	enc, err := lookupCharset(charset)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefix []byte
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "utf-8", "utf8":
+		if bom {
+			prefix = bomUTF8
+		}
+		return append(prefix, text...), nil
+	case "utf-16le", "utf16le", "utf-16", "utf16":
+		enc = textunicode.UTF16(textunicode.LittleEndian, textunicode.IgnoreBOM)
+		if bom {
+			prefix = bomUTF16LE
+		}
+	case "utf-16be", "utf16be":
+		enc = textunicode.UTF16(textunicode.BigEndian, textunicode.IgnoreBOM)
+		if bom {
+			prefix = bomUTF16BE
+		}
+	default:
+		if bom {
+			return nil, fmt.Errorf("%s has no byte order mark", charset)
+		}
+	}
+
+	encoded, err := enc.NewEncoder().Bytes([]byte(text))
+	if err != nil {
+		return nil, fmt.Errorf("the text contains characters that %s cannot represent: %w", charset, err)
+	}
+	return append(prefix, encoded...), nil
+}
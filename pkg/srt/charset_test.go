@@ -0,0 +1,65 @@
+package srt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDetectCharset(t *testing.T) {
+	samples := []struct {
+		charset string
+		text    string
+	}{
+		{"Windows-1252", "Où étais-tu hier soir ? Je t'ai attendu des heures, ça ne se fait pas.\nJe suis désolé, c'était très compliqué au bureau."},
+		{"Windows-1250", "Gdzie byłeś wczoraj wieczorem? Czekałam na ciebie całą noc.\nPrzepraszam, to było bardzo trudne. Muszę już iść."},
+		{"Windows-1251", "Где ты был вчера вечером? Я ждала тебя несколько часов.\nПрости, на работе было очень много дел."},
+		{"Windows-1253", "Πού ήσουν χθες το βράδυ; Σε περίμενα για ώρες.\nΣυγγνώμη, είχα πολλή δουλειά στο γραφείο."},
+		{"Windows-1255", "איפה היית אתמול בלילה? חיכיתי לך שעות.\nסליחה, היה לי הרבה עבודה במשרד."},
+		{"Windows-1256", "أين كنت الليلة الماضية؟ انتظرتك لساعات طويلة.\nآسف، كان لدي الكثير من العمل في المكتب."},
+		{"GB18030", "你昨天晚上去哪儿了？我等了你好几个小时。\n对不起，我在公司加班，有很多事情要做。"},
+		{"Big5", "你昨天晚上去哪裡了？我等了你好幾個小時。\n對不起，我在公司加班，有很多事情要做。"},
+		{"Shift_JIS", "昨日の夜はどこにいたの？何時間も待っていたのよ。\nごめん、会社でずっと仕事をしていたんだ。"},
+		{"EUC-KR", "어젯밤에 어디 있었어? 몇 시간이나 기다렸잖아.\n미안해, 회사에서 일이 너무 많았어."},
+	}
+
+	for _, sample := range samples {
+		t.Run(sample.charset, func(t *testing.T) {
+			content := "1\n00:00:01,000 --> 00:00:04,000\n" + sample.text + "\n"
+			data, err := EncodeText(content, sample.charset, false)
+			if err != nil {
+				t.Fatalf("EncodeText() failed: %v", err)
+			}
+			if got := DetectCharset(data); got != sample.charset {
+				t.Errorf("DetectCharset() = %s, want %s", got, sample.charset)
+			}
+			text, _, err := DecodeText(data, "")
+			if err != nil || text != content {
+				t.Errorf("DecodeText() = %q, %v", text, err)
+			}
+		})
+	}
+}
+
+func TestEncodeText(t *testing.T) {
+	data, err := EncodeText("Hi", "utf-8", true)
+	if err != nil || !bytes.Equal(data, []byte("\xef\xbb\xbfHi")) {
+		t.Errorf("EncodeText(UTF-8 with BOM) = %q, %v", data, err)
+	}
+	data, err = EncodeText("Hi", "utf-16le", true)
+	if err != nil || !bytes.Equal(data, []byte("\xff\xfeH\x00i\x00")) {
+		t.Errorf("EncodeText(UTF-16LE with BOM) = %q, %v", data, err)
+	}
+	data, err = EncodeText("Привет", "cp1251", false)
+	if err != nil || !bytes.Equal(data, []byte("\xcf\xf0\xe8\xe2\xe5\xf2")) {
+		t.Errorf("EncodeText(cp1251) = %q, %v", data, err)
+	}
+	if _, err = EncodeText("你好", "windows-1252", false); err == nil {
+		t.Error("EncodeText() encoded Chinese as Windows-1252")
+	}
+	if _, err = EncodeText("Hi", "gbk", true); err == nil {
+		t.Error("EncodeText() wrote a byte order mark for GBK")
+	}
+	if _, err = ResolveCharset("klingon-8"); err == nil {
+		t.Error("ResolveCharset() accepted an unknown charset")
+	}
+}
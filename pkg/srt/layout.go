@@ -0,0 +1,137 @@
+package srt
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Layout is how a subtitle file was written: its charset, byte order mark,
+// line endings and the exact lines of every cue. Compose writes subtitles
+// back in it, so a file whose cues did not change comes out byte for byte
+// as it was read.
+type Layout struct {
+	Charset    string // Charset the file was decoded from
+	BOM        bool   // The file starts with a byte order mark
+	LineEnding string // Most common line ending, used for the lines Compose rewrites
+
+	lines     []string   // Lines of the file with their terminators
+	subtitles []Subtitle // Cues as parsed
+	spans     []cueSpan  // Where each parsed cue is in lines
+}
+
+// ParseLayout is ParseCharset that also records the layout of the file
+func ParseLayout(data []byte, charset string) ([]Subtitle, *Layout, []Diagnostic, error) {
+	charset, err := ResolveCharset(charset)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	text, used, err := DecodeText(data, charset)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	lines := splitLines(strings.TrimPrefix(text, "\ufeff"))
+	subtitles, spans, diagnostics := parseLines(lines)
+	if charset == "" && used != "UTF-8" {
+		diagnostics = append(diagnostics, Diagnostic{Severity: SeverityWarning, Message: fmt.Sprintf("file is %s encoded, not UTF-8", used)})
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool { return diagnostics[i].Line < diagnostics[j].Line })
+
+	layout := &Layout{
+		Charset:    used,
+		BOM:        HasBOM(data),
+		LineEnding: lineEnding(lines),
+		lines:      lines,
+		subtitles:  append([]Subtitle(nil), subtitles...),
+		spans:      spans,
+	}
+	return subtitles, layout, diagnostics, nil
+}
+
+// Compose writes subtitles in the layout, matching them to the parsed cues
+// by position. The number, timing and text of a cue are written as they were
+// read while they keep their parsed value, and in standard form once changed;
+// text between cues, such as dropped cues and extra blank lines, is kept.
+// Subtitles past the parsed cues are appended in standard form.
+func (l *Layout) Compose(subtitles []Subtitle) string {
+	var b strings.Builder
+	next := 0 // First line not written yet
+	for i, sub := range subtitles[:min(len(subtitles), len(l.spans))] {
+		parsed, span := l.subtitles[i], l.spans[i]
+		start := span.timing
+		if span.index >= 0 {
+			start = span.index
+		}
+		b.WriteString(strings.Join(l.lines[next:start], ""))
+
+		switch {
+		case sub.Index != parsed.Index:
+			b.WriteString(strconv.Itoa(sub.Index) + l.terminator(start))
+		case span.index >= 0:
+			b.WriteString(l.lines[span.index])
+		}
+
+		timing := l.lines[span.timing]
+		if sub.Start != parsed.Start || sub.End != parsed.End {
+			// Position coordinates after the timestamps are kept
+			coordinates := timingLine.FindStringSubmatch(strings.TrimRight(timing, "\r\n"))[4]
+			timing = formatDuration(sub.Start) + " --> " + formatDuration(sub.End) + coordinates + l.terminator(span.timing)
+		}
+		b.WriteString(timing)
+
+		if sub.Content == parsed.Content {
+			b.WriteString(strings.Join(l.lines[span.timing+1:span.end], ""))
+		} else {
+			content := strings.ReplaceAll(strings.ReplaceAll(sub.Content, "\r\n", "\n"), "\n", l.LineEnding)
+			b.WriteString(content + l.terminator(span.end-1))
+		}
+		next = span.end
+	}
+
+	// Appended cues go after the last cue, before the lines that end the file
+	tail := 0
+	if len(l.spans) > 0 {
+		tail = max(next, l.spans[len(l.spans)-1].end)
+	}
+	b.WriteString(strings.Join(l.lines[next:tail], ""))
+	for _, sub := range subtitles[min(len(subtitles), len(l.spans)):] {
+		if text := b.String(); text != "" {
+			if !strings.HasSuffix(text, "\n") && !strings.HasSuffix(text, "\r") {
+				b.WriteString(l.LineEnding)
+			}
+			b.WriteString(l.LineEnding)
+		}
+		b.WriteString(strconv.Itoa(sub.Index) + l.LineEnding)
+		b.WriteString(formatDuration(sub.Start) + " --> " + formatDuration(sub.End) + l.LineEnding)
+		b.WriteString(strings.ReplaceAll(sub.Content, "\n", l.LineEnding) + l.LineEnding)
+	}
+	b.WriteString(strings.Join(l.lines[tail:], ""))
+	return b.String()
+}
+
+// terminator returns the line ending of a line, which is empty for the last
+// line of a file without a final newline
+func (l *Layout) terminator(line int) string {
+	text := l.lines[line]
+	return text[len(strings.TrimRight(text, "\r\n")):]
+}
+
+// lineEnding returns the most common line ending of the lines, "\n" when
+// they have none
+func lineEnding(lines []string) string {
+	counts := make(map[string]int)
+	for _, line := range lines {
+		if ending := line[len(strings.TrimRight(line, "\r\n")):]; ending != "" {
+			counts[ending]++
+		}
+	}
+	best := "\n"
+	for _, ending := range []string{"\r\n", "\r"} {
+		if counts[ending] > counts[best] {
+			best = ending
+		}
+	}
+	return best
+}
@@ -0,0 +1,122 @@
+package srt
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the round-trip tests")
+
+// roundTripFiles returns the files of the round-trip corpus
+func roundTripFiles(t *testing.T) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join("testdata", "roundtrip", "*.srt"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no round-trip test files: %v", err)
+	}
+	return files
+}
+
+// composeFile writes subtitles in a layout and encodes them as the file was
+func composeFile(t *testing.T, layout *Layout, subtitles []Subtitle) []byte {
+	t.Helper()
+	data, err := EncodeText(layout.Compose(subtitles), layout.Charset, layout.BOM)
+	if err != nil {
+		t.Fatalf("EncodeText() error = %v", err)
+	}
+	return data
+}
+
+func TestLayout_roundTrip(t *testing.T) {
+	for _, file := range roundTripFiles(t) {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			subtitles, layout, _, err := ParseLayout(data, "")
+			if err != nil {
+				t.Fatalf("ParseLayout() error = %v", err)
+			}
+			if len(subtitles) == 0 {
+				t.Fatal("ParseLayout() found no cues")
+			}
+			if got := composeFile(t, layout, subtitles); !bytes.Equal(got, data) {
+				t.Errorf("round trip changed the file:\ngot  %q\nwant %q", got, data)
+			}
+		})
+	}
+}
+
+// TestLayout_edits checks the lines rewritten for changed cues against the
+// .golden files; run with -update to rewrite them
+func TestLayout_edits(t *testing.T) {
+	for _, file := range roundTripFiles(t) {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			subtitles, layout, _, err := ParseLayout(data, "")
+			if err != nil {
+				t.Fatalf("ParseLayout() error = %v", err)
+			}
+
+			// Retime the first cue, translate the second and renumber the last
+			subtitles[0].Start += time.Second
+			if len(subtitles) > 1 {
+				subtitles[1].Content = strings.ToUpper(subtitles[1].Content) + "\nADDED LINE"
+			}
+			subtitles[len(subtitles)-1].Index += 100
+			subtitles = append(subtitles, Subtitle{Index: 500, Start: time.Minute, End: time.Minute + time.Second, Content: "Appended"})
+
+			got := composeFile(t, layout, subtitles)
+			golden := strings.TrimSuffix(file, ".srt") + ".golden"
+			if *update {
+				if err = os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Compose() =\n%q\nwant\n%q", got, want)
+			}
+
+			// The edited file parses back to the edited cues
+			reparsed, _, _, err := ParseLayout(got, layout.Charset)
+			if err != nil {
+				t.Fatalf("ParseLayout() error = %v", err)
+			}
+			if len(reparsed) != len(subtitles) {
+				t.Fatalf("reparsed %d cues, want %d", len(reparsed), len(subtitles))
+			}
+			for i := range subtitles {
+				if reparsed[i] != subtitles[i] {
+					t.Errorf("cue %d = %+v, want %+v", i, reparsed[i], subtitles[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLayout_lineEnding(t *testing.T) {
+	tests := map[string]string{
+		"a\nb\n":        "\n",
+		"a\r\nb\r\nc\n": "\r\n",
+		"a\rb\r":        "\r",
+		"no newline":    "\n",
+	}
+	for text, want := range tests {
+		if got := lineEnding(splitLines(text)); got != want {
+			t.Errorf("lineEnding(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
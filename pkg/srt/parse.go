@@ -0,0 +1,339 @@
+package srt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Severity tells whether a parsing problem was repaired or lost content
+type Severity int
+
+const (
+	SeverityWarning Severity = iota // Repaired; no content was lost
+	SeverityError                   // The affected lines were dropped
+)
+
+// String names the severity
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Diagnostic is a problem found while parsing a subtitle file
+type Diagnostic struct {
+	Line     int // 1-based line number, 0 for the whole file
+	Severity Severity
+	Message  string
+}
+
+// String formats the diagnostic as "line 12: warning: message"
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", d.Line, d.Severity, d.Message)
+}
+
+// ParseError is returned by ParseStrict when a file has any problem
+type ParseError struct {
+	Diagnostics []Diagnostic
+}
+
+// Error lists the first problems of the file
+func (e *ParseError) Error() string {
+	const shown = 3
+	messages := make([]string, 0, shown)
+	for _, diagnostic := range e.Diagnostics[:min(shown, len(e.Diagnostics))] {
+		messages = append(messages, diagnostic.String())
+	}
+	text := fmt.Sprintf("%d problems in subtitle file: %s", len(e.Diagnostics), strings.Join(messages, "; "))
+	if len(e.Diagnostics) > shown {
+		text += "; ..."
+	}
+	return text
+}
+
+var (
+	// timingLine accepts the timestamp defects the parser repairs: "." or ":"
+	// before the milliseconds, missing milliseconds, one-digit fields and
+	// other arrows or spacing; position coordinates may follow
+	timingLine = regexp.MustCompile(`^\s*(\d+:\d{1,2}:\d{1,2}(?:[,.:]\d{1,3})?)\s*(-{1,2}>|—>|–>)\s*(\d+:\d{1,2}:\d{1,2}(?:[,.:]\d{1,3})?)(\s+.*)?$`)
+	// canonicalTimestamp is a timestamp written as the format requires
+	canonicalTimestamp = regexp.MustCompile(`^\d{2,}:\d{2}:\d{2},\d{3}$`)
+)
+
+// Parse reads subtitle file contents leniently: it decodes them from the
+// detected charset, repairs common defects and drops what it cannot read, and
+// reports both in diagnostics with line numbers
+func Parse(data []byte) ([]Subtitle, []Diagnostic) {
+	subtitles, diagnostics, _ := ParseCharset(data, "")
+	return subtitles, diagnostics
+}
+
+// ParseCharset is Parse for contents in the given charset; an empty charset
+// or "auto" detects it, and a detected charset other than UTF-8 is reported
+func ParseCharset(data []byte, charset string) ([]Subtitle, []Diagnostic, error) {
+	subtitles, _, diagnostics, err := ParseLayout(data, charset)
+	return subtitles, diagnostics, err
+}
+
+// ParseStrict reads subtitle file contents and fails with a *ParseError when
+// Parse reports any problem
+func ParseStrict(data []byte) ([]Subtitle, error) {
+	subtitles, diagnostics := Parse(data)
+	if len(diagnostics) > 0 {
+		return nil, &ParseError{Diagnostics: diagnostics}
+	}
+	return subtitles, nil
+}
+
+// lineKind classifies the lines of a subtitle file
+type lineKind int
+
+const (
+	textLine lineKind = iota
+	blankLine
+	timing    // A readable timing line
+	badTiming // A line with an arrow that is not a readable timing line
+)
+
+// cueSpan locates a parsed cue in the lines of its file
+type cueSpan struct {
+	index  int // Line of the cue number, -1 when the cue has none
+	timing int // Line of the timing
+	end    int // Line after the last text line
+}
+
+// parse parses decoded SRT text, see Parse
+func parse(content string) ([]Subtitle, []Diagnostic) {
+	subtitles, _, diagnostics := parseLines(splitLines(strings.TrimPrefix(content, "\ufeff")))
+	return subtitles, diagnostics
+}
+
+// splitLines splits text into lines that keep their "\r\n", "\n" or "\r"
+// terminator; only the last line may have none
+func splitLines(text string) []string {
+	var lines []string
+	for text != "" {
+		end := strings.IndexAny(text, "\r\n")
+		switch {
+		case end < 0:
+			end = len(text)
+		case strings.HasPrefix(text[end:], "\r\n"):
+			end += 2
+		default:
+			end++
+		}
+		lines = append(lines, text[:end])
+		text = text[end:]
+	}
+	return lines
+}
+
+// parseLines parses the lines of a file, see Parse, and locates every cue
+func parseLines(raw []string) ([]Subtitle, []cueSpan, []Diagnostic) {
+	var diagnostics []Diagnostic
+	report := func(line int, severity Severity, format string, args ...any) {
+		diagnostics = append(diagnostics, Diagnostic{Line: line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if strings.TrimSpace(strings.Join(raw, "")) == "" {
+		return nil, nil, nil
+	}
+	lines := make([]string, len(raw))
+	for i, line := range raw {
+		lines[i] = strings.TrimRight(line, "\r\n")
+	}
+	if lines[len(lines)-1] == raw[len(raw)-1] {
+		report(len(lines), SeverityWarning, "file does not end with a newline")
+	}
+
+	kinds := make([]lineKind, len(lines))
+	var markers []int
+	for i, line := range lines {
+		switch {
+		case strings.TrimSpace(line) == "":
+			kinds[i] = blankLine
+		case !timingCandidate(lines, kinds, i):
+			// Text such as "The arrow --> points here" stays text
+		case timingLine.MatchString(line):
+			kinds[i] = timing
+			markers = append(markers, i)
+		case strings.Contains(line, "-->"):
+			kinds[i] = badTiming
+			markers = append(markers, i)
+		}
+	}
+
+	// Each marker's cue begins at its index line, when it has one
+	starts := make([]int, len(markers))
+	indexes := make([]int, len(markers))
+	for m, i := range markers {
+		starts[m] = i
+		indexes[m] = -1
+		if i == 0 || kinds[i-1] != textLine {
+			continue
+		}
+		if index, err := strconv.Atoi(strings.TrimSpace(lines[i-1])); err == nil {
+			starts[m], indexes[m] = i-1, index
+		} else if i-1 == 0 || kinds[i-2] == blankLine {
+			// A line on its own before the timing is a broken index, not text;
+			// one with a number in it, such as "1.", is renumbered
+			starts[m], indexes[m] = i-1, -3
+			if strings.ContainsAny(lines[i-1], "0123456789") {
+				indexes[m] = -2
+			}
+		}
+	}
+
+	if len(markers) == 0 {
+		for i := range lines {
+			if kinds[i] != blankLine {
+				report(i+1, SeverityError, "no timing line found; the file is not SRT")
+				break
+			}
+		}
+		return nil, nil, diagnostics
+	}
+	for i := 0; i < starts[0]; i++ {
+		if kinds[i] != blankLine {
+			report(i+1, SeverityError, "text before the first cue was ignored")
+			break
+		}
+	}
+
+	var subtitles []Subtitle
+	var spans []cueSpan
+	expected := 1
+	for m, i := range markers {
+		end := len(lines)
+		if m+1 < len(markers) {
+			end = starts[m+1]
+		}
+
+		if kinds[i] == badTiming {
+			if indexes[m] >= 0 {
+				expected = indexes[m] + 1
+			}
+			report(i+1, SeverityError, "invalid timing line %q; the cue was dropped", strings.TrimSpace(lines[i]))
+			continue
+		}
+		if indexes[m] == -3 {
+			expected++
+			report(i, SeverityError, "invalid cue number %q; the cue was dropped", strings.TrimSpace(lines[i-1]))
+			continue
+		}
+
+		// Cue text, without the blank lines that separate it from the next cue
+		text := lines[i+1 : end]
+		for len(text) > 0 && strings.TrimSpace(text[len(text)-1]) == "" {
+			text = text[:len(text)-1]
+		}
+		kept := make([]string, 0, len(text))
+		for j, line := range text {
+			if strings.TrimSpace(line) == "" {
+				report(i+2+j, SeverityWarning, "blank line inside the text of a cue was removed")
+				continue
+			}
+			kept = append(kept, strings.TrimRight(line, " \t"))
+		}
+
+		index := indexes[m]
+		switch {
+		case index == -2:
+			index = expected
+			report(i, SeverityWarning, "invalid cue number %q; numbered %d", strings.TrimSpace(lines[i-1]), index)
+		case index < 0:
+			index = expected
+			report(i+1, SeverityWarning, "cue has no number; numbered %d", index)
+		case index != expected:
+			report(i, SeverityWarning, "cue number %d is out of sequence, expected %d", index, expected)
+		}
+		expected = index + 1
+
+		start, stop, problems := parseTimingLine(lines[i])
+		for _, problem := range problems {
+			report(i+1, SeverityWarning, "%s", problem)
+		}
+		if stop < start {
+			report(i+1, SeverityWarning, "cue %d ends before it starts", index)
+		}
+
+		if len(kept) == 0 {
+			report(i+1, SeverityWarning, "cue %d has no text and was dropped", index)
+			continue
+		}
+		subtitles = append(subtitles, Subtitle{
+			Index:   index,
+			Start:   start,
+			End:     stop,
+			Content: strings.TrimSpace(strings.Join(kept, "\n")),
+		})
+		span := cueSpan{index: -1, timing: i, end: i + 1 + len(text)}
+		if starts[m] < i {
+			span.index = starts[m]
+		}
+		spans = append(spans, span)
+	}
+	return subtitles, spans, diagnostics
+}
+
+// timingCandidate reports whether line i, whose predecessors are classified,
+// can be the timing of a cue: it starts with a digit and follows the start of
+// the file, a blank line or a line on its own that can be a cue number
+func timingCandidate(lines []string, kinds []lineKind, i int) bool {
+	line := strings.TrimLeft(lines[i], " \t")
+	if line == "" || line[0] < '0' || line[0] > '9' {
+		return false
+	}
+	if i == 0 || kinds[i-1] == blankLine {
+		return true
+	}
+	if kinds[i-1] != textLine {
+		return false
+	}
+	if _, err := strconv.Atoi(strings.TrimSpace(lines[i-1])); err == nil {
+		return true
+	}
+	return i-1 == 0 || kinds[i-2] == blankLine
+}
+
+// parseTimingLine reads a line matched by timingLine and describes how it
+// differs from the standard "00:00:01,000 --> 00:00:04,000"
+func parseTimingLine(line string) (time.Duration, time.Duration, []string) {
+	parts := timingLine.FindStringSubmatch(line)
+	var problems []string
+	if strings.ContainsAny(parts[1]+parts[3], ".") {
+		problems = append(problems, "timestamp uses '.' instead of ',' before the milliseconds")
+	}
+	if !canonicalTimestamp.MatchString(parts[1]) || !canonicalTimestamp.MatchString(parts[3]) {
+		if len(problems) == 0 {
+			problems = append(problems, "timestamp is not in the form 00:00:00,000")
+		}
+	}
+	arrow := strings.TrimSpace(parts[1]) + " --> " + strings.TrimSpace(parts[3])
+	if parts[2] != "-->" || !strings.HasPrefix(strings.TrimLeft(line, " \t"), arrow) {
+		problems = append(problems, "timing arrow is not written as \" --> \"")
+	}
+	return lenientDuration(parts[1]), lenientDuration(parts[3]), problems
+}
+
+// lenientDuration reads a timestamp matched by timingLine
+func lenientDuration(s string) time.Duration {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ':' || r == ',' || r == '.' })
+	hours, _ := strconv.Atoi(fields[0])
+	minutes, _ := strconv.Atoi(fields[1])
+	seconds, _ := strconv.Atoi(fields[2])
+	d := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	if len(fields) == 4 {
+		// Milliseconds are a fraction: ",5" is 500 ms
+		millis, _ := strconv.Atoi((fields[3] + "00")[:3])
+		d += time.Duration(millis) * time.Millisecond
+	}
+	return d
+}
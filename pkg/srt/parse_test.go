@@ -0,0 +1,135 @@
+package srt
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func TestParse_repairs(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		want     []Subtitle
+		messages []string // Expected diagnostics as "line: severity: message" prefixes
+	}{
+		{
+			name:    "clean",
+			content: "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n",
+			want:    []Subtitle{{1, time.Second, 2 * time.Second, "Hello"}, {2, 3 * time.Second, 4 * time.Second, "World"}},
+		},
+		{
+			name:     "missing index",
+			content:  "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n00:00:03,000 --> 00:00:04,000\nWorld\n",
+			want:     []Subtitle{{1, time.Second, 2 * time.Second, "Hello"}, {2, 3 * time.Second, 4 * time.Second, "World"}},
+			messages: []string{"line 5: warning: cue has no number"},
+		},
+		{
+			name:     "dot and arrow spacing",
+			content:  "1\n00:00:01.500-->00:00:02.250\nHello\n",
+			want:     []Subtitle{{1, 1500 * time.Millisecond, 2250 * time.Millisecond, "Hello"}},
+			messages: []string{"line 2: warning: timestamp uses '.'", "line 2: warning: timing arrow"},
+		},
+		{
+			name:     "blank line inside text",
+			content:  "1\n00:00:01,000 --> 00:00:02,000\nHello\n\nthere\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n",
+			want:     []Subtitle{{1, time.Second, 2 * time.Second, "Hello\nthere"}, {2, 3 * time.Second, 4 * time.Second, "World"}},
+			messages: []string{"line 4: warning: blank line inside the text"},
+		},
+		{
+			name:     "missing trailing newline and CRLF",
+			content:  "1\r\n00:00:01,000 --> 00:00:02,000\r\nHello",
+			want:     []Subtitle{{1, time.Second, 2 * time.Second, "Hello"}},
+			messages: []string{"line 3: warning: file does not end with a newline"},
+		},
+		{
+			name:     "invalid timing",
+			content:  "1\n00:00:01,000 --> soon\nLost\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n",
+			want:     []Subtitle{{2, 3 * time.Second, 4 * time.Second, "World"}},
+			messages: []string{"line 2: error: invalid timing line"},
+		},
+		{
+			name:     "cue number with punctuation",
+			content:  "1.\n00:00:01,000 --> 00:00:02,000\nHello\n\n2.\n00:00:03,000 --> 00:00:04,000\nWorld\n",
+			want:     []Subtitle{{1, time.Second, 2 * time.Second, "Hello"}, {2, 3 * time.Second, 4 * time.Second, "World"}},
+			messages: []string{"line 1: warning: invalid cue number \"1.\"; numbered 1", "line 5: warning: invalid cue number \"2.\"; numbered 2"},
+		},
+		{
+			name:    "arrow in the text",
+			content: "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nThe arrow --> points here\n10 --> 20 apples\n",
+			want:    []Subtitle{{1, time.Second, 2 * time.Second, "Hello"}, {2, 3 * time.Second, 4 * time.Second, "The arrow --> points here\n10 --> 20 apples"}},
+		},
+		{
+			name:     "unreadable cue number",
+			content:  "abc\n00:00:01,000 --> 00:00:02,000\nLost\n\n2\n00:00:03,000 --> 00:00:04,000\nWorld\n",
+			want:     []Subtitle{{2, 3 * time.Second, 4 * time.Second, "World"}},
+			messages: []string{"line 1: error: invalid cue number \"abc\"; the cue was dropped"},
+		},
+		{
+			name:     "out of sequence",
+			content:  "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n5\n00:00:03,000 --> 00:00:04,000\nWorld\n",
+			want:     []Subtitle{{1, time.Second, 2 * time.Second, "Hello"}, {5, 3 * time.Second, 4 * time.Second, "World"}},
+			messages: []string{"line 5: warning: cue number 5 is out of sequence, expected 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diagnostics := Parse([]byte(tt.content))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Parse() returned %d cues, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Cue %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+			if len(diagnostics) != len(tt.messages) {
+				t.Fatalf("Parse() diagnostics = %v, want %d", diagnostics, len(tt.messages))
+			}
+			for i, message := range tt.messages {
+				if !strings.HasPrefix(diagnostics[i].String(), message) {
+					t.Errorf("Diagnostic %d = %q, want prefix %q", i, diagnostics[i], message)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_encodings(t *testing.T) {
+	content := "1\r\n00:00:01,000 --> 00:00:02,000\r\nÇa va, café?\r\n"
+	units := utf16.Encode([]rune(content))
+
+	le := []byte{0xFF, 0xFE}
+	be := []byte{}
+	for _, unit := range units {
+		le = binary.LittleEndian.AppendUint16(le, unit)
+		be = binary.BigEndian.AppendUint16(be, unit)
+	}
+	windows := []byte(strings.NewReplacer("Ç", "\xc7", "é", "\xe9").Replace(content))
+
+	for name, data := range map[string][]byte{"UTF-16LE": le, "UTF-16BE": be, "Windows-1252": windows} {
+		subtitles, diagnostics := Parse(data)
+		if len(subtitles) != 1 || subtitles[0].Content != "Ça va, café?" {
+			t.Errorf("%s: Parse() = %+v", name, subtitles)
+		}
+		if len(diagnostics) != 1 || diagnostics[0].Line != 0 || !strings.Contains(diagnostics[0].Message, name) {
+			t.Errorf("%s: diagnostics = %v", name, diagnostics)
+		}
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	if _, err := ParseStrict([]byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n")); err != nil {
+		t.Errorf("ParseStrict() failed on a clean file: %v", err)
+	}
+
+	_, err := ParseStrict([]byte("1\n00:00:01.000 --> 00:00:02,000\nHello\n"))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || len(parseErr.Diagnostics) != 1 || parseErr.Diagnostics[0].Line != 2 {
+		t.Errorf("ParseStrict() error = %v, want one problem on line 2", err)
+	}
+}
@@ -0,0 +1,97 @@
+package srt
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SDHMergeGap is the largest gap between two cues left with the same text by
+// StripSDH that are merged into one
+const SDHMergeGap = 500 * time.Millisecond
+
+var (
+	// inlineSound is a sound description in brackets anywhere in a line
+	inlineSound = regexp.MustCompile(`[ \t]*\[[^\]]*\]`)
+	// inlineMusic is music between notes anywhere in a line
+	inlineMusic = regexp.MustCompile(`[ \t]*[♪♫]+[^♪♫]*[♪♫]+`)
+)
+
+// StripSDH removes the annotations for the hearing impaired from the cues:
+// sound descriptions, speaker labels in capitals and lines of music. Cues left
+// without text are dropped, a cue left with the text of the cue just before it
+// extends that cue, and the cues are renumbered from 1. It returns the cleaned
+// cues and the number of cues dropped or merged.
+func StripSDH(subtitles []Subtitle) ([]Subtitle, int) {
+	stripped := make([]Subtitle, 0, len(subtitles))
+	for _, subtitle := range subtitles {
+		subtitle.Content = stripSDHText(subtitle.Content)
+		if subtitle.Content == "" {
+			continue
+		}
+		if last := len(stripped) - 1; last >= 0 && stripped[last].Content == subtitle.Content && subtitle.Start >= stripped[last].Start && subtitle.Start-stripped[last].End <= SDHMergeGap {
+			stripped[last].End = max(stripped[last].End, subtitle.End)
+			continue
+		}
+		stripped = append(stripped, subtitle)
+	}
+	for i := range stripped {
+		stripped[i].Index = i + 1
+	}
+	return stripped, len(subtitles) - len(stripped)
+}
+
+// stripSDHText removes the annotations from the text of a cue, see StripSDH
+func stripSDHText(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = stripSDHLine(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	// A dialogue dash is dropped once its line is the only one left
+	if len(kept) == 1 && len(kept) < len(lines) {
+		if dash := dialogueDash.FindString(kept[0]); dash != "" && dash != kept[0] && !strings.HasPrefix(kept[0], "--") {
+			kept[0] = kept[0][len(dash):]
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// stripSDHLine removes the annotations from one line, returning "" when
+// nothing else is left
+func stripSDHLine(line string) string {
+	line = strings.TrimSpace(line)
+	dash := dialogueDash.FindString(line)
+	if dash == line || strings.HasPrefix(line, "--") {
+		dash = ""
+	}
+	rest := line[len(dash):]
+
+	// Lines of music go as a whole
+	if strings.HasPrefix(rest, "♪") || strings.HasPrefix(rest, "♫") {
+		return ""
+	}
+
+	// Speaker labels and annotations in parentheses open the line
+	labelled := false
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		if m := soundAnnotation.FindString(rest); m != "" {
+			rest = rest[len(m):]
+			continue
+		}
+		if m := speakerLabel.FindStringSubmatch(rest); !labelled && m != nil && isSpeaker(m[1]) {
+			rest, labelled = rest[len(m[0]):], true
+			continue
+		}
+		break
+	}
+	rest = inlineMusic.ReplaceAllString(rest, "")
+	rest = strings.TrimSpace(inlineSound.ReplaceAllString(rest, ""))
+	if rest == "" {
+		return ""
+	}
+	return dash + rest
+}
@@ -0,0 +1,53 @@
+package srt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStripSDHText(t *testing.T) {
+	tests := map[string]string{
+		"[SUSPENSEFUL MUSIC]":                     "",
+		"♪ Happy birthday to you ♪":               "",
+		"♪ La la la":                              "",
+		"JOHN: We have to go.":                    "We have to go.",
+		"(whispering) Who's there?":               "Who's there?",
+		"Hello [laughs] friend.":                  "Hello friend.",
+		"She said ♪ la la ♪ and left.":            "She said and left.",
+		"- [laughs]\n- Hello.":                    "Hello.",
+		"- JOHN: Run!\n- MARY: Why?":              "- Run!\n- Why?",
+		"- Hi.":                                   "- Hi.",
+		"I (barely) made it\nat 10:30.":           "I (barely) made it\nat 10:30.",
+		"[door slams]\nNURSE 2: Doctor!\n[gasps]": "Doctor!",
+	}
+	for content, want := range tests {
+		if got := stripSDHText(content); got != want {
+			t.Errorf("stripSDHText(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
+
+func TestStripSDH(t *testing.T) {
+	subtitles := []Subtitle{
+		{1, 1 * time.Second, 2 * time.Second, "[SUSPENSEFUL MUSIC]"},
+		{2, 3 * time.Second, 4 * time.Second, "JOHN: Who's there?"},
+		{3, 4200 * time.Millisecond, 5 * time.Second, "[knocking]\nWho's there?"},
+		{4, 6 * time.Second, 7 * time.Second, "♪ ♪"},
+		{5, 8 * time.Second, 9 * time.Second, "It's me."},
+	}
+	got, removed := StripSDH(subtitles)
+	want := []Subtitle{
+		{1, 3 * time.Second, 5 * time.Second, "Who's there?"},
+		{2, 8 * time.Second, 9 * time.Second, "It's me."},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StripSDH() = %+v, want %+v", got, want)
+	}
+	if removed != 3 {
+		t.Errorf("StripSDH() removed %d cues, want 3", removed)
+	}
+	if subtitles[1].Content != "JOHN: Who's there?" {
+		t.Error("StripSDH() changed its input")
+	}
+}
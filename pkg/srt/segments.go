@@ -0,0 +1,272 @@
+package srt
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// SegmentKind tells what a segment of a cue holds
+type SegmentKind string
+
+const (
+	SegmentSpeech SegmentKind = "speech" // Spoken text
+	SegmentSound  SegmentKind = "sound"  // A sound description, e.g. [door slams]
+	SegmentMusic  SegmentKind = "music"  // Lyrics or music between notes, e.g. ♪ lyrics ♪
+)
+
+// Segment is one part of a cue translated on its own: a dialogue line, or an
+// annotation for the hearing impaired. The layout around the text (dialogue
+// dash, brackets, notes, line breaks) is kept out of the payload and restored
+// by JoinSegments.
+type Segment struct {
+	Kind    SegmentKind `json:"kind"`
+	Speaker string      `json:"speaker,omitempty"` // Speaker label such as JOHN in "JOHN: Hello."
+	Text    string      `json:"text"`
+
+	newline bool   // The segment starts a new line
+	breaks  []int  // Offsets in Text of the spaces that were line breaks
+	dash    string // Dialogue dash opening the line, e.g. "- "
+	space   string // Spaces before the segment on its line
+	colon   string // What follows the speaker label, e.g. ": "
+	open    string // Bracket or note before the text
+	close   string // Bracket or note after the text
+}
+
+var (
+	// dialogueDash opens a line spoken by one of several speakers
+	dialogueDash = regexp.MustCompile(`^[-‐–—]+[ \t]*`)
+	// speakerLabel is a speaker name in capitals followed by a colon and a
+	// space, which tells it from a time such as 10:30
+	speakerLabel = regexp.MustCompile(`^(\p{Lu}[\p{Lu}\p{N}'’. &\-]*):(?:[ \t]+|$)`)
+	// soundAnnotation is a sound description in brackets, or in parentheses
+	// at the start of a line
+	soundAnnotation = regexp.MustCompile(`^(?:\[([^\]]*)\]|\(([^)]*)\))`)
+	// musicAnnotation is text after a music note, up to the closing note
+	musicAnnotation = regexp.MustCompile(`^([♪♫]+[ \t]*)([^♪♫]*?)([ \t]*[♪♫]+|$)`)
+)
+
+// SplitSegments splits the text of a cue with dialogue dashes, speaker labels
+// or annotations for the hearing impaired into segments. Lines that continue
+// the previous line's speech are joined to it, and JoinSegments breaks them
+// again. A cue with none of them returns nil and is translated as a whole.
+func SplitSegments(content string) []Segment {
+	var segments []Segment
+	structured := false
+	for n, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		first := len(segments)
+		next := Segment{newline: n > 0 && len(segments) > 0}
+		rest := line
+		if dash := dialogueDash.FindString(rest); dash != "" && dash != rest && !strings.HasPrefix(rest, "--") {
+			next.dash, rest = dash, rest[len(dash):]
+			structured = true
+		}
+
+		opening := true // Speaker labels and annotations in parentheses come before the speech of a line
+		for rest != "" {
+			trimmed := strings.TrimLeftFunc(rest, unicode.IsSpace)
+			next.space, rest = next.space+rest[:len(rest)-len(trimmed)], trimmed
+
+			if m := speakerLabel.FindStringSubmatch(rest); opening && next.Speaker == "" && m != nil && isSpeaker(m[1]) {
+				next.Speaker, next.colon = strings.TrimSpace(m[1]), m[0][len(strings.TrimSpace(m[1])):]
+				rest = rest[len(m[0]):]
+				structured = true
+				continue
+			}
+
+			var piece string
+			switch m := soundAnnotation.FindStringSubmatch(rest); {
+			case m != nil && (strings.HasPrefix(rest, "[") || opening):
+				piece = m[0]
+				next.Kind, next.Text, next.open, next.close = SegmentSound, m[1]+m[2], piece[:1], piece[len(piece)-1:]
+			case strings.HasPrefix(rest, "♪") || strings.HasPrefix(rest, "♫"):
+				m := musicAnnotation.FindStringSubmatch(rest)
+				piece = m[0]
+				next.Kind, next.Text, next.open, next.close = SegmentMusic, m[2], m[1], m[3]
+			default:
+				end := strings.IndexAny(rest, "[♪♫")
+				if end <= 0 {
+					end = len(rest)
+				}
+				piece = rest[:end]
+				text := strings.TrimRightFunc(piece, unicode.IsSpace)
+				piece = text
+				// A line of plain speech continues the speech of the line before,
+				// or follows a speaker label on a line of its own
+				if prev := len(segments) - 1; next.newline && next.dash == "" && next.Speaker == "" && len(segments) == first && prev >= 0 && segments[prev].Kind == SegmentSpeech {
+					if segments[prev].Text == "" {
+						segments[prev].colon += "\n"
+						segments[prev].Text = text
+					} else {
+						segments[prev].breaks = append(segments[prev].breaks, len(segments[prev].Text))
+						segments[prev].Text += " " + text
+					}
+					rest = rest[len(piece):]
+					opening, next = false, Segment{}
+					continue
+				}
+				next.Kind, next.Text = SegmentSpeech, text
+				opening = false
+			}
+			if next.Kind != SegmentSpeech {
+				structured = true
+			}
+			segments = append(segments, next)
+			rest = rest[len(piece):]
+			next = Segment{}
+		}
+		if next.dash != "" || next.Speaker != "" {
+			// A dash or label with nothing after it is kept as an empty line of speech
+			next.Kind = SegmentSpeech
+			segments = append(segments, next)
+		}
+	}
+	if !structured {
+		return nil
+	}
+	return segments
+}
+
+// isSpeaker tells whether a label in capitals is a speaker name, which has at
+// least two letters and is not a time or a number
+func isSpeaker(label string) bool {
+	letters := 0
+	for _, r := range label {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters >= 2
+}
+
+// DropAnnotationCues removes the cues that hold nothing but annotations for
+// the hearing impaired, such as [door slams] or ♪ ♪, and renumbers the rest.
+// It returns how many cues were removed.
+func DropAnnotationCues(subtitles []Subtitle) ([]Subtitle, int) {
+	kept := make([]Subtitle, 0, len(subtitles))
+	for _, subtitle := range subtitles {
+		if segments := SplitSegments(subtitle.Content); segments != nil && !hasSpeech(DropAnnotations(segments)) {
+			continue
+		}
+		kept = append(kept, subtitle)
+	}
+	if len(kept) == len(subtitles) {
+		return subtitles, 0
+	}
+	for i := range kept {
+		kept[i].Index = i + 1
+	}
+	return kept, len(subtitles) - len(kept)
+}
+
+// hasSpeech tells whether any segment has text
+func hasSpeech(segments []Segment) bool {
+	for _, segment := range segments {
+		if strings.TrimSpace(segment.Text) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// DropAnnotations removes the sound and music segments, keeping the dialogue
+// dash and speaker of a removed segment for the speech after it on its line
+func DropAnnotations(segments []Segment) []Segment {
+	kept := make([]Segment, 0, len(segments))
+	var carried *Segment // Removed segment whose line has not been written yet
+	for _, segment := range segments {
+		if segment.newline {
+			carried = nil
+		}
+		if segment.Kind != SegmentSpeech {
+			if carried == nil {
+				removed := segment
+				carried = &removed
+			}
+			continue
+		}
+		if carried != nil {
+			segment.newline, segment.dash, segment.space = carried.newline, carried.dash, carried.space
+			if segment.Speaker == "" {
+				segment.Speaker, segment.colon = carried.Speaker, carried.colon
+			}
+			carried = nil
+		}
+		kept = append(kept, segment)
+	}
+	if len(kept) > 0 {
+		kept[0].newline = false
+	}
+	return kept
+}
+
+// JoinSegments writes segments back as the text of a cue, in the layout of the
+// source segments they were split from. translated holds the new text and
+// speaker of each source segment; nil writes the source segments as they are.
+func JoinSegments(source []Segment, translated []Segment) string {
+	var b strings.Builder
+	for i, segment := range source {
+		text := segment.Text
+		if i < len(translated) {
+			text = strings.TrimSpace(translated[i].Text)
+			if speaker := strings.TrimSpace(translated[i].Speaker); segment.Speaker != "" && speaker != "" {
+				segment.Speaker = speaker
+			}
+		}
+		if segment.newline && b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(segment.dash + segment.space)
+		if segment.Speaker != "" {
+			b.WriteString(segment.Speaker + segment.colon)
+		}
+		b.WriteString(segment.open + breakLines(text, segment.Text, segment.breaks) + segment.close)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// breakLines writes text on as many lines as the source text it replaces had.
+// The source text gets its line breaks back where they were; other text is
+// broken at the spaces that split it most evenly.
+func breakLines(text string, source string, breaks []int) string {
+	if len(breaks) == 0 {
+		return text
+	}
+	runes := []rune(text)
+	if text == source {
+		bytes := []byte(text)
+		for _, offset := range breaks {
+			bytes[offset] = '\n'
+		}
+		return string(bytes)
+	}
+	lines := len(breaks) + 1
+	from := 0
+	for n := 1; n < lines; n++ {
+		target := n * len(runes) / lines
+		best := -1
+		for i := from; i < len(runes); i++ {
+			if runes[i] == ' ' && (best < 0 || abs(i-target) < abs(best-target)) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		runes[best] = '\n'
+		from = best + 1
+	}
+	return string(runes)
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
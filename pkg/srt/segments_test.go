@@ -0,0 +1,145 @@
+package srt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitSegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Segment // Kind, Speaker and Text only
+		joined  string    // JoinSegments without translations, when it differs from content
+		dropped string    // JoinSegments after DropAnnotations
+	}{
+		{
+			name:    "plain text",
+			content: "I told you,\nI was working late.",
+		},
+		{
+			name:    "dialogue dashes",
+			content: "- Hi.\n- Hello, how are you?",
+			want:    []Segment{{Kind: SegmentSpeech, Text: "Hi."}, {Kind: SegmentSpeech, Text: "Hello, how are you?"}},
+			dropped: "- Hi.\n- Hello, how are you?",
+		},
+		{
+			name:    "continued dialogue line",
+			content: "-Where were you\nlast night?\n-At work.",
+			want:    []Segment{{Kind: SegmentSpeech, Text: "Where were you last night?"}, {Kind: SegmentSpeech, Text: "At work."}},
+			dropped: "-Where were you\nlast night?\n-At work.",
+		},
+		{
+			name:    "speaker label on its own line",
+			content: "NARRATOR:\nLong ago,\nin a land far away...",
+			want:    []Segment{{Kind: SegmentSpeech, Speaker: "NARRATOR", Text: "Long ago, in a land far away..."}},
+			dropped: "NARRATOR:\nLong ago,\nin a land far away...",
+		},
+		{
+			name:    "speaker labels",
+			content: "JOHN: We have to go.\nMARY JANE: Now?",
+			want:    []Segment{{Kind: SegmentSpeech, Speaker: "JOHN", Text: "We have to go."}, {Kind: SegmentSpeech, Speaker: "MARY JANE", Text: "Now?"}},
+			dropped: "JOHN: We have to go.\nMARY JANE: Now?",
+		},
+		{
+			name:    "sound descriptions",
+			content: "[door slams]\n(whispering) Who's there?",
+			want:    []Segment{{Kind: SegmentSound, Text: "door slams"}, {Kind: SegmentSound, Text: "whispering"}, {Kind: SegmentSpeech, Text: "Who's there?"}},
+			dropped: "Who's there?",
+		},
+		{
+			name:    "everything together",
+			content: "- [laughs] BOB: Sure.\n- Really? [sighs]",
+			want:    []Segment{{Kind: SegmentSound, Text: "laughs"}, {Kind: SegmentSpeech, Speaker: "BOB", Text: "Sure."}, {Kind: SegmentSpeech, Text: "Really?"}, {Kind: SegmentSound, Text: "sighs"}},
+			dropped: "- BOB: Sure.\n- Really?",
+		},
+		{
+			name:    "lyrics",
+			content: "♪ Happy birthday to you ♪",
+			want:    []Segment{{Kind: SegmentMusic, Text: "Happy birthday to you"}},
+			dropped: "",
+		},
+		{
+			name:    "parentheses inside speech are text",
+			content: "- I (barely) made it.\n- Good.",
+			want:    []Segment{{Kind: SegmentSpeech, Text: "I (barely) made it."}, {Kind: SegmentSpeech, Text: "Good."}},
+			dropped: "- I (barely) made it.\n- Good.",
+		},
+		{
+			name:    "times and ranges are not labels or dashes",
+			content: "AT 10:30 we leave.\n--and then nothing.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments := SplitSegments(tt.content)
+			var got []Segment
+			for _, segment := range segments {
+				got = append(got, Segment{Kind: segment.Kind, Speaker: segment.Speaker, Text: segment.Text})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("SplitSegments() = %+v, want %+v", got, tt.want)
+			}
+			if segments == nil {
+				return
+			}
+
+			joined := tt.joined
+			if joined == "" {
+				joined = tt.content
+			}
+			if got := JoinSegments(segments, nil); got != joined {
+				t.Errorf("JoinSegments() = %q, want %q", got, joined)
+			}
+			if got := JoinSegments(DropAnnotations(segments), nil); got != tt.dropped {
+				t.Errorf("JoinSegments(DropAnnotations()) = %q, want %q", got, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestJoinSegments_translated(t *testing.T) {
+	source := SplitSegments("- [laughs] BOB: Sure.\n- ♪ La la la ♪")
+	translated := []Segment{
+		{Kind: SegmentSound, Text: "rit"},
+		{Kind: SegmentSpeech, Speaker: "BOB", Text: " Bien sûr. "},
+		{Kind: SegmentMusic, Text: "Tra la la"},
+	}
+	want := "- [rit] BOB: Bien sûr.\n- ♪ Tra la la ♪"
+	if got := JoinSegments(source, translated); got != want {
+		t.Errorf("JoinSegments() = %q, want %q", got, want)
+	}
+	if got := JoinSegments(source, translated); strings.Contains(got, "    ") {
+		t.Errorf("JoinSegments() = %q, lines were joined with spaces", got)
+	}
+}
+
+func TestJoinSegments_lineBreaks(t *testing.T) {
+	source := SplitSegments("- Where are you\ngoing?\n- Home.")
+	translated := []Segment{
+		{Kind: SegmentSpeech, Text: "Où est-ce que tu vas ?"},
+		{Kind: SegmentSpeech, Text: "À la maison."},
+	}
+	want := "- Où est-ce\nque tu vas ?\n- À la maison."
+	if got := JoinSegments(source, translated); got != want {
+		t.Errorf("JoinSegments() = %q, want %q", got, want)
+	}
+}
+
+func TestDropAnnotationCues(t *testing.T) {
+	subtitles := []Subtitle{
+		{Index: 1, Content: "[door slams]"},
+		{Index: 2, Content: "- [laughs]\n- Who's there?"},
+		{Index: 3, Content: "♪ ♪"},
+		{Index: 4, Content: "Plain text."},
+	}
+	kept, removed := DropAnnotationCues(subtitles)
+	if removed != 2 || len(kept) != 2 {
+		t.Fatalf("DropAnnotationCues() kept %+v, removed %d, want 2 and 2", kept, removed)
+	}
+	if kept[0].Index != 1 || kept[0].Content != subtitles[1].Content || kept[1].Index != 2 || kept[1].Content != "Plain text." {
+		t.Errorf("DropAnnotationCues() = %+v", kept)
+	}
+}
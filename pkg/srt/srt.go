@@ -2,7 +2,6 @@ package srt
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -18,55 +17,18 @@ type Subtitle struct {
 
 // SubtitleObject represents the object structure used for translation
 type SubtitleObject struct {
-	Index     int     `json:"index"`
-	Content   string  `json:"content"`
-	Guard     string  `json:"guard,omitempty"`
-	TimeStart *string `json:"time_start,omitempty"`
-	TimeEnd   *string `json:"time_end,omitempty"`
+	Index     int       `json:"index"`
+	Content   string    `json:"content"`
+	Guard     string    `json:"guard,omitempty"`
+	Segments  []Segment `json:"segments,omitempty"` // Set instead of Content for cues split with SplitSegments
+	TimeStart *string   `json:"time_start,omitempty"`
+	TimeEnd   *string   `json:"time_end,omitempty"`
 }
 
-// ParseSRT parses SRT content from a string
+// ParseSRT parses SRT content from a string, repairing common defects and
+// skipping cues it cannot read; use Parse to get the problems it found
 func ParseSRT(content string) ([]Subtitle, error) {
-	var subtitles []Subtitle
-
-	content = strings.TrimPrefix(content, "\ufeff")
-
-	// Split by empty lines to get individual subtitle blocks
-	blocks := regexp.MustCompile(`\r?\n\s*\r?\n`).Split(strings.TrimSpace(content), -1)
-	for _, block := range blocks {
-		if strings.TrimSpace(block) == "" {
-			continue
-		}
-
-		lines := strings.Split(strings.TrimSpace(block), "\n")
-		if len(lines) < 3 {
-			continue
-		}
-
-		// Parse index
-		index, errIndex := strconv.Atoi(strings.TrimSpace(lines[0]))
-		if errIndex != nil {
-			continue
-		}
-
-		// Parse timing
-		timingLine := strings.TrimSpace(lines[1])
-		start, end, errTiming := parseTimestamp(timingLine)
-		if errTiming != nil {
-			continue
-		}
-
-		// Parse content (everything after the second line)
-		content = strings.Join(lines[2:], "\n")
-
-		subtitles = append(subtitles, Subtitle{
-			Index:   index,
-			Start:   start,
-			End:     end,
-			Content: strings.TrimSpace(content),
-		})
-	}
-
+	subtitles, _ := parse(content)
 	return subtitles, nil
 }
 
@@ -90,6 +52,11 @@ func parseTimestamp(line string) (time.Duration, time.Duration, error) {
 	return start, end, nil
 }
 
+// ParseDuration parses an SRT duration such as "00:00:00,000" or "00:00:00"
+func ParseDuration(s string) (time.Duration, error) {
+	return parseDuration(strings.TrimSpace(s))
+}
+
 // parseDuration parses SRT duration format "00:00:00,000"
 func parseDuration(s string) (time.Duration, error) {
 	// Replace comma with dot for milliseconds
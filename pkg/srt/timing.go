@@ -0,0 +1,285 @@
+package srt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCueDuration is the duration given to cues whose end is before their start
+const DefaultCueDuration = 2 * time.Second
+
+// Anchor maps a time in the subtitles to the time it should appear at
+type Anchor struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// Shift moves every cue by offset in place; times before zero become zero
+func Shift(subtitles []Subtitle, offset time.Duration) {
+	for i := range subtitles {
+		subtitles[i].Start = max(0, subtitles[i].Start+offset)
+		subtitles[i].End = max(0, subtitles[i].End+offset)
+	}
+}
+
+// Scale multiplies every time by factor in place, rounding to milliseconds
+func Scale(subtitles []Subtitle, factor float64) {
+	for i := range subtitles {
+		subtitles[i].Start = scaleDuration(subtitles[i].Start, factor)
+		subtitles[i].End = scaleDuration(subtitles[i].End, factor)
+	}
+}
+
+// Sync retimes the subtitles in place so that each anchor's From time lands
+// on its To time. One anchor shifts the subtitles; two anchors also stretch
+// them linearly, which corrects a constant offset and drift together.
+func Sync(subtitles []Subtitle, anchors ...Anchor) error {
+	switch len(anchors) {
+	case 1:
+		Shift(subtitles, anchors[0].To-anchors[0].From)
+		return nil
+	case 2:
+	default:
+		return fmt.Errorf("sync needs one or two anchors, got %d", len(anchors))
+	}
+
+	first, second := anchors[0], anchors[1]
+	if first.From == second.From || first.To == second.To {
+		return fmt.Errorf("sync anchors must be at different times")
+	}
+	factor := float64(second.To-first.To) / float64(second.From-first.From)
+	if factor <= 0 {
+		return fmt.Errorf("sync anchors must be in the same order before and after")
+	}
+	for i := range subtitles {
+		subtitles[i].Start = max(0, first.To+scaleDuration(subtitles[i].Start-first.From, factor))
+		subtitles[i].End = max(0, first.To+scaleDuration(subtitles[i].End-first.From, factor))
+	}
+	return nil
+}
+
+// ConvertFrameRate retimes subtitles made for a video at from frames per
+// second to the same video played at to frames per second (23.976 → 25)
+func ConvertFrameRate(subtitles []Subtitle, from, to float64) error {
+	if from <= 0 || to <= 0 {
+		return fmt.Errorf("frame rates must be positive")
+	}
+	Scale(subtitles, from/to)
+	return nil
+}
+
+// FixOverlaps repairs cues in place: a cue ending before it starts gets
+// DefaultCueDuration, and a cue running into the next one ends where the next
+// starts. It returns the number of cues changed.
+func FixOverlaps(subtitles []Subtitle) int {
+	changed := 0
+	for i := range subtitles {
+		fixed := false
+		if subtitles[i].End < subtitles[i].Start {
+			subtitles[i].End = subtitles[i].Start + DefaultCueDuration
+			fixed = true
+		}
+		if i+1 < len(subtitles) {
+			next := subtitles[i+1].Start
+			if next >= subtitles[i].Start && subtitles[i].End > next {
+				subtitles[i].End = next
+				fixed = true
+			}
+		}
+		if fixed {
+			changed++
+		}
+	}
+	return changed
+}
+
+// EnforceMinGap shortens cues in place so at least gap separates each cue
+// from the next, never making a cue end before it starts. It returns the
+// number of cues changed.
+func EnforceMinGap(subtitles []Subtitle, gap time.Duration) int {
+	changed := 0
+	for i := 0; i+1 < len(subtitles); i++ {
+		next := subtitles[i+1].Start
+		if next < subtitles[i].Start || subtitles[i].End <= next-gap {
+			continue
+		}
+		end := max(subtitles[i].Start, next-gap)
+		if end != subtitles[i].End {
+			subtitles[i].End = end
+			changed++
+		}
+	}
+	return changed
+}
+
+// ParseOffset parses a signed offset given as a Go duration ("-1.5s", "250ms")
+// or as an SRT timestamp ("-00:00:01,500")
+func ParseOffset(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if offset, err := time.ParseDuration(s); err == nil {
+		return offset, nil
+	}
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	offset, err := parseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset %q, use a duration such as -1.5s or a timestamp such as 00:00:01,500", s)
+	}
+	return sign * offset, nil
+}
+
+// ParseAnchor parses an anchor written as "00:01:00,000->00:01:02,500"
+func ParseAnchor(s string) (Anchor, error) {
+	from, to, ok := strings.Cut(s, "->")
+	if !ok {
+		return Anchor{}, fmt.Errorf("invalid anchor %q, use OLD->NEW such as 00:01:00,000->00:01:02,500", s)
+	}
+	var anchor Anchor
+	var err error
+	if anchor.From, err = ParseDuration(from); err != nil {
+		return Anchor{}, fmt.Errorf("invalid anchor %q: %w", s, err)
+	}
+	if anchor.To, err = ParseDuration(to); err != nil {
+		return Anchor{}, fmt.Errorf("invalid anchor %q: %w", s, err)
+	}
+	return anchor, nil
+}
+
+// ParseFrameRate parses a frame rate; the rounded NTSC rates 23.976, 29.97,
+// 47.952, 59.94 and 119.88 stand for their exact values (24000/1001 ...)
+func ParseFrameRate(s string) (float64, error) {
+	rate, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || rate <= 0 {
+		return 0, fmt.Errorf("invalid frame rate %q", s)
+	}
+	for _, base := range []float64{24, 30, 48, 60, 120} {
+		if exact := base * 1000 / 1001; math.Abs(rate-exact) < 0.005 {
+			return exact, nil
+		}
+	}
+	return rate, nil
+}
+
+// TimingStep is a timing transform of the translation pipeline, parsed from a
+// step such as "shift=-1.5s" or "fix-overlaps"
+type TimingStep struct {
+	Spec  string
+	apply func([]Subtitle) error
+}
+
+// Apply runs the step on the subtitles in place
+func (s TimingStep) Apply(subtitles []Subtitle) error {
+	if err := s.apply(subtitles); err != nil {
+		return fmt.Errorf("timing step %s: %w", s.Spec, err)
+	}
+	return nil
+}
+
+// ParseTimingStep parses one pipeline step:
+//
+//	shift=<offset>                     constant offset, e.g. shift=-1.5s
+//	sync=<old>-><new>[;<old>-><new>]   offset, or offset and drift from two anchors
+//	fps=<from>:<to>                    frame rate conversion, e.g. fps=23.976:25
+//	fix-overlaps                       repair overlaps and negative durations
+//	min-gap=<duration>                 minimum gap between cues, e.g. min-gap=84ms
+func ParseTimingStep(spec string) (TimingStep, error) {
+	spec = strings.TrimSpace(spec)
+	name, arg, _ := strings.Cut(spec, "=")
+	step := TimingStep{Spec: spec}
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "shift":
+		offset, err := ParseOffset(arg)
+		if err != nil {
+			return step, err
+		}
+		step.apply = func(subtitles []Subtitle) error {
+			Shift(subtitles, offset)
+			return nil
+		}
+	case "sync":
+		var anchors []Anchor
+		for _, part := range strings.Split(arg, ";") {
+			anchor, err := ParseAnchor(part)
+			if err != nil {
+				return step, err
+			}
+			anchors = append(anchors, anchor)
+		}
+		if err := Sync(nil, anchors...); err != nil {
+			return step, err
+		}
+		step.apply = func(subtitles []Subtitle) error {
+			return Sync(subtitles, anchors...)
+		}
+	case "fps":
+		fromText, toText, ok := strings.Cut(arg, ":")
+		if !ok {
+			return step, fmt.Errorf("invalid frame rates %q, use FROM:TO such as 23.976:25", arg)
+		}
+		from, err := ParseFrameRate(fromText)
+		if err != nil {
+			return step, err
+		}
+		to, err := ParseFrameRate(toText)
+		if err != nil {
+			return step, err
+		}
+		step.apply = func(subtitles []Subtitle) error {
+			return ConvertFrameRate(subtitles, from, to)
+		}
+	case "fix-overlaps":
+		step.apply = func(subtitles []Subtitle) error {
+			FixOverlaps(subtitles)
+			return nil
+		}
+	case "min-gap":
+		gap, err := time.ParseDuration(strings.TrimSpace(arg))
+		if err != nil || gap < 0 {
+			return step, fmt.Errorf("invalid minimum gap %q, use a duration such as 84ms", arg)
+		}
+		step.apply = func(subtitles []Subtitle) error {
+			EnforceMinGap(subtitles, gap)
+			return nil
+		}
+	default:
+		return step, fmt.Errorf("unknown timing step %q, use shift, sync, fps, fix-overlaps or min-gap", spec)
+	}
+	return step, nil
+}
+
+// ParseTimingSteps parses a list of pipeline steps
+func ParseTimingSteps(specs []string) ([]TimingStep, error) {
+	steps := make([]TimingStep, 0, len(specs))
+	for _, spec := range specs {
+		step, err := ParseTimingStep(spec)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// ApplyTiming runs the steps in order on the subtitles in place
+func ApplyTiming(subtitles []Subtitle, steps []TimingStep) error {
+	for _, step := range steps {
+		if err := step.Apply(subtitles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scaleDuration multiplies a duration by factor, rounded to milliseconds
+func scaleDuration(d time.Duration, factor float64) time.Duration {
+	return time.Duration(math.Round(float64(d)*factor/float64(time.Millisecond))) * time.Millisecond
+}
@@ -0,0 +1,104 @@
+package srt
+
+import (
+	"testing"
+	"time"
+)
+
+// cues builds subtitles from start/end pairs in milliseconds
+func cues(times ...int) []Subtitle {
+	subtitles := make([]Subtitle, 0, len(times)/2)
+	for i := 0; i+1 < len(times); i += 2 {
+		subtitles = append(subtitles, Subtitle{
+			Index: i/2 + 1,
+			Start: time.Duration(times[i]) * time.Millisecond,
+			End:   time.Duration(times[i+1]) * time.Millisecond,
+		})
+	}
+	return subtitles
+}
+
+func assertTimes(t *testing.T, name string, got []Subtitle, want []Subtitle) {
+	t.Helper()
+	for i := range want {
+		if got[i].Start != want[i].Start || got[i].End != want[i].End {
+			t.Errorf("%s: cue %d = %v --> %v, want %v --> %v", name, i+1, got[i].Start, got[i].End, want[i].Start, want[i].End)
+		}
+	}
+}
+
+func TestTimingTransforms(t *testing.T) {
+	subtitles := cues(500, 2000, 3000, 4000)
+	Shift(subtitles, -time.Second)
+	assertTimes(t, "Shift", subtitles, cues(0, 1000, 2000, 3000))
+
+	subtitles = cues(1000, 2000, 10000, 11000)
+	if err := Sync(subtitles, Anchor{From: time.Second, To: 2 * time.Second}, Anchor{From: 10 * time.Second, To: 20 * time.Second}); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	assertTimes(t, "Sync", subtitles, cues(2000, 4000, 20000, 22000))
+
+	if err := Sync(subtitles, Anchor{From: time.Second, To: 2 * time.Second}, Anchor{From: 10 * time.Second, To: time.Second}); err == nil {
+		t.Error("Sync() accepted anchors in reverse order")
+	}
+
+	subtitles = cues(0, 1001, 60060, 61000)
+	rate, _ := ParseFrameRate("23.976")
+	if err := ConvertFrameRate(subtitles, rate, 25); err != nil {
+		t.Fatalf("ConvertFrameRate() failed: %v", err)
+	}
+	assertTimes(t, "ConvertFrameRate", subtitles, cues(0, 960, 57600, 58501))
+
+	subtitles = cues(0, 3000, 2000, 1000, 5000, 6000)
+	if changed := FixOverlaps(subtitles); changed != 2 {
+		t.Errorf("FixOverlaps() changed %d cues, want 2", changed)
+	}
+	assertTimes(t, "FixOverlaps", subtitles, cues(0, 2000, 2000, 4000, 5000, 6000))
+
+	subtitles = cues(0, 1000, 1050, 2000, 2030, 3000, 5000, 6000)
+	if changed := EnforceMinGap(subtitles, 84*time.Millisecond); changed != 2 {
+		t.Errorf("EnforceMinGap() changed %d cues, want 2", changed)
+	}
+	assertTimes(t, "EnforceMinGap", subtitles, cues(0, 966, 1050, 1946, 2030, 3000, 5000, 6000))
+}
+
+func TestParseTimingStep(t *testing.T) {
+	subtitles := cues(10000, 12000, 20000, 21000)
+	steps, err := ParseTimingSteps([]string{
+		"shift=-00:00:02,000",
+		"sync=00:00:08,000->00:00:09,000",
+		"fix-overlaps",
+		"min-gap=100ms",
+	})
+	if err != nil {
+		t.Fatalf("ParseTimingSteps() failed: %v", err)
+	}
+	if err = ApplyTiming(subtitles, steps); err != nil {
+		t.Fatalf("ApplyTiming() failed: %v", err)
+	}
+	assertTimes(t, "ApplyTiming", subtitles, cues(9000, 11000, 19000, 20000))
+
+	for _, spec := range []string{"", "shift", "shift=soon", "sync=00:00:01,000", "sync=00:00:01,000->00:00:02,000;00:00:03,000->00:00:04,000;00:00:05,000->00:00:06,000", "fps=25", "fps=0:25", "min-gap=-1s", "stretch=2"} {
+		if _, err := ParseTimingStep(spec); err == nil {
+			t.Errorf("ParseTimingStep(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"1.5s", 1500 * time.Millisecond},
+		{"-250ms", -250 * time.Millisecond},
+		{"+00:00:02,500", 2500 * time.Millisecond},
+		{"-01:00:00,000", -time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := ParseOffset(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseOffset(%q) = %v, %v, want %v", tt.input, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Package translate is the library API of the subtitle translator. It works on
+// subtitles in memory or on readers and writers, never touches the terminal
+// and can be used with the built-in Gemini and OpenAI providers or any custom
+// Provider implementation.
+package translate
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/luispater/gemini-srt-translator-go/internal/providers"
+	"github.com/luispater/gemini-srt-translator-go/internal/translator"
+	"github.com/luispater/gemini-srt-translator-go/pkg/config"
+	"github.com/luispater/gemini-srt-translator-go/pkg/errors"
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+)
+
+// Provider is the interface a translation backend implements
+type Provider = providers.TranslationProvider
+
+// ContextMessage is a conversation message passed to the provider as context
+type ContextMessage = providers.ContextMessage
+
+// RequestConfig holds the settings of a single TranslateBatch request
+type RequestConfig = providers.TranslationConfig
+
+// Response is the result of a single TranslateBatch request
+type Response = providers.TranslationResponse
+
+// Usage holds token counts reported by the provider
+type Usage = providers.Usage
+
+// ProgressUpdater receives streaming state changes from a provider
+type ProgressUpdater = providers.ProgressUpdater
+
+// ProgressFunc receives the number of translated and total subtitles after every batch
+type ProgressFunc func(done int, total int)
+
+// Options configures a translation. Only TargetLanguage is required when
+// Provider is set; otherwise APIKeys are needed for the named provider.
+type Options struct {
+	TargetLanguage string
+	Description    string // Additional instructions for the model
+
+	// Provider is used as is when set. Otherwise a built-in provider is
+	// created from ProviderName ("gemini" or "openai"), APIKeys and BaseURL.
+	Provider     Provider
+	ProviderName string
+	APIKeys      []string
+	BaseURL      string
+
+	Model         string // Defaults to the provider's default model
+	BatchSize     int    // Defaults to 300
+	RetryCount    int    // Defaults to 3, use a negative value to disable retries
+	Streaming     bool   // Stream responses, off unless set
+	Thinking      bool   // Enable model thinking, off unless set
+	ThinkingLevel string // minimal, low, medium or high, defaults to high
+	Temperature   *float32
+	TopP          *float32
+	TopK          *float32
+	FreeQuota     bool // Pace requests to pro models for the Gemini free tier
+
+	// Context seeds the conversation, e.g. with the last batch of the previous episode
+	Context []ContextMessage
+
+	Progress ProgressFunc
+}
+
+// Result is the outcome of a translation
+type Result struct {
+	Subtitles []srt.Subtitle
+	Context   []ContextMessage // Context of the last batch, to continue with the next file
+	Usage     Usage
+}
+
+// Translate translates subtitles and returns the translated copy. Timing and
+// order are preserved, only the content is replaced.
+func Translate(ctx context.Context, subtitles []srt.Subtitle, opts Options) ([]srt.Subtitle, error) {
+	result, err := TranslateWithResult(ctx, subtitles, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Subtitles, nil
+}
+
+// TranslateWithResult is like Translate but also returns the conversation
+// context and the token usage
+func TranslateWithResult(ctx context.Context, subtitles []srt.Subtitle, opts Options) (*Result, error) {
+	cfg, err := opts.config()
+	if err != nil {
+		return nil, err
+	}
+
+	provider := opts.Provider
+	if provider == nil {
+		factory := &providers.ProviderFactory{}
+		if provider, err = factory.NewProvider(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	t := translator.NewTranslatorWithProvider(cfg, provider)
+	if opts.Context != nil {
+		t.SetContext(opts.Context)
+	}
+	if opts.Progress != nil {
+		t.SetProgressFunc(opts.Progress)
+	}
+
+	translated, err := t.TranslateSubtitles(ctx, subtitles)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Subtitles: translated, Context: t.Context(), Usage: t.Usage()}, nil
+}
+
+// TranslateReader reads SRT content from r, translates it and writes the
+// translated SRT content to w
+func TranslateReader(ctx context.Context, r io.Reader, w io.Writer, opts Options) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return errors.NewFileError("failed to read subtitles", err)
+	}
+
+	subtitles, err := srt.ParseSRT(string(data))
+	if err != nil {
+		return errors.NewFileError("failed to parse SRT content", err)
+	}
+
+	translated, err := Translate(ctx, subtitles, opts)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.WriteString(w, srt.ComposeSRT(translated)); err != nil {
+		return errors.NewFileError("failed to write subtitles", err)
+	}
+	return nil
+}
+
+// config converts the options into a translator configuration
+func (o Options) config() (*config.Config, error) {
+	if strings.TrimSpace(o.TargetLanguage) == "" {
+		return nil, errors.NewValidationError("please provide a target language", nil)
+	}
+
+	cfg := config.NewConfig()
+	cfg.TargetLanguage = o.TargetLanguage
+	cfg.Description = o.Description
+	cfg.Streaming = o.Streaming
+	cfg.Thinking = o.Thinking
+	cfg.Temperature = o.Temperature
+	cfg.TopP = o.TopP
+	cfg.TopK = o.TopK
+	cfg.FreeQuota = o.FreeQuota
+	cfg.UseColors = false
+	cfg.QuietMode = true
+	cfg.NonInteractive = true
+
+	if o.ProviderName != "" {
+		cfg.Provider = strings.ToLower(o.ProviderName)
+	}
+	switch cfg.Provider {
+	case "gemini":
+	case "openai":
+		cfg.ModelName = "gpt-4o"
+	default:
+		return nil, errors.NewConfigurationError("provider must be gemini or openai", nil).WithContext("provider", o.ProviderName)
+	}
+
+	// Never pick up keys from the environment implicitly
+	cfg.APIKeys = o.APIKeys
+	cfg.BaseURL = o.BaseURL
+	if o.Provider == nil && len(cfg.APIKeys) == 0 {
+		return nil, errors.NewConfigurationError("please provide API keys or a provider", nil)
+	}
+
+	if o.Model != "" {
+		cfg.ModelName = o.Model
+	}
+	if o.BatchSize > 0 {
+		cfg.BatchSize = o.BatchSize
+	}
+	if o.RetryCount > 0 {
+		cfg.RetryCount = o.RetryCount
+	} else if o.RetryCount < 0 {
+		cfg.RetryCount = 0
+	}
+	if o.ThinkingLevel != "" {
+		cfg.ThinkingLevel = o.ThinkingLevel
+	}
+
+	return cfg, nil
+}
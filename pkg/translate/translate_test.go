@@ -0,0 +1,135 @@
+package translate_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/luispater/gemini-srt-translator-go/pkg/srt"
+	"github.com/luispater/gemini-srt-translator-go/pkg/translate"
+)
+
+const testSRT = "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:03,000 --> 00:00:04,000\nGood\nbye\n\n3\n00:00:05,000 --> 00:00:06,000\nWorld\n"
+
+// upperProvider is a custom provider built only from the public API
+type upperProvider struct {
+	batches int
+}
+
+func (p *upperProvider) GetModels(ctx context.Context) ([]string, error) {
+	return []string{"upper-1"}, nil
+}
+
+func (p *upperProvider) GetTokenLimit(ctx context.Context, modelName string) (int32, error) {
+	return 100000, nil
+}
+
+func (p *upperProvider) CountTokens(ctx context.Context, modelName string, content string) (int32, error) {
+	return int32(len(content)), nil
+}
+
+func (p *upperProvider) TranslateBatch(ctx context.Context, batch []srt.SubtitleObject, previousContext []translate.ContextMessage, config *translate.RequestConfig) (*translate.Response, error) {
+	p.batches++
+	translated := make([]srt.SubtitleObject, len(batch))
+	for i, item := range batch {
+		item.Content = strings.ToUpper(item.Content)
+		translated[i] = item
+	}
+	return &translate.Response{
+		TranslatedBatch: translated,
+		Context:         []translate.ContextMessage{{Role: "model", Content: "last"}},
+		Usage:           translate.Usage{PromptTokens: 10, OutputTokens: 5},
+	}, nil
+}
+
+func (p *upperProvider) GetName() string {
+	return "upper"
+}
+
+func TestTranslate(t *testing.T) {
+	subtitles, err := srt.ParseSRT(testSRT)
+	if err != nil {
+		t.Fatalf("ParseSRT() failed: %v", err)
+	}
+
+	provider := &upperProvider{}
+	var progress [][2]int
+	result, err := translate.TranslateWithResult(context.Background(), subtitles, translate.Options{
+		TargetLanguage: "Upper",
+		Provider:       provider,
+		Model:          "upper-1",
+		BatchSize:      2,
+		Progress: func(done int, total int) {
+			progress = append(progress, [2]int{done, total})
+		},
+	})
+	if err != nil {
+		t.Fatalf("TranslateWithResult() failed: %v", err)
+	}
+
+	if provider.batches != 2 {
+		t.Errorf("Expected 2 batches, got %d", provider.batches)
+	}
+	if got := result.Subtitles[0].Content; got != "HELLO" {
+		t.Errorf("First subtitle = %q, want %q", got, "HELLO")
+	}
+	// Multi-line cues are sent as one unit
+	if got := result.Subtitles[1].Content; got != "GOOD    BYE" {
+		t.Errorf("Second subtitle = %q, want %q", got, "GOOD    BYE")
+	}
+	if result.Subtitles[2].Start != subtitles[2].Start {
+		t.Error("Timing must be preserved")
+	}
+	if subtitles[0].Content != "Hello" {
+		t.Error("Input subtitles must not be modified")
+	}
+	if result.Usage.PromptTokens != 20 || len(result.Context) != 1 {
+		t.Errorf("Unexpected usage %+v or context %+v", result.Usage, result.Context)
+	}
+	wantProgress := [][2]int{{0, 3}, {2, 3}, {3, 3}}
+	if len(progress) != len(wantProgress) {
+		t.Fatalf("Progress = %v, want %v", progress, wantProgress)
+	}
+	for i := range wantProgress {
+		if progress[i] != wantProgress[i] {
+			t.Errorf("Progress = %v, want %v", progress, wantProgress)
+			break
+		}
+	}
+}
+
+func TestTranslateReader(t *testing.T) {
+	var out bytes.Buffer
+	err := translate.TranslateReader(context.Background(), strings.NewReader(testSRT), &out, translate.Options{
+		TargetLanguage: "Upper",
+		Provider:       &upperProvider{},
+		Model:          "upper-1",
+	})
+	if err != nil {
+		t.Fatalf("TranslateReader() failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "00:00:05,000 --> 00:00:06,000\nWORLD") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+}
+
+func TestTranslate_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts translate.Options
+	}{
+		{"missing target language", translate.Options{Provider: &upperProvider{}}},
+		{"missing API keys", translate.Options{TargetLanguage: "French"}},
+		{"unknown provider", translate.Options{TargetLanguage: "French", ProviderName: "acme", APIKeys: []string{"key"}}},
+		{"unknown model", translate.Options{TargetLanguage: "French", Provider: &upperProvider{}, Model: "other"}},
+		{"invalid thinking level", translate.Options{TargetLanguage: "French", Provider: &upperProvider{}, Model: "upper-1", ThinkingLevel: "max"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := translate.Translate(context.Background(), nil, tt.opts); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}